@@ -0,0 +1,76 @@
+package address
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type decodeCommand struct {
+	JSON bool
+}
+
+func (c *decodeCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode <address>",
+		Short: "decode an address into its network, kind and keys",
+		Args:  cobra.ExactArgs(1),
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *decodeCommand) RunE(_ *cobra.Command, args []string) error {
+	addr, err := monero.ParseAddress(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("parse address: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(struct {
+			Network        monero.Network     `json:"network"`
+			Kind           monero.AddressKind `json:"kind"`
+			PublicSpendKey string             `json:"public_spend_key"`
+			PublicViewKey  string             `json:"public_view_key"`
+			PaymentID      string             `json:"payment_id,omitempty"`
+		}{
+			Network:        addr.Network,
+			Kind:           addr.Kind,
+			PublicSpendKey: hex.EncodeToString(addr.PublicSpendKey),
+			PublicViewKey:  hex.EncodeToString(addr.PublicViewKey),
+			PaymentID:      hex.EncodeToString(addr.PaymentID),
+		})
+	}
+
+	c.pretty(addr)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *decodeCommand) pretty(addr *monero.Address) {
+	table := display.NewTable()
+
+	table.AddRow("Network:", addr.Network)
+	table.AddRow("Kind:", addr.Kind)
+	table.AddRow("Public Spend Key:", hex.EncodeToString(addr.PublicSpendKey))
+	table.AddRow("Public View Key:", hex.EncodeToString(addr.PublicViewKey))
+
+	if addr.PaymentID != nil {
+		table.AddRow("Payment ID:", hex.EncodeToString(addr.PaymentID))
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&decodeCommand{}).Cmd())
+}
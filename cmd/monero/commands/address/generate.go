@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -30,7 +29,7 @@ func (c *generateCommand) Cmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&c.networkName, "network", "mainnet",
 		"network that the addresses should be used for "+
-			c.networkOptions())
+			networkOptions())
 
 	return cmd
 }
@@ -41,21 +40,21 @@ func (c *generateCommand) RunE(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("private key: %w", err)
 	}
 
-	network, err := c.network()
+	network, err := parseNetwork(c.networkName)
 	if err != nil {
 		return fmt.Errorf("network: %w", err)
 	}
 
-	c.pretty(monero.NewSeed(privateKey, monero.WithNetwork(network)))
+	prettySeed(monero.NewSeed(privateKey, monero.WithNetwork(network)))
 	return nil
 }
 
-func (c *generateCommand) pretty(seed *monero.Seed) {
-	c.prettyMnemonic(seed)
-	c.prettyKeys(seed)
+func prettySeed(seed *monero.Seed) {
+	prettyMnemonic(seed)
+	prettyKeys(seed)
 }
 
-func (c *generateCommand) prettyKeys(seed *monero.Seed) {
+func prettyKeys(seed *monero.Seed) {
 	table := display.NewTable()
 	defer fmt.Println(table)
 
@@ -70,7 +69,7 @@ func (c *generateCommand) prettyKeys(seed *monero.Seed) {
 		hex.EncodeToString(seed.PublicViewKey()))
 }
 
-func (c *generateCommand) prettyMnemonic(seed *monero.Seed) {
+func prettyMnemonic(seed *monero.Seed) {
 	table := display.NewTable()
 	defer fmt.Println(table)
 
@@ -78,17 +77,17 @@ func (c *generateCommand) prettyMnemonic(seed *monero.Seed) {
 
 	mnemonic := seed.Mnemonic()
 
-	table.AddRow(c.row("Mnemonic:", mnemonic[0:4]...)...)
-	table.AddRow(c.row("", mnemonic[4:8]...)...)
-	table.AddRow(c.row("", mnemonic[8:12]...)...)
-	table.AddRow(c.row("", mnemonic[12:16]...)...)
-	table.AddRow(c.row("", mnemonic[16:20]...)...)
-	table.AddRow(c.row("", mnemonic[20:24]...)...)
-	table.AddRow(c.row("", mnemonic[24])...)
+	table.AddRow(row("Mnemonic:", mnemonic[0:4]...)...)
+	table.AddRow(row("", mnemonic[4:8]...)...)
+	table.AddRow(row("", mnemonic[8:12]...)...)
+	table.AddRow(row("", mnemonic[12:16]...)...)
+	table.AddRow(row("", mnemonic[16:20]...)...)
+	table.AddRow(row("", mnemonic[20:24]...)...)
+	table.AddRow(row("", mnemonic[24])...)
 	table.AddRow("")
 }
 
-func (c *generateCommand) row(key string, values ...string) []interface{} {
+func row(key string, values ...string) []interface{} {
 	res := []interface{}{key}
 	for _, v := range values {
 		res = append(res, v)
@@ -108,37 +107,6 @@ func (c *generateCommand) privateKey() ([]byte, error) {
 	return v, nil
 }
 
-func (c *generateCommand) networkOptions() string {
-	strs := []string{}
-
-	for _, network := range []monero.Network{
-		monero.NetworkMainnet,
-		monero.NetworkTestnet,
-		monero.NetworkStagenet,
-		monero.NetworkFakechain,
-	} {
-		strs = append(strs, string(network))
-	}
-
-	return "(" + strings.Join(strs, ",") + ")"
-}
-
-func (c *generateCommand) network() (monero.Network, error) {
-	switch c.networkName {
-	case string(monero.NetworkMainnet):
-		return monero.NetworkMainnet, nil
-	case string(monero.NetworkTestnet):
-		return monero.NetworkTestnet, nil
-	case string(monero.NetworkStagenet):
-		return monero.NetworkStagenet, nil
-	case string(monero.NetworkFakechain):
-		return monero.NetworkFakechain, nil
-	}
-
-	err := fmt.Errorf("unknown network %s", c.network)
-	return monero.NetworkFakechain, err
-}
-
 func init() {
 	RootCommand.AddCommand((&generateCommand{}).Cmd())
 }
@@ -0,0 +1,90 @@
+package address
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type integratedCommand struct {
+	paymentID string
+}
+
+func (c *integratedCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "integrated <address>",
+		Short: "make an integrated address, or split one",
+		Long: `Makes an integrated address out of a standard one and a (short) payment ID
+(a random one if not specified), or, if given an integrated address, splits
+it into its standard address and payment ID.`,
+		Args: cobra.ExactArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.paymentID, "payment-id", "",
+		"hex-encoded 8-byte payment id to integrate (random if not set)")
+
+	return cmd
+}
+
+func (c *integratedCommand) RunE(_ *cobra.Command, args []string) error {
+	addr, err := monero.ParseAddress(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("parse address: %w", err)
+	}
+
+	if addr.Kind == monero.AddressIntegrated {
+		c.pretty(addr.Standard(), addr)
+		return nil
+	}
+
+	paymentID, err := c.paymentIDBytes()
+	if err != nil {
+		return fmt.Errorf("payment id: %w", err)
+	}
+
+	integrated, err := addr.Integrated(paymentID)
+	if err != nil {
+		return fmt.Errorf("integrated: %w", err)
+	}
+
+	c.pretty(addr, integrated)
+	return nil
+}
+
+func (c *integratedCommand) paymentIDBytes() ([]byte, error) {
+	if c.paymentID != "" {
+		return hex.DecodeString(c.paymentID)
+	}
+
+	v := make([]byte, monero.EncryptedPaymentIDSize)
+
+	_, err := io.ReadFull(rand.Reader, v)
+	if err != nil {
+		return nil, fmt.Errorf("read full: %w", err)
+	}
+
+	return v, nil
+}
+
+// nolint:forbidigo
+func (c *integratedCommand) pretty(standard, integrated *monero.Address) {
+	table := display.NewTable()
+
+	table.AddRow("Standard Address:", standard.String())
+	table.AddRow("Payment ID:", hex.EncodeToString(integrated.PaymentID))
+	table.AddRow("Integrated Address:", integrated.String())
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&integratedCommand{}).Cmd())
+}
@@ -0,0 +1,36 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+var networks = []monero.Network{
+	monero.NetworkMainnet,
+	monero.NetworkTestnet,
+	monero.NetworkStagenet,
+	monero.NetworkFakechain,
+}
+
+func networkOptions() string {
+	strs := []string{}
+
+	for _, network := range networks {
+		strs = append(strs, string(network))
+	}
+
+	return "(" + strings.Join(strs, ",") + ")"
+}
+
+func parseNetwork(name string) (monero.Network, error) {
+	for _, network := range networks {
+		if name == string(network) {
+			return network, nil
+		}
+	}
+
+	err := fmt.Errorf("unknown network %s", name)
+	return monero.NetworkFakechain, err
+}
@@ -0,0 +1,119 @@
+package address
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type restoreCommand struct {
+	mnemonic        string
+	privateSpendKey string
+	privateViewKey  string
+	address         string
+	networkName     string
+}
+
+func (c *restoreCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "restore the keys of a wallet from its mnemonic or keys",
+		Long: `Restores the full set of keys of a wallet out of either its mnemonic (25
+words, or 24 leaving out the checksum one), its private spend key, or - for
+a view-only wallet - its private view key along with its primary address.
+
+A private view key alone isn't enough to restore a view-only wallet: the
+public spend key (and the network) come from the primary address, which
+must then be supplied with --address.`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.mnemonic, "mnemonic", "",
+		"mnemonic seed (space-separated words)")
+	cmd.Flags().StringVar(&c.privateSpendKey, "private-spend-key", "",
+		"hex-encoded private spend key")
+	cmd.Flags().StringVar(&c.privateViewKey, "private-view-key", "",
+		"hex-encoded private view key (requires --address)")
+	cmd.Flags().StringVar(&c.address, "address", "",
+		"primary address of the view-only wallet (required by --private-view-key)")
+
+	cmd.Flags().StringVar(&c.networkName, "network", "mainnet",
+		"network that the addresses should be used for "+
+			networkOptions()+" (must match --address if both are set)")
+
+	return cmd
+}
+
+func (c *restoreCommand) RunE(cmd *cobra.Command, _ []string) error {
+	network, err := parseNetwork(c.networkName)
+	if err != nil {
+		return fmt.Errorf("network: %w", err)
+	}
+
+	switch {
+	case c.mnemonic != "":
+		seed, err := monero.NewSeedFromMnemonic(
+			strings.Fields(c.mnemonic), monero.WithNetwork(network),
+		)
+		if err != nil {
+			return fmt.Errorf("restore from mnemonic: %w", err)
+		}
+
+		prettySeed(seed)
+	case c.privateSpendKey != "":
+		seed, err := monero.NewSeedFromHex(
+			c.privateSpendKey, monero.WithNetwork(network),
+		)
+		if err != nil {
+			return fmt.Errorf("restore from private spend key: %w", err)
+		}
+
+		prettySeed(seed)
+	case c.privateViewKey != "":
+		if c.address == "" {
+			return fmt.Errorf("--address must be set along with --private-view-key")
+		}
+
+		keys, err := viewOnlyKeys(c.address, c.privateViewKey)
+		if err != nil {
+			return fmt.Errorf("restore from private view key: %w", err)
+		}
+
+		primary := keys.PrimaryAddress()
+		if cmd.Flags().Changed("network") && primary.Network != network {
+			return fmt.Errorf("--network %s doesn't match the %s address",
+				network, primary.Network)
+		}
+
+		c.prettyViewOnly(keys)
+	default:
+		return fmt.Errorf("one of --mnemonic, --private-spend-key " +
+			"or --private-view-key must be set")
+	}
+
+	return nil
+}
+
+// nolint:forbidigo
+func (c *restoreCommand) prettyViewOnly(keys *monero.ViewOnlyKeys) {
+	table := display.NewTable()
+
+	table.AddRow("Primary Address:", keys.PrimaryAddress().String())
+	table.AddRow("Private View Key:",
+		hex.EncodeToString(keys.PrivateViewKey()))
+	table.AddRow("Public Spend Key:",
+		hex.EncodeToString(keys.PublicSpendKey()))
+	table.AddRow("Public View Key:",
+		hex.EncodeToString(keys.PublicViewKey()))
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&restoreCommand{}).Cmd())
+}
@@ -0,0 +1,133 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type subaddressCommand struct {
+	address        string
+	privateViewKey string
+
+	account uint32
+	index   uint32
+	count   uint32
+
+	JSON bool
+}
+
+func (c *subaddressCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subaddress",
+		Short: "derive subaddresses from a primary address and its view key",
+		Long: `Derives subaddresses of a wallet out of its primary address and private
+view key, without reaching out to any wallet.`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.address, "address", "",
+		"primary address of the wallet")
+	_ = cmd.MarkFlagRequired("address")
+
+	cmd.Flags().StringVar(&c.privateViewKey, "private-view-key", "",
+		"hex-encoded private view key of the wallet")
+	_ = cmd.MarkFlagRequired("private-view-key")
+
+	cmd.Flags().Uint32Var(&c.account, "account", 0,
+		"index of the account (major index)")
+	cmd.Flags().Uint32Var(&c.index, "index", 0,
+		"index of the first subaddress within the account (minor index)")
+	cmd.Flags().Uint32Var(&c.count, "count", 1,
+		"number of consecutive subaddresses to derive")
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *subaddressCommand) RunE(_ *cobra.Command, _ []string) error {
+	keys, err := viewOnlyKeys(c.address, c.privateViewKey)
+	if err != nil {
+		return err
+	}
+
+	addrs := keys.Subaddresses(c.account, c.index, c.count)
+
+	if c.JSON {
+		type subaddress struct {
+			monero.SubaddressIndex
+			Address string `json:"address"`
+		}
+
+		res := make([]subaddress, len(addrs))
+		for idx, addr := range addrs {
+			res[idx] = subaddress{
+				SubaddressIndex: monero.SubaddressIndex{
+					Major: c.account,
+					Minor: c.index + uint32(idx),
+				},
+				Address: addr.String(),
+			}
+		}
+
+		return display.JSON(res)
+	}
+
+	c.pretty(addrs)
+	return nil
+}
+
+// viewOnlyKeys builds the view-only keys of the wallet whose primary address
+// is `address`, making sure that `privateViewKey` belongs to it.
+//
+func viewOnlyKeys(address, privateViewKey string) (*monero.ViewOnlyKeys, error) {
+	primary, err := monero.ParseAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("parse address: %w", err)
+	}
+
+	if primary.Kind != monero.AddressStandard {
+		return nil, fmt.Errorf("%s address rather than a primary one", primary.Kind)
+	}
+
+	viewKey, err := hex.DecodeString(privateViewKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode private view key: %w", err)
+	}
+
+	keys, err := monero.NewViewOnlyKeys(
+		viewKey, primary.PublicSpendKey, primary.Network,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new view-only keys: %w", err)
+	}
+
+	if !bytes.Equal(keys.PublicViewKey(), primary.PublicViewKey) {
+		return nil, fmt.Errorf("private view key doesn't belong to the address")
+	}
+
+	return keys, nil
+}
+
+// nolint:forbidigo
+func (c *subaddressCommand) pretty(addrs []*monero.Address) {
+	table := display.NewTable()
+
+	table.AddRow("ACCOUNT", "INDEX", "ADDRESS")
+	for idx, addr := range addrs {
+		table.AddRow(c.account, c.index+uint32(idx), addr.String())
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&subaddressCommand{}).Cmd())
+}
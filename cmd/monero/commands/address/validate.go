@@ -0,0 +1,66 @@
+package address
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type validateCommand struct {
+	networkName string
+}
+
+func (c *validateCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <address>",
+		Short: "check whether an address is valid",
+		Long: `Checks whether an address is valid (encoding, checksum and keys),
+exiting with a non-zero code if it isn't.`,
+		Args: cobra.ExactArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.networkName, "network", "",
+		"network that the address must be meant for "+
+			networkOptions()+" - any if not set")
+
+	return cmd
+}
+
+func (c *validateCommand) RunE(_ *cobra.Command, args []string) error {
+	addr, err := monero.ParseAddress(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+
+	if c.networkName != "" {
+		network, err := parseNetwork(c.networkName)
+		if err != nil {
+			return fmt.Errorf("network: %w", err)
+		}
+
+		if network == monero.NetworkFakechain {
+			network = monero.NetworkMainnet
+		}
+
+		if addr.Network != network {
+			return fmt.Errorf("invalid address: meant for %s rather than %s",
+				addr.Network, network)
+		}
+	}
+
+	c.pretty(addr)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *validateCommand) pretty(addr *monero.Address) {
+	fmt.Printf("valid %s %s address\n", addr.Network, addr.Kind)
+}
+
+func init() {
+	RootCommand.AddCommand((&validateCommand{}).Cmd())
+}
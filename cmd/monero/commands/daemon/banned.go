@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type bannedCommand struct {
+	Address string
+
+	JSON bool
+}
+
+func (c *bannedCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "banned",
+		Short: "check whether a node is banned",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringVar(&c.Address, "address",
+		"", "address of the node to check")
+	_ = cmd.MarkFlagRequired("address")
+
+	return cmd
+}
+
+func (c *bannedCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.Banned(ctx, c.Address)
+	if err != nil {
+		return fmt.Errorf("banned: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *bannedCommand) pretty(v *daemon.BannedResult) {
+	table := display.NewTable()
+
+	table.AddRow("Banned:", v.Banned)
+	if v.Banned {
+		table.AddRow("For:", time.Duration(v.Seconds)*time.Second)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&bannedCommand{}).Cmd())
+}
@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirm asks for confirmation before going on with an action that can't be
+// undone, unless `yes` has been supplied already.
+//
+// nolint:forbidigo
+func confirm(yes bool, format string, args ...interface{}) error {
+	if yes {
+		return nil
+	}
+
+	fmt.Printf(format+" [y/N] ", args...)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("read confirmation: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+
+	return fmt.Errorf("aborted")
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type flushTxpoolCommand struct {
+	TxIDs []string
+	Yes   bool
+
+	JSON bool
+}
+
+func (c *flushTxpoolCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush-txpool",
+		Short: "drop transactions from the transaction pool",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringArrayVar(&c.TxIDs, "txid",
+		nil, "id of a transaction to drop (all of them if none is given)")
+	cmd.Flags().BoolVarP(&c.Yes, "yes", "y",
+		false, "do not ask for confirmation")
+
+	return cmd
+}
+
+func (c *flushTxpoolCommand) RunE(_ *cobra.Command, _ []string) error {
+	what := "all of the transactions"
+	if len(c.TxIDs) != 0 {
+		what = fmt.Sprintf("%d transaction(s)", len(c.TxIDs))
+	}
+
+	if err := confirm(c.Yes, "drop %s from the pool?", what); err != nil {
+		return err
+	}
+
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.FlushTxpool(ctx, daemon.FlushTxpoolRequestParameters{
+		TxIDs: c.TxIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("flush txpool: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *flushTxpoolCommand) pretty(v *daemon.FlushTxpoolResult) {
+	table := display.NewTable()
+	table.AddRow("Status:", v.Status)
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&flushTxpoolCommand{}).Cmd())
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type getAltBlocksHashesCommand struct {
+	JSON bool
+}
+
+func (c *getAltBlocksHashesCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-alt-blocks-hashes",
+		Short: "hashes of the blocks known to the node but not in the main chain",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *getAltBlocksHashesCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.GetAltBlocksHashes(ctx)
+	if err != nil {
+		return fmt.Errorf("get alt blocks hashes: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *getAltBlocksHashesCommand) pretty(v *daemon.GetAltBlocksHashesResult) {
+	for _, hash := range v.BlksHashes {
+		fmt.Println(hash)
+	}
+}
+
+func init() {
+	RootCommand.AddCommand((&getAltBlocksHashesCommand{}).Cmd())
+}
@@ -118,11 +118,12 @@ func (c *getBlockCommand) pretty(ctx context.Context, v *daemon.GetBlockResult)
 
 	fees := uint64(0)
 	for _, txnDetails := range txnsDetails {
-		fees += txnDetails.RctSignatures.Txnfee
+		fees += txnDetails.Fee()
 	}
 
 	table.AddRow("Fees:", display.PreciseXMR(fees))
 	table.AddRow("Block Subsidy:", display.PreciseXMR(v.BlockHeader.Reward-fees))
+	prettyTxExtra(table, blockDetails.MinerTx.Extra)
 	fmt.Println(table)
 	fmt.Println("")
 
@@ -131,7 +132,7 @@ func (c *getBlockCommand) pretty(ctx context.Context, v *daemon.GetBlockResult)
 	for idx, txn := range txnsResult.Txs {
 		txnDetails := txnsDetails[idx]
 
-		fee := float64(txnDetails.RctSignatures.Txnfee)
+		fee := float64(txnDetails.Fee())
 		size := len(txn.AsHex) / 2
 
 		table.AddRow(
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jjsteel/go-monero/cmd/monero/display"
 	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
 )
 
 type getBlockTemplateCommand struct {
@@ -28,13 +29,15 @@ func (c *getBlockTemplateCommand) Cmd() *cobra.Command {
 	_ = cmd.MarkFlagRequired("wallet-address")
 
 	cmd.Flags().UintVar(&c.ReserveSize, "reserve-size",
-		0, "reserve size")
+		0, "number of bytes to reserve in the blob for an extra nonce")
 
 	cmd.Flags().StringVar(&c.PreviousBlock, "previous-block",
-		"", "previous block")
+		"", "hash of the block to build the template on top of "+
+			"(defaults to the chain tip)")
 
 	cmd.Flags().StringVar(&c.ExtraNonce, "extra-nonce",
-		"", "extra nonce")
+		"", "hex-encoded extra nonce to embed in the template "+
+			"(conflicts with --reserve-size)")
 
 	return cmd
 }
@@ -48,9 +51,14 @@ func (c *getBlockTemplateCommand) RunE(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("client: %w", err)
 	}
 
-	resp, err := client.GetBlockTemplate(ctx, c.WalletAddress, c.ReserveSize)
+	resp, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+		WalletAddress: c.WalletAddress,
+		ReserveSize:   c.ReserveSize,
+		ExtraNonce:    c.ExtraNonce,
+		PreviousBlock: c.PreviousBlock,
+	})
 	if err != nil {
-		return fmt.Errorf("get block count: %w", err)
+		return fmt.Errorf("get block template: %w", err)
 	}
 
 	return display.JSON(resp)
@@ -0,0 +1,94 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/jjsteel/go-monero/pkg/http"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+// replayClient gives a client serving responses from the cassette at `fpath`,
+// reaching out to the address the cassette was recorded against.
+//
+func replayClient(t *testing.T, fpath string) *daemon.Client {
+	t.Helper()
+
+	cassette, err := mhttp.LoadCassette(fpath)
+	require.NoError(t, err)
+	require.NotEmpty(t, cassette.Interactions)
+
+	u, err := url.Parse(cassette.Interactions[0].Request.URL)
+	require.NoError(t, err)
+
+	replay, err := mhttp.NewReplayTransportFromCassette(cassette)
+	require.NoError(t, err)
+
+	c, err := rpc.NewClient(u.Scheme+"://"+u.Host,
+		rpc.WithHTTPClient(&http.Client{Transport: replay}),
+	)
+	require.NoError(t, err)
+
+	return daemon.NewClient(c)
+}
+
+// captureStdout gives what `fn` writes to stdout.
+//
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	fn()
+	require.NoError(t, w.Close())
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+// TestGetInfoPretty replays the `get_info` cassettes under testdata (one per
+// network, recorded through a RecordTransport from daemontest servers set up
+// for each) through the pretty printer.
+//
+// nolint:paralleltest
+func TestGetInfoPretty(t *testing.T) {
+	for _, network := range []string{"mainnet", "stagenet", "testnet"} {
+		client := replayClient(t,
+			filepath.Join("testdata", "get_info-"+network+".json"))
+
+		resp, err := client.GetInfo(context.Background())
+		require.NoError(t, err, network)
+
+		out := captureStdout(t, func() {
+			(&getInfoCommand{}).pretty(resp)
+		})
+
+		for _, row := range []string{
+			`Nettype:\s+` + network,
+			`Height:\s+12`,
+			`TopBlockHash:\s+` + resp.TopBlockHash,
+		} {
+			assert.Regexp(t, regexp.MustCompile(`(?m)^`+row+`\s*$`), out, network)
+		}
+	}
+}
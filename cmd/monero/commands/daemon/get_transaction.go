@@ -2,7 +2,6 @@ package daemon
 
 import (
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -104,7 +103,7 @@ func (c *getTransactionCommand) prettyHeader(
 ) error {
 	table := display.NewTable()
 
-	fee := float64(txnDetails.RctSignatures.Txnfee)
+	fee := float64(txnDetails.Fee())
 	size := len(txn.AsHex) / 2
 
 	table.AddRow("Hash:", txn.TxHash)
@@ -112,7 +111,7 @@ func (c *getTransactionCommand) prettyHeader(
 	table.AddRow("Fee per kB (µɱ):", (fee/constant.MicroXMR)/(float64(size)/1024))
 	table.AddRow("In/Out:", fmt.Sprintf("%d/%d", len(txnDetails.Vin), len(txnDetails.Vout)))
 	table.AddRow("Size:", humanize.IBytes(uint64(len(txn.AsHex))/2))
-	table.AddRow("Public Key:", hex.EncodeToString(txnDetails.Extra[1:33]))
+	prettyTxExtra(table, txnDetails.Extra)
 
 	if !txn.InPool {
 		table.AddRow("Age:", humanize.Time(time.Unix(txn.BlockTimestamp, 0)))
@@ -154,7 +153,7 @@ func (c *getTransactionCommand) prettyOutputs(
 		if len(txn.OutputIndices) != 0 {
 			outIdx = txn.OutputIndices[idx]
 		}
-		table.AddRow(idx, vout.Target.Key, amount, outIdx)
+		table.AddRow(idx, vout.Key(), amount, outIdx)
 	}
 
 	fmt.Println(table)
@@ -169,6 +168,10 @@ func (c *getTransactionCommand) prettyInputs(
 	txnDetails *daemon.TransactionJSON,
 ) error {
 	for _, vin := range txnDetails.Vin {
+		if vin.Key == nil {
+			continue
+		}
+
 		outsResp, err := c.client.GetOuts(ctx, decodeOffsets(vin.Key.KeyOffsets), true)
 		if err != nil {
 			return fmt.Errorf("outs: %w", err)
@@ -176,7 +179,7 @@ func (c *getTransactionCommand) prettyInputs(
 
 		fmt.Println()
 		table := display.NewTable()
-		table.AddRow("Input Key Image:", vin.Key.KImage)
+		table.AddRow("Input Key Image:", vin.Key.KeyImage)
 		fmt.Println(table)
 		fmt.Println()
 
@@ -199,12 +202,12 @@ func (c *getTransactionCommand) prettyInputs(
 	return nil
 }
 
-func decodeOffsets(offsets []uint) []uint {
+func decodeOffsets(offsets []uint64) []uint {
 	accum := uint(0)
 	res := make([]uint, len(offsets))
 
 	for idx, offset := range offsets {
-		accum += offset
+		accum += uint(offset)
 		res[idx] = accum
 	}
 
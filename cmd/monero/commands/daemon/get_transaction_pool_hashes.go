@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type getTransactionPoolHashesCommand struct {
+	JSON bool
+}
+
+func (c *getTransactionPoolHashesCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-transaction-pool-hashes",
+		Short: "hashes of the transactions in the pool",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *getTransactionPoolHashesCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.GetTransactionPoolHashes(ctx)
+	if err != nil {
+		return fmt.Errorf("get transaction pool hashes: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *getTransactionPoolHashesCommand) pretty(v *daemon.GetTransactionPoolHashesResult) {
+	for _, hash := range v.TxHashes {
+		fmt.Println(hash)
+	}
+}
+
+func init() {
+	RootCommand.AddCommand((&getTransactionPoolHashesCommand{}).Cmd())
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type getTxpoolBacklogCommand struct {
+	JSON bool
+}
+
+func (c *getTxpoolBacklogCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-txpool-backlog",
+		Short: "weight, fee and age of the transactions in the pool",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *getTxpoolBacklogCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.GetTxpoolBacklog(ctx)
+	if err != nil {
+		return fmt.Errorf("get txpool backlog: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *getTxpoolBacklogCommand) pretty(v *daemon.GetTxpoolBacklogResult) {
+	table := display.NewTable()
+
+	table.AddRow("WEIGHT", "FEE", "TIME IN POOL")
+	for _, entry := range v.Backlog {
+		table.AddRow(entry.Weight, display.PreciseXMR(entry.Fee),
+			time.Duration(entry.TimeInPool)*time.Second)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&getTxpoolBacklogCommand{}).Cmd())
+}
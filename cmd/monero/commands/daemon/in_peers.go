@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type inPeersCommand struct {
+	Limit uint32
+
+	JSON bool
+}
+
+func (c *inPeersCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "in-peers",
+		Short: "retrieve or update the maximum number of incoming connections",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().Uint32Var(&c.Limit, "limit",
+		0, "new maximum number of incoming connections "+
+			"(the current one is only shown if not set)")
+
+	return cmd
+}
+
+func (c *inPeersCommand) RunE(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.InPeers(ctx, daemon.InPeersRequestParameters{
+		Set:     cmd.Flags().Changed("limit"),
+		InPeers: c.Limit,
+	})
+	if err != nil {
+		return fmt.Errorf("in peers: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *inPeersCommand) pretty(v *daemon.InPeersResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("In Peers:", v.InPeers)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&inPeersCommand{}).Cmd())
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type keyImageStatusCommand struct {
+	KeyImages []string
+
+	JSON bool
+}
+
+func (c *keyImageStatusCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "key-image-status",
+		Short: "whether key images have been spent",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringArrayVar(&c.KeyImages, "key-image",
+		[]string{}, "hex-encoded key image to check")
+	_ = cmd.MarkFlagRequired("key-image")
+
+	return cmd
+}
+
+func (c *keyImageStatusCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.IsKeyImageSpent(ctx, c.KeyImages)
+	if err != nil {
+		return fmt.Errorf("is key image spent: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *keyImageStatusCommand) pretty(v *daemon.IsKeyImageSpentResult) {
+	table := display.NewTable()
+
+	table.AddRow("KEY IMAGE", "STATUS")
+	for idx, status := range v.SpentStatus {
+		if idx >= len(c.KeyImages) {
+			break
+		}
+
+		table.AddRow(c.KeyImages[idx], status)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&keyImageStatusCommand{}).Cmd())
+}
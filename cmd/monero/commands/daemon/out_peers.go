@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type outPeersCommand struct {
+	Limit uint32
+
+	JSON bool
+}
+
+func (c *outPeersCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "out-peers",
+		Short: "retrieve or update the maximum number of outgoing connections",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().Uint32Var(&c.Limit, "limit",
+		0, "new maximum number of outgoing connections "+
+			"(the current one is only shown if not set)")
+
+	return cmd
+}
+
+func (c *outPeersCommand) RunE(cmd *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.OutPeers(ctx, daemon.OutPeersRequestParameters{
+		Set:      cmd.Flags().Changed("limit"),
+		OutPeers: c.Limit,
+	})
+	if err != nil {
+		return fmt.Errorf("out peers: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *outPeersCommand) pretty(v *daemon.OutPeersResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Out Peers:", v.OutPeers)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&outPeersCommand{}).Cmd())
+}
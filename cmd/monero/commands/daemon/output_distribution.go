@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type outputDistributionCommand struct {
+	Amounts    []uint
+	FromHeight uint64
+	ToHeight   uint64
+	Cumulative bool
+	Binary     bool
+	Compress   bool
+
+	JSON bool
+	CSV  bool
+}
+
+func (c *outputDistributionCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "output-distribution",
+		Short: "number of outputs of given amounts created at each height",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().BoolVar(&c.CSV, "csv",
+		false, "whether or not to output the distributions as csv "+
+			"(one row per amount and height)")
+
+	cmd.Flags().UintSliceVar(&c.Amounts, "amount",
+		[]uint{0}, "amounts to look for (0 for ringct outputs)")
+	cmd.Flags().Uint64Var(&c.FromHeight, "from-height",
+		0, "height to start the distributions from")
+	cmd.Flags().Uint64Var(&c.ToHeight, "to-height",
+		0, "height to end the distributions at (0 for the top of the chain)")
+	cmd.Flags().BoolVar(&c.Cumulative, "cumulative",
+		false, "whether or not to give cumulative distributions")
+	cmd.Flags().BoolVar(&c.Binary, "binary",
+		true, "have the node send distributions in binary form")
+	cmd.Flags().BoolVar(&c.Compress, "compress",
+		true, "have the node compress binary distributions")
+
+	return cmd
+}
+
+func (c *outputDistributionCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+		Amounts:    amounts(c.Amounts),
+		FromHeight: c.FromHeight,
+		ToHeight:   c.ToHeight,
+		Cumulative: c.Cumulative,
+		Binary:     c.Binary,
+		Compress:   c.Compress,
+	})
+	if err != nil {
+		return fmt.Errorf("get output distribution: %w", err)
+	}
+
+	switch {
+	case c.JSON:
+		return display.JSON(resp)
+	case c.CSV:
+		return c.csv(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+func (c *outputDistributionCommand) csv(v *daemon.GetOutputDistributionResult) error {
+	w := csv.NewWriter(os.Stdout)
+
+	if err := w.Write([]string{"amount", "height", "outputs"}); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	for _, d := range v.Distributions {
+		for idx, outputs := range d.Distribution {
+			err := w.Write([]string{
+				strconv.FormatUint(d.Amount, 10),
+				strconv.FormatUint(d.StartHeight+uint64(idx), 10),
+				strconv.FormatUint(outputs, 10),
+			})
+			if err != nil {
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// nolint:forbidigo
+func (c *outputDistributionCommand) pretty(v *daemon.GetOutputDistributionResult) {
+	table := display.NewTable()
+
+	table.AddRow("AMOUNT", "START HEIGHT", "HEIGHTS", "BASE", "OUTPUTS")
+	for _, d := range v.Distributions {
+		var outputs uint64
+
+		switch {
+		case len(d.Distribution) == 0:
+		case c.Cumulative:
+			outputs = d.Distribution[len(d.Distribution)-1] - d.Base
+		default:
+			for _, n := range d.Distribution {
+				outputs += n
+			}
+		}
+
+		table.AddRow(display.PreciseXMR(d.Amount), d.StartHeight,
+			len(d.Distribution), d.Base, outputs)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&outputDistributionCommand{}).Cmd())
+}
@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type outputHistogramCommand struct {
+	Amounts  []uint
+	MinCount uint64
+	MaxCount uint64
+	Unlocked bool
+	Recent   time.Duration
+
+	JSON bool
+}
+
+func (c *outputHistogramCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "output-histogram",
+		Short: "number of outputs of each amount in the chain",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	cmd.Flags().UintSliceVar(&c.Amounts, "amount",
+		[]uint{}, "amounts to look for (all if none)")
+	cmd.Flags().Uint64Var(&c.MinCount, "min-count",
+		0, "minimum number of outputs for an amount to be included")
+	cmd.Flags().Uint64Var(&c.MaxCount, "max-count",
+		0, "maximum number of outputs for an amount to be included "+
+			"(0 for no limit)")
+	cmd.Flags().BoolVar(&c.Unlocked, "unlocked",
+		false, "only count outputs that can be spent")
+	cmd.Flags().DurationVar(&c.Recent, "recent",
+		0, "how far back outputs are considered recent (e.g., 24h)")
+
+	return cmd
+}
+
+func (c *outputHistogramCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	params := daemon.GetOutputHistogramRequestParameters{
+		Amounts:  amounts(c.Amounts),
+		MinCount: c.MinCount,
+		MaxCount: c.MaxCount,
+		Unlocked: c.Unlocked,
+	}
+
+	if c.Recent != 0 {
+		params.RecentCutoff = uint64(time.Now().Add(-c.Recent).Unix())
+	}
+
+	resp, err := client.GetOutputHistogram(ctx, params)
+	if err != nil {
+		return fmt.Errorf("get output histogram: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *outputHistogramCommand) pretty(v *daemon.GetOutputHistogramResult) {
+	table := display.NewTable()
+
+	table.AddRow("AMOUNT", "TOTAL", "UNLOCKED", "RECENT")
+	for _, entry := range v.Histogram {
+		table.AddRow(display.PreciseXMR(entry.Amount), entry.TotalInstances,
+			entry.UnlockedInstances, entry.RecentInstances)
+	}
+
+	fmt.Println(table)
+}
+
+// amounts converts amounts taken from flags to the type the client expects.
+//
+func amounts(v []uint) []uint64 {
+	res := make([]uint64, len(v))
+	for idx := range v {
+		res[idx] = uint64(v[idx])
+	}
+
+	return res
+}
+
+func init() {
+	RootCommand.AddCommand((&outputHistogramCommand{}).Cmd())
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type popBlocksCommand struct {
+	Blocks uint64
+	Yes    bool
+
+	JSON bool
+}
+
+func (c *popBlocksCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pop-blocks",
+		Short: "remove blocks from the top of the chain",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().Uint64Var(&c.Blocks, "blocks",
+		0, "number of blocks to remove")
+	_ = cmd.MarkFlagRequired("blocks")
+	cmd.Flags().BoolVarP(&c.Yes, "yes", "y",
+		false, "do not ask for confirmation")
+
+	return cmd
+}
+
+func (c *popBlocksCommand) RunE(_ *cobra.Command, _ []string) error {
+	err := confirm(c.Yes, "remove the top %d block(s) from the chain?", c.Blocks)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.PopBlocks(ctx, daemon.PopBlocksRequestParameters{
+		NBlocks: c.Blocks,
+	})
+	if err != nil {
+		return fmt.Errorf("pop blocks: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *popBlocksCommand) pretty(v *daemon.PopBlocksResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Height:", v.Height)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&popBlocksCommand{}).Cmd())
+}
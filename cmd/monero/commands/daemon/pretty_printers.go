@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/gosuri/uitable"
 
 	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
 	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
 )
 
@@ -37,3 +39,37 @@ func prettyBlockHeader(table *uitable.Table, header daemon.BlockHeader) {
 	table.AddRow("Wide Cumulative Difficulty:", header.WideCumulativeDifficulty)
 	table.AddRow("Wide Difficulty:", header.WideDifficulty)
 }
+
+// prettyTxExtra adds to the table the fields of interest in `tx_extra`,
+// showing whatever could be parsed from it if malformed.
+//
+func prettyTxExtra(table *uitable.Table, extra monero.TxExtra) {
+	fields, err := extra.Fields()
+	if err != nil {
+		table.AddRow("Extra:", fmt.Sprintf("malformed (%v)", err))
+	}
+
+	if key := fields.PublicKey(); key != nil {
+		table.AddRow("Public Key:", hex.EncodeToString(key))
+	}
+
+	if keys := fields.AdditionalPublicKeys(); len(keys) != 0 {
+		table.AddRow("Additional Public Keys:", len(keys))
+	}
+
+	if nonce := fields.Nonce(); nonce != nil {
+		switch {
+		case nonce.PaymentID() != nil:
+			table.AddRow("Payment ID:", hex.EncodeToString(nonce.PaymentID()))
+		case nonce.EncryptedPaymentID() != nil:
+			table.AddRow("Encrypted Payment ID:", hex.EncodeToString(nonce.EncryptedPaymentID()))
+		default:
+			table.AddRow("Extra Nonce:", hex.EncodeToString(nonce.Data))
+		}
+	}
+
+	if tag := fields.MergeMining(); tag != nil {
+		table.AddRow("Merge Mining Root:", fmt.Sprintf("%s (depth %d)",
+			hex.EncodeToString(tag.MerkleRoot), tag.Depth))
+	}
+}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type pruneBlockchainCommand struct {
+	Check bool
+	Yes   bool
+
+	JSON bool
+}
+
+func (c *pruneBlockchainCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune-blockchain",
+		Short: "prune the chain, or check whether it is",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().BoolVar(&c.Check, "check",
+		false, "only check whether the chain is pruned")
+	cmd.Flags().BoolVarP(&c.Yes, "yes", "y",
+		false, "do not ask for confirmation")
+
+	return cmd
+}
+
+func (c *pruneBlockchainCommand) RunE(_ *cobra.Command, _ []string) error {
+	if !c.Check {
+		err := confirm(c.Yes, "prune the chain? (pruned data can't be recovered)")
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.PruneBlockchain(ctx, daemon.PruneBlockchainRequestParameters{
+		Check: c.Check,
+	})
+	if err != nil {
+		return fmt.Errorf("prune blockchain: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *pruneBlockchainCommand) pretty(v *daemon.PruneBlockchainResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Pruned:", v.Pruned)
+	table.AddRow("Pruning Seed:", fmt.Sprintf("0x%x", v.PruningSeed))
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&pruneBlockchainCommand{}).Cmd())
+}
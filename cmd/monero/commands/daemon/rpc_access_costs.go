@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type rpcAccessCostsCommand struct {
+	JSON bool
+}
+
+func (c *rpcAccessCostsCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-access-costs",
+		Short: "credits charged per call of each rpc method",
+		Long: "Computes the average number of credits charged per call " +
+			"of each rpc method from the statistics the node keeps " +
+			"(see rpc-access-tracking).",
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+// rpcCost is the cost of calls to an rpc method.
+//
+type rpcCost struct {
+	RPC            string  `json:"rpc"`
+	Calls          uint64  `json:"calls"`
+	Credits        uint64  `json:"credits"`
+	CreditsPerCall float64 `json:"credits_per_call"`
+}
+
+func (c *rpcAccessCostsCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.RPCAccessTracking(ctx)
+	if err != nil {
+		return fmt.Errorf("rpc access tracking: %w", err)
+	}
+
+	costs := rpcCosts(resp)
+
+	if c.JSON {
+		return display.JSON(costs)
+	}
+
+	c.pretty(costs)
+	return nil
+}
+
+// rpcCosts computes the cost per call of each method from the tracking
+// statistics, most expensive first.
+//
+func rpcCosts(v *daemon.RPCAccessTrackingResult) []rpcCost {
+	costs := make([]rpcCost, 0, len(v.Data))
+
+	for _, entry := range v.Data {
+		cost := rpcCost{
+			RPC:     entry.RPC,
+			Calls:   entry.Count,
+			Credits: entry.Credits,
+		}
+
+		if entry.Count > 0 {
+			cost.CreditsPerCall = float64(entry.Credits) / float64(entry.Count)
+		}
+
+		costs = append(costs, cost)
+	}
+
+	sort.Slice(costs, func(i, j int) bool {
+		return costs[i].CreditsPerCall > costs[j].CreditsPerCall
+	})
+
+	return costs
+}
+
+// nolint:forbidigo
+func (c *rpcAccessCostsCommand) pretty(v []rpcCost) {
+	table := display.NewTable()
+
+	table.AddRow("RPC", "CALLS", "CREDITS", "CREDITS PER CALL")
+	for _, cost := range v {
+		table.AddRow(cost.RPC, cost.Calls, cost.Credits,
+			fmt.Sprintf("%.2f", cost.CreditsPerCall))
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&rpcAccessCostsCommand{}).Cmd())
+}
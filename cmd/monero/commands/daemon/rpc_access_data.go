@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type rpcAccessDataCommand struct {
+	JSON bool
+}
+
+func (c *rpcAccessDataCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-access-data",
+		Short: "accounts of the clients paying for rpc with credits",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *rpcAccessDataCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.RPCAccessData(ctx)
+	if err != nil {
+		return fmt.Errorf("rpc access data: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *rpcAccessDataCommand) pretty(v *daemon.RPCAccessDataResult) {
+	table := display.NewTable()
+
+	table.AddRow("CLIENT", "BALANCE", "EARNED", "SPENT",
+		"GOOD", "STALE", "BAD", "DUPE", "LAST UPDATE")
+	for _, entry := range v.Entries {
+		table.AddRow(
+			options.RootOpts.AddrFmter()(entry.Client),
+			entry.Balance,
+			entry.CreditsTotal,
+			entry.CreditsUsed,
+			entry.NoncesGood,
+			entry.NoncesStale,
+			entry.NoncesBad,
+			entry.NoncesDupe,
+			time.Unix(int64(entry.LastUpdateTime), 0).Format(time.RFC3339),
+		)
+	}
+
+	fmt.Println(table)
+	fmt.Println()
+	fmt.Println("Hashrate:", v.Hashrate)
+}
+
+func init() {
+	RootCommand.AddCommand((&rpcAccessDataCommand{}).Cmd())
+}
@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type rpcAccessInfoCommand struct {
+	JSON bool
+}
+
+func (c *rpcAccessInfoCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rpc-access-info",
+		Short: "credits available and how to earn them on a node offering rpc for credits",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *rpcAccessInfoCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	signature, err := options.RootOpts.RPCPaymentClient()
+	if err != nil {
+		return fmt.Errorf("rpc payment client: %w", err)
+	}
+
+	resp, err := client.RPCAccessInfo(ctx, daemon.RPCAccessInfoRequestParameters{
+		Client: signature,
+	})
+	if err != nil {
+		return fmt.Errorf("rpc access info: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *rpcAccessInfoCommand) pretty(v *daemon.RPCAccessInfoResult) {
+	table := display.NewTable()
+
+	table.AddRow("Credits:", v.Credits)
+	table.AddRow("Credits Per Hash Found:", v.CreditsPerHashFound)
+	table.AddRow("Difficulty:", v.Diff)
+	table.AddRow("Height:", v.Height)
+	table.AddRow("Top Hash:", v.TopHash)
+	table.AddRow("Seed Height:", v.SeedHeight)
+	table.AddRow("Seed Hash:", v.SeedHash)
+	table.AddRow("Next Seed Hash:", v.NextSeedHash)
+	table.AddRow("Cookie:", v.Cookie)
+	table.AddRow("Hashing Blob:", v.HashingBlob)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&rpcAccessInfoCommand{}).Cmd())
+}
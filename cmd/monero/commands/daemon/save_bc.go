@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type saveBcCommand struct {
+	JSON bool
+}
+
+func (c *saveBcCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save-bc",
+		Short: "save the chain to disk",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *saveBcCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.SaveBc(ctx)
+	if err != nil {
+		return fmt.Errorf("save bc: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *saveBcCommand) pretty(v *daemon.SaveBcResult) {
+	table := display.NewTable()
+	table.AddRow("Status:", v.Status)
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&saveBcCommand{}).Cmd())
+}
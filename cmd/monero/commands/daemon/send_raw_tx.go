@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type sendRawTxCommand struct {
+	Tx           string
+	File         string
+	DoNotRelay   bool
+	SanityChecks bool
+
+	JSON bool
+}
+
+func (c *sendRawTxCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send-raw-tx",
+		Short: "submit a signed transaction to the node",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringVar(&c.Tx, "tx",
+		"", "hex-encoded signed transaction")
+	cmd.Flags().StringVar(&c.File, "file",
+		"", "file to read the hex-encoded signed transaction from "+
+			"('-' for stdin)")
+	cmd.Flags().BoolVar(&c.DoNotRelay, "do-not-relay",
+		false, "keep the transaction in the node's pool without "+
+			"broadcasting it")
+	cmd.Flags().BoolVar(&c.SanityChecks, "sanity-checks",
+		true, "have the node verify the transaction against heuristics "+
+			"that avoid privacy mistakes")
+
+	return cmd
+}
+
+func (c *sendRawTxCommand) RunE(_ *cobra.Command, _ []string) error {
+	txHex, err := c.txHex()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+		TxAsHex:        txHex,
+		DoNotRelay:     c.DoNotRelay,
+		DoSanityChecks: c.SanityChecks,
+	})
+	if err != nil {
+		var rejected *daemon.TxRejectedError
+		if errors.As(err, &rejected) && c.JSON {
+			if err := display.JSON(rejected.Result); err != nil {
+				return err
+			}
+		}
+
+		return fmt.Errorf("send raw transaction: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// txHex gives the transaction to submit, either from the flag or read from
+// the file supplied.
+//
+func (c *sendRawTxCommand) txHex() (string, error) {
+	if (c.Tx == "") == (c.File == "") {
+		return "", fmt.Errorf("either --tx or --file must be supplied")
+	}
+
+	if c.Tx != "" {
+		return c.Tx, nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if c.File == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(c.File)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("read '%s': %w", c.File, err)
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// nolint:forbidigo
+func (c *sendRawTxCommand) pretty(v *daemon.SendRawTransactionResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Relayed:", !v.NotRelayed)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&sendRawTxCommand{}).Cmd())
+}
@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type submitBlockCommand struct {
+	Blobs []string
+	File  string
+
+	JSON bool
+}
+
+func (c *submitBlockCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit-block",
+		Short: "submit a mined block to the network",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringArrayVar(&c.Blobs, "blob",
+		nil, "hex-encoded block blob (can be specified multiple times)")
+	cmd.Flags().StringVar(&c.File, "file",
+		"", "file to read hex-encoded block blobs from, one per line "+
+			"('-' for stdin)")
+
+	return cmd
+}
+
+func (c *submitBlockCommand) RunE(_ *cobra.Command, _ []string) error {
+	blobs, err := c.blobs()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.SubmitBlock(ctx, blobs...)
+	if err != nil {
+		return fmt.Errorf("submit block: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// blobs gives the blocks to submit, either from the flags or read from the
+// file supplied.
+//
+func (c *submitBlockCommand) blobs() ([]string, error) {
+	if (len(c.Blobs) == 0) == (c.File == "") {
+		return nil, fmt.Errorf("either --blob or --file must be supplied")
+	}
+
+	if len(c.Blobs) != 0 {
+		return c.Blobs, nil
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+
+	if c.File == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(c.File)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read '%s': %w", c.File, err)
+	}
+
+	blobs := strings.Fields(string(b))
+	if len(blobs) == 0 {
+		return nil, fmt.Errorf("no block found in '%s'", c.File)
+	}
+
+	return blobs, nil
+}
+
+// nolint:forbidigo
+func (c *submitBlockCommand) pretty(v *daemon.SubmitBlockResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Block ID:", v.BlockID)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&submitBlockCommand{}).Cmd())
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type updateCommand struct {
+	Download bool
+	Path     string
+
+	JSON bool
+}
+
+func (c *updateCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "check for, or download, an update of monerod",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().BoolVar(&c.Download, "download",
+		false, "have the node download the update rather than only "+
+			"checking for one")
+	cmd.Flags().StringVar(&c.Path, "path",
+		"", "where the node should download the update to "+
+			"(defaults to its data directory)")
+
+	return cmd
+}
+
+func (c *updateCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	params := daemon.UpdateRequestParameters{
+		Command: daemon.UpdateCheck,
+	}
+
+	if c.Download {
+		params.Command = daemon.UpdateDownload
+		params.Path = c.Path
+	}
+
+	resp, err := client.Update(ctx, params)
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *updateCommand) pretty(v *daemon.UpdateResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Update Available:", v.Update)
+
+	if v.Update {
+		table.AddRow("Version:", v.Version)
+		table.AddRow("Hash:", v.Hash)
+		table.AddRow("URI:", v.UserURI)
+	}
+
+	if v.Path != "" {
+		table.AddRow("Path:", v.Path)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&updateCommand{}).Cmd())
+}
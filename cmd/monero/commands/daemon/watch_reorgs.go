@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/cmd/monero/options"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/zmq"
+)
+
+type watchReorgsCommand struct {
+	Interval    time.Duration
+	Window      int
+	ZMQEndpoint string
+	DOT         bool
+
+	JSON bool
+}
+
+func (c *watchReorgsCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch-reorgs",
+		Short: "watch the chain for new blocks, reorgs and alternative chains",
+		Long: `Watches the chain for new blocks, reorgs and alternative chains,
+printing an event for each as they happen.
+
+With --dot, the recent blocks of the main chain along with the alternative
+chains forking off from them are printed as a Graphviz DOT graph instead,
+every time a reorg happens or an alternative chain appears, e.g.:
+
+	monero daemon watch-reorgs --dot | dot -Tsvg -O
+`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the events as json")
+	cmd.Flags().BoolVar(&c.DOT, "dot",
+		false, "print the chains as a graphviz dot graph on reorgs "+
+			"and new alternative chains")
+	cmd.Flags().DurationVar(&c.Interval, "interval",
+		daemon.DefaultPollInterval, "how often to poll the node")
+	cmd.Flags().IntVar(&c.Window, "window",
+		daemon.DefaultMonitorWindow, "number of recent blocks to keep "+
+			"track of (bounding how deep a reorg can be)")
+	cmd.Flags().StringVar(&c.ZMQEndpoint, "zmq-endpoint",
+		"", "zero-mq endpoint to listen for chain_main publications "+
+			"on for learning about new blocks right away")
+
+	return cmd
+}
+
+func (c *watchReorgsCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	opts := []daemon.MonitorOption{
+		daemon.WithMonitorPollInterval(c.Interval),
+		daemon.WithMonitorWindow(c.Window),
+	}
+
+	errC := make(chan error, 1)
+
+	if c.ZMQEndpoint != "" {
+		zmqClient := zmq.NewClient(c.ZMQEndpoint, zmq.TopicMinimalChainMain)
+		defer zmqClient.Close()
+
+		stream, err := zmqClient.Listen(ctx)
+		if err != nil {
+			return fmt.Errorf("listen: %w", err)
+		}
+
+		notifications := make(chan daemon.ChainMain)
+		opts = append(opts, daemon.WithChainMain(notifications))
+
+		go func() {
+			if err := c.forward(ctx, stream, notifications); err != nil {
+				errC <- err
+				cancel()
+			}
+		}()
+	}
+
+	monitor := daemon.NewReorgMonitor(client, opts...)
+
+	err = monitor.Watch(ctx, func(_ context.Context, event daemon.ChainEvent) error {
+		return c.handle(monitor, event)
+	})
+	if errors.Is(err, context.Canceled) {
+		select {
+		case err = <-errC:
+			return fmt.Errorf("zmq: %w", err)
+		default:
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	return nil
+}
+
+// forward forwards the chain_main notifications published over zmq to the
+// monitor.
+//
+func (c *watchReorgsCommand) forward(
+	ctx context.Context, stream *zmq.Stream, notifications chan<- daemon.ChainMain,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-stream.ErrC:
+			return err
+		case v := <-stream.MinimalChainMainC:
+			if v == nil {
+				continue
+			}
+
+			notification := daemon.ChainMain{
+				FirstHeight: v.FirstHeight,
+				FirstPrevID: v.FirstPrevID,
+				IDs:         v.Ids,
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case notifications <- notification:
+			}
+		}
+	}
+}
+
+func (c *watchReorgsCommand) handle(monitor *daemon.ReorgMonitor, event daemon.ChainEvent) error {
+	if c.DOT {
+		if event.NewTip != nil {
+			return nil
+		}
+
+		if err := monitor.WriteDOT(os.Stdout); err != nil {
+			return fmt.Errorf("write dot: %w", err)
+		}
+
+		return nil
+	}
+
+	if c.JSON {
+		return display.JSON(event)
+	}
+
+	c.pretty(event)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *watchReorgsCommand) pretty(v daemon.ChainEvent) {
+	now := time.Now().Format(time.RFC3339)
+
+	switch {
+	case v.NewTip != nil:
+		fmt.Printf("%s  new tip    height=%d hash=%s\n", now,
+			v.NewTip.Block.Height, v.NewTip.Block.Hash)
+	case v.Reorg != nil:
+		orphaned := make([]string, len(v.Reorg.Orphaned))
+		for idx, block := range v.Reorg.Orphaned {
+			orphaned[idx] = block.Hash
+		}
+
+		fmt.Printf("%s  reorg      depth=%d fork_height=%d orphaned=%s\n", now,
+			v.Reorg.Depth, v.Reorg.ForkHeight, strings.Join(orphaned, ","))
+	case v.AltChain != nil:
+		fmt.Printf("%s  alt chain  height=%d length=%d top=%s parent=%s\n", now,
+			v.AltChain.Height, v.AltChain.Length, v.AltChain.BlockHash,
+			v.AltChain.MainChainParentBlock)
+	}
+}
+
+func init() {
+	RootCommand.AddCommand((&watchReorgsCommand{}).Cmd())
+}
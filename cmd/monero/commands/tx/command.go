@@ -0,0 +1,10 @@
+package tx
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var RootCommand = &cobra.Command{
+	Use:   "tx",
+	Short: "offline transaction utils",
+}
@@ -0,0 +1,119 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jjsteel/go-monero/cmd/monero/display"
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+type decodeCommand struct {
+	JSON bool
+}
+
+func (c *decodeCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode <hex>",
+		Short: "decode a hex-encoded (possibly pruned) transaction",
+		Long: `Decodes a hex-encoded transaction (e.g., the as_hex or pruned_as_hex
+fields of get_transactions) without reaching out to any node.`,
+		Args: cobra.ExactArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	return cmd
+}
+
+func (c *decodeCommand) RunE(_ *cobra.Command, args []string) error {
+	blob, err := hex.DecodeString(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fmt.Errorf("decode hex: %w", err)
+	}
+
+	txn, err := monero.DecodeTransaction(blob)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return display.JSON(txn)
+	}
+
+	c.pretty(txn)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *decodeCommand) pretty(txn *monero.Transaction) {
+	table := display.NewTable()
+
+	pruned := txn.RctSigPrunable == nil && txn.RctSignatures.Type != monero.RctTypeNull
+	if txn.Version == 1 {
+		pruned = txn.Signatures == nil && !txn.IsCoinbase()
+	}
+
+	if hash, err := txn.Hash(); err == nil {
+		table.AddRow("Hash:", hash)
+	}
+
+	if prefixHash, err := txn.PrefixHash(); err == nil {
+		table.AddRow("Prefix Hash:", prefixHash)
+	}
+
+	table.AddRow("Version:", txn.Version)
+	table.AddRow("Unlock Time:", txn.UnlockTime)
+	table.AddRow("Coinbase:", txn.IsCoinbase())
+	table.AddRow("RingCT Type:", txn.RctSignatures.Type)
+	table.AddRow("Fee:", display.PreciseXMR(txn.Fee()))
+	table.AddRow("In/Out:", fmt.Sprintf("%d/%d", len(txn.Vin), len(txn.Vout)))
+	table.AddRow("Pruned:", pruned)
+
+	if fields, err := txn.Extra.Fields(); err == nil && fields.PublicKey() != nil {
+		table.AddRow("Public Key:", hex.EncodeToString(fields.PublicKey()))
+	}
+
+	fmt.Println(table)
+	fmt.Println("")
+
+	table = display.NewTable()
+	table.AddRow("", "STEALTH ADDR", "VIEW TAG", "AMOUNT")
+	for idx, vout := range txn.Vout {
+		viewTag := ""
+		if vout.Target.TaggedKey != nil {
+			viewTag = vout.Target.TaggedKey.ViewTag
+		}
+
+		amount := "?"
+		if vout.Amount != 0 {
+			amount = display.PreciseXMR(vout.Amount)
+		}
+
+		table.AddRow(idx, vout.Key(), viewTag, amount)
+	}
+
+	fmt.Println(table)
+	fmt.Println("")
+
+	table = display.NewTable()
+	table.AddRow("", "KEY IMAGE", "RING SIZE")
+	for idx, vin := range txn.Vin {
+		if vin.Key == nil {
+			continue
+		}
+
+		table.AddRow(idx, vin.Key.KeyImage, len(vin.Key.KeyOffsets))
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&decodeCommand{}).Cmd())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jjsteel/go-monero/cmd/monero/commands/address"
 	"github.com/jjsteel/go-monero/cmd/monero/commands/daemon"
 	"github.com/jjsteel/go-monero/cmd/monero/commands/p2p"
+	"github.com/jjsteel/go-monero/cmd/monero/commands/tx"
 	"github.com/jjsteel/go-monero/cmd/monero/commands/wallet"
 )
 
@@ -36,6 +37,7 @@ func init() {
 	rootCmd.AddCommand(wallet.RootCommand)
 	rootCmd.AddCommand(p2p.RootCommand)
 	rootCmd.AddCommand(address.RootCommand)
+	rootCmd.AddCommand(tx.RootCommand)
 	rootCmd.AddCommand(versionCmd)
 }
 
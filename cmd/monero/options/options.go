@@ -2,14 +2,18 @@ package options
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/jjsteel/go-monero/cmd/monero/display"
 	mhttp "github.com/jjsteel/go-monero/pkg/http"
+	"github.com/jjsteel/go-monero/pkg/monero"
 	"github.com/jjsteel/go-monero/pkg/rpc"
 	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
 	"github.com/jjsteel/go-monero/pkg/rpc/wallet"
@@ -23,9 +27,45 @@ var RootOpts = &options{}
 // package.
 //
 type options struct {
-	address string
+	addresses   []string
+	strategy    string
+	maxAttempts int
 	mhttp.ClientConfig
 	shortenAddresses bool
+
+	cache              bool
+	cacheDir           string
+	cacheConfirmations uint64
+
+	verify bool
+
+	rpcPaymentKey    string
+	rpcPaymentPolicy string
+
+	logCalls    bool
+	rateLimit   float64
+	rateBurst   int
+	metricsFile string
+
+	// metrics gathers the metrics of the calls made when `metricsFile`
+	// has been set, to be written out once the command finishes.
+	//
+	metrics *rpc.Metrics
+
+	// daemonCache is the cache that daemon clients have been set up
+	// with, if any.
+	//
+	daemonCache *daemon.Cache
+
+	// payer is what daemon clients pay for rpc through when a payment
+	// key has been supplied.
+	//
+	payer *daemon.Payer
+
+	// interceptors caches the interceptors built from the options so
+	// that all clients share them (e.g., the same rate limit).
+	//
+	interceptors []rpc.Interceptor
 }
 
 // AddrFmter provides the function that should be used when displaying
@@ -53,12 +93,16 @@ func (o *options) Context() (context.Context, context.CancelFunc) {
 //
 func (o *options) initializeFromEnv() {
 	if address := os.Getenv("MONERO_ADDRESS"); address != "" {
-		o.address = address
+		o.addresses = strings.Split(address, ",")
 	}
 }
 
 // Client instantiates a new daemon RPC client based on the options filled.
 //
+// When more than a single address has been supplied, calls are spread across
+// all of them according to the strategy chosen, with nodes that are not
+// fit for serving calls being left out.
+//
 func (o *options) Client() (*daemon.Client, error) {
 	o.initializeFromEnv()
 
@@ -67,14 +111,114 @@ func (o *options) Client() (*daemon.Client, error) {
 		return nil, fmt.Errorf("new httpclient: %w", err)
 	}
 
-	client, err := rpc.NewClient(o.address, rpc.WithHTTPClient(httpClient))
+	clientOpts := o.clientOptions(httpClient)
+
+	if len(o.addresses) == 1 {
+		client, err := rpc.NewClient(o.addresses[0], clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new daemon client for '%s': %w",
+				o.addresses[0], err,
+			)
+		}
+
+		return o.daemonClient(client)
+	}
+
+	poolOpts := []daemon.PoolOption{
+		daemon.WithStrategy(daemon.Strategy(o.strategy)),
+	}
+
+	if o.Verbose {
+		poolOpts = append(poolOpts, daemon.WithServedCallback(
+			func(call daemon.ServedCall) {
+				fmt.Fprintf(os.Stderr, "%s served by %s in %s (err=%v)\n",
+					call.Method, call.Address, call.Duration, call.Err)
+			},
+		))
+	}
+
+	pool, err := daemon.NewPoolFromAddresses(o.addresses, clientOpts, poolOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("new daemon client for '%s': %w",
-			o.address, err,
+		return nil, fmt.Errorf("new pool for %v: %w", o.addresses, err)
+	}
+
+	ctx, cancel := o.Context()
+	defer cancel()
+
+	pool.HealthCheck(ctx)
+
+	return o.daemonClient(pool)
+}
+
+// daemonClient instantiates a daemon client making requests through
+// `requester`, paying for rpc if a payment key has been supplied, and placing
+// a cache in front of it if enabled.
+//
+func (o *options) daemonClient(requester daemon.Requester) (*daemon.Client, error) {
+	if o.rpcPaymentKey != "" {
+		key, err := hex.DecodeString(o.rpcPaymentKey)
+		if err != nil {
+			return nil, fmt.Errorf("decode rpc payment key: %w", err)
+		}
+
+		o.payer, err = daemon.NewPayer(requester, key,
+			daemon.WithPaymentPolicy(daemon.PaymentPolicy(o.rpcPaymentPolicy)),
 		)
+		if err != nil {
+			return nil, fmt.Errorf("new payer: %w", err)
+		}
+
+		requester = o.payer
 	}
 
-	return daemon.NewClient(client), nil
+	clientOpts := []daemon.ClientOption{}
+	if o.verify {
+		clientOpts = append(clientOpts, daemon.WithVerification())
+	}
+
+	if !o.cache && o.cacheDir == "" {
+		return daemon.NewClient(requester, clientOpts...), nil
+	}
+
+	cacheOpts := []daemon.CacheOption{
+		daemon.WithConfirmations(o.cacheConfirmations),
+	}
+
+	if o.cacheDir != "" {
+		store, err := daemon.NewDiskCacheStore(o.cacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("new disk cache store: %w", err)
+		}
+
+		cacheOpts = append(cacheOpts, daemon.WithCacheStore(store))
+	}
+
+	o.daemonCache = daemon.NewCache(requester, cacheOpts...)
+
+	return daemon.NewClient(o.daemonCache, clientOpts...), nil
+}
+
+// RPCPaymentClient generates a signature identifying the client to nodes
+// offering rpc for credits: with the payment key supplied if any, or with a
+// throwaway one otherwise.
+//
+func (o *options) RPCPaymentClient() (string, error) {
+	if o.payer != nil {
+		return o.payer.Client()
+	}
+
+	key := monero.NewRPCPaymentKey()
+
+	if o.rpcPaymentKey != "" {
+		var err error
+
+		key, err = hex.DecodeString(o.rpcPaymentKey)
+		if err != nil {
+			return "", fmt.Errorf("decode rpc payment key: %w", err)
+		}
+	}
+
+	return monero.RPCPaymentSignature(key, time.Now())
 }
 
 // WalletClient instantiates a new wallet RPC client based on the options
@@ -88,20 +232,117 @@ func (o *options) WalletClient() (*wallet.Client, error) {
 		return nil, fmt.Errorf("new httpclient: %w", err)
 	}
 
-	client, err := rpc.NewClient(o.address, rpc.WithHTTPClient(httpClient))
+	if len(o.addresses) != 1 {
+		return nil, fmt.Errorf("expected a single wallet address, got %d",
+			len(o.addresses))
+	}
+
+	client, err := rpc.NewClient(o.addresses[0], o.clientOptions(httpClient)...)
 	if err != nil {
 		return nil, fmt.Errorf("new daemon client for '%s': %w",
-			o.address, err,
+			o.addresses[0], err,
 		)
 	}
 
 	return wallet.NewClient(client), nil
 }
 
+// clientOptions gathers the options to instantiate rpc clients with.
+//
+func (o *options) clientOptions(httpClient *http.Client) []rpc.ClientOption {
+	opts := []rpc.ClientOption{rpc.WithHTTPClient(httpClient)}
+
+	if o.maxAttempts > 1 {
+		policy := rpc.DefaultRetryPolicy
+		policy.MaxAttempts = o.maxAttempts
+
+		opts = append(opts, rpc.WithRetryPolicy(policy))
+	}
+
+	if interceptors := o.clientInterceptors(); len(interceptors) > 0 {
+		opts = append(opts, rpc.WithInterceptors(interceptors...))
+	}
+
+	return opts
+}
+
+// clientInterceptors builds (once) the interceptors that all calls should go
+// through according to the options filled.
+//
+func (o *options) clientInterceptors() []rpc.Interceptor {
+	if o.interceptors != nil {
+		return o.interceptors
+	}
+
+	o.interceptors = []rpc.Interceptor{}
+
+	if o.rateLimit > 0 {
+		o.interceptors = append(o.interceptors,
+			rpc.NewRateLimiter(o.rateLimit, o.rateBurst))
+	}
+
+	if o.metricsFile != "" {
+		o.metrics = rpc.NewMetrics()
+		o.interceptors = append(o.interceptors, o.metrics.Interceptor())
+	}
+
+	if o.logCalls {
+		o.interceptors = append(o.interceptors,
+			rpc.NewLoggingInterceptor(os.Stderr))
+	}
+
+	return o.interceptors
+}
+
+// finish wraps up once a command has run, reporting what's been gathered
+// about the calls made.
+//
+func (o *options) finish(_ *cobra.Command, _ []string) error {
+	if o.Verbose && o.daemonCache != nil {
+		stats := o.daemonCache.Stats()
+
+		fmt.Fprintf(os.Stderr, "cache: hits=%d misses=%d stores=%d invalidations=%d\n",
+			stats.Hits, stats.Misses, stats.Stores, stats.Invalidations)
+	}
+
+	if o.Verbose && o.payer != nil {
+		credits, topHash := o.payer.Credits()
+
+		fmt.Fprintf(os.Stderr, "rpc payment: credits=%d top_hash=%s\n",
+			credits, topHash)
+	}
+
+	return o.writeMetrics()
+}
+
+// writeMetrics writes the metrics gathered (if any) to the metrics file in
+// Prometheus' text exposition format.
+//
+func (o *options) writeMetrics() error {
+	if o.metrics == nil {
+		return nil
+	}
+
+	f, err := os.Create(o.metricsFile)
+	if err != nil {
+		return fmt.Errorf("create '%s': %w", o.metricsFile, err)
+	}
+
+	defer f.Close()
+
+	if _, err := o.metrics.WriteTo(f); err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+
+	return nil
+}
+
 // Bind binds the flags defined by `options` to a `cobra` command so that they
 // can be filled either via comand arguments or environment variables.
 //
 func Bind(cmd *cobra.Command) {
+	cmd.PersistentPostRunE = RootOpts.finish
+
 	cmd.PersistentFlags().BoolVarP(&RootOpts.Verbose,
 		"verbose", "v",
 		false,
@@ -113,12 +354,87 @@ func Bind(cmd *cobra.Command) {
 		"whether addresses should be shortened when displaying "+
 			"pretty results")
 
-	cmd.PersistentFlags().StringVarP(&RootOpts.address,
+	cmd.PersistentFlags().StringSliceVarP(&RootOpts.addresses,
 		"address", "a",
-		"http://localhost:18081",
-		"full address of the monero node to reach out to "+
+		[]string{"http://localhost:18081"},
+		"full address of the monero node to reach out to, or a "+
+			"comma-separated list of them to spread calls across "+
 			"[MONERO_ADDRESS]")
 
+	cmd.PersistentFlags().StringVar(&RootOpts.strategy,
+		"strategy",
+		string(daemon.StrategyPrimaryBackup),
+		"how to pick the node to reach out to when multiple "+
+			"addresses are supplied "+strategyOptions())
+
+	cmd.PersistentFlags().IntVar(&RootOpts.maxAttempts,
+		"max-attempts",
+		1,
+		"max number of times to attempt read-only calls that failed "+
+			"due to transient errors")
+
+	cmd.PersistentFlags().BoolVar(&RootOpts.cache,
+		"cache",
+		false,
+		"cache (in memory) block headers, blocks, transactions and "+
+			"outputs that are deep enough in the chain")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.cacheDir,
+		"cache-dir",
+		"",
+		"directory to cache block headers, blocks, transactions and "+
+			"outputs to, reusing them across invocations")
+
+	cmd.PersistentFlags().Uint64Var(&RootOpts.cacheConfirmations,
+		"cache-confirmations",
+		daemon.DefaultCacheConfirmations,
+		"number of blocks on top of a block for data from it to be "+
+			"cached")
+
+	cmd.PersistentFlags().BoolVar(&RootOpts.verify,
+		"verify",
+		false,
+		"recompute the hashes of the transactions and blocks retrieved, "+
+			"failing if they don't match what the node claims")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.rpcPaymentKey,
+		"rpc-payment-key",
+		"",
+		"hex-encoded private key identifying this client to nodes "+
+			"offering rpc for credits, paying for calls with them")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.rpcPaymentPolicy,
+		"rpc-payment-policy",
+		string(daemon.PaymentPolicyFail),
+		"what to do when a node requires payment for a call "+
+			"("+string(daemon.PaymentPolicyFail)+","+
+			string(daemon.PaymentPolicyWait)+"; '"+
+			string(daemon.PaymentPolicyMine)+"' needs a nonce solver, "+
+			"which the cli doesn't have)")
+
+	cmd.PersistentFlags().BoolVar(&RootOpts.logCalls,
+		"log-calls",
+		false,
+		"log every rpc call made (method, duration, error) to stderr "+
+			"in logfmt")
+
+	cmd.PersistentFlags().Float64Var(&RootOpts.rateLimit,
+		"rate-limit",
+		0,
+		"max number of rpc calls per second to make (0 for no limit)")
+
+	cmd.PersistentFlags().IntVar(&RootOpts.rateBurst,
+		"rate-burst",
+		1,
+		"number of rpc calls that can be made at once over the "+
+			"rate limit")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.metricsFile,
+		"metrics-file",
+		"",
+		"file to write per-method rpc call metrics to (in prometheus' "+
+			"text format) once the command finishes")
+
 	cmd.PersistentFlags().StringVarP(&RootOpts.Username,
 		"username", "u",
 		"",
@@ -149,8 +465,31 @@ func Bind(cmd *cobra.Command) {
 		"",
 		"certificate authority to load")
 
+	cmd.PersistentFlags().StringVar(&RootOpts.RecordCassette,
+		"record",
+		"",
+		"record http requests and responses to a cassette file "+
+			"(with credentials redacted)")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.ReplayCassette,
+		"replay",
+		"",
+		"serve responses from a cassette file previously recorded "+
+			"with --record (against the same addresses) instead of "+
+			"reaching out to a node")
+
 	cmd.PersistentFlags().DurationVar(&RootOpts.RequestTimeout,
 		"request-timeout",
 		1*time.Minute,
 		"max wait time until considering the request a failure")
 }
+
+func strategyOptions() string {
+	strs := []string{}
+
+	for _, strategy := range daemon.Strategies {
+		strs = append(strs, string(strategy))
+	}
+
+	return "(" + strings.Join(strs, ",") + ")"
+}
@@ -0,0 +1,383 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// redacted is the value that sensitive header values are replaced with before
+// being written to a cassette.
+//
+const redacted = "REDACTED"
+
+// bodyEncodingBase64 indicates that a body (e.g., from a `.bin` endpoint) is
+// not valid UTF-8 and has thus been stored base64-encoded.
+//
+const bodyEncodingBase64 = "base64"
+
+// ErrInteractionNotFound indicates that a replaying transport has been asked
+// to serve a request that has not been recorded in its cassette.
+//
+var ErrInteractionNotFound = errors.New("interaction not found in cassette")
+
+// sensitiveHeaders are the headers that never make it to a cassette as they
+// might carry credentials (or material derived from them).
+//
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Cassette is a set of HTTP interactions (request/response pairs) captured by
+// a `RecordTransport`, which can later be served back by a
+// `ReplayTransport`.
+//
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single request/response pair.
+//
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded form of an `http.Request`.
+//
+type RecordedRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// RecordedResponse is the recorded form of an `http.Response`.
+//
+type RecordedResponse struct {
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+}
+
+// LoadCassette reads a cassette previously saved to the file at `fpath`.
+//
+func LoadCassette(fpath string) (*Cassette, error) {
+	b, err := os.ReadFile(fpath)
+	if err != nil {
+		return nil, fmt.Errorf("read file '%s': %w", fpath, err)
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(b, cassette); err != nil {
+		return nil, fmt.Errorf("unmarshal '%s': %w", fpath, err)
+	}
+
+	return cassette, nil
+}
+
+// Save writes the cassette to the file at `fpath`, replacing it if it already
+// exists.
+//
+func (c *Cassette) Save(fpath string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal indent: %w", err)
+	}
+
+	if err := os.WriteFile(fpath, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write file '%s': %w", fpath, err)
+	}
+
+	return nil
+}
+
+// RecordTransport implements the `net/http.RoundTripper` interface wrapping
+// another RoundTripper, recording to a cassette file every request and
+// response that passes through it.
+//
+// Sensitive headers (like `Authorization`) are redacted before anything gets
+// written, as well as credentials embedded in the URL.
+//
+type RecordTransport struct {
+	R http.RoundTripper
+
+	fpath    string
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// NewRecordTransport instantiates a new RecordTransport that writes the
+// interactions it sees to the cassette file at `fpath`.
+//
+func NewRecordTransport(fpath string, rt http.RoundTripper) *RecordTransport {
+	return &RecordTransport{
+		R:        rt,
+		fpath:    fpath,
+		cassette: &Cassette{},
+	}
+}
+
+// RoundTrip passes the request down to the wrapped RoundTripper, recording
+// both the request and the response it gets back.
+//
+// The cassette file is rewritten after every interaction so that short-lived
+// processes (like a single CLI command) don't need to explicitly flush it.
+//
+func (t *RecordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("drain request body: %w", err)
+	}
+
+	resp, err := t.R.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("drain response body: %w", err)
+	}
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    redactURL(req),
+			Header: redactHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+		},
+	}
+
+	interaction.Request.Body, interaction.Request.BodyEncoding = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = encodeBody(respBody)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	if err := t.cassette.Save(t.fpath); err != nil {
+		return nil, fmt.Errorf("save cassette: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport implements the `net/http.RoundTripper` interface serving
+// responses out of a cassette rather than reaching out to a server.
+//
+// Requests are matched against the recorded ones by their HTTP method, host,
+// endpoint and, for JSON-RPC calls, the JSON-RPC method and params (ignoring
+// the request ID and how the params are formatted). Other bodies (e.g., for
+// `.bin` endpoints) must match byte for byte.
+//
+// Matching on the host keeps apart the responses of different nodes (e.g.,
+// behind a `daemon.Pool`), but also means that a cassette can only be
+// replayed against the addresses it was recorded with.
+//
+// When the same request has been recorded more than once, the responses are
+// served in the order they have been recorded, with the last one being
+// repeated once all have been served.
+//
+type ReplayTransport struct {
+	mu      sync.Mutex
+	entries map[string][]*Interaction
+	served  map[string]int
+}
+
+// NewReplayTransport instantiates a new ReplayTransport serving the
+// interactions recorded in the cassette file at `fpath`.
+//
+func NewReplayTransport(fpath string) (*ReplayTransport, error) {
+	cassette, err := LoadCassette(fpath)
+	if err != nil {
+		return nil, fmt.Errorf("load cassette: %w", err)
+	}
+
+	return NewReplayTransportFromCassette(cassette)
+}
+
+// NewReplayTransportFromCassette instantiates a new ReplayTransport serving
+// the interactions from an already loaded cassette.
+//
+func NewReplayTransportFromCassette(cassette *Cassette) (*ReplayTransport, error) {
+	t := &ReplayTransport{
+		entries: map[string][]*Interaction{},
+		served:  map[string]int{},
+	}
+
+	for idx, interaction := range cassette.Interactions {
+		body, err := decodeBody(interaction.Request.Body, interaction.Request.BodyEncoding)
+		if err != nil {
+			return nil, fmt.Errorf("interaction %d: decode request body: %w", idx, err)
+		}
+
+		u, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("interaction %d: parse url '%s': %w",
+				idx, interaction.Request.URL, err)
+		}
+
+		key := matchKey(interaction.Request.Method, u.Host, u.Path, body)
+		t.entries[key] = append(t.entries[key], interaction)
+	}
+
+	return t, nil
+}
+
+// RoundTrip serves the recorded response matching the request, failing with
+// `ErrInteractionNotFound` if there's none.
+//
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("drain request body: %w", err)
+	}
+
+	key := matchKey(req.Method, req.URL.Host, req.URL.Path, body)
+
+	t.mu.Lock()
+	interactions := t.entries[key]
+	if len(interactions) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("%s %s%s: %w", req.Method, req.URL.Host,
+			req.URL.Path, ErrInteractionNotFound)
+	}
+
+	idx := t.served[key]
+	if idx < len(interactions)-1 {
+		t.served[key]++
+	}
+	t.mu.Unlock()
+
+	recorded := interactions[idx].Response
+
+	respBody, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("decode response body: %w", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// matchKey computes the key under which a request is matched against the
+// recorded ones.
+//
+func matchKey(method, host, path string, body []byte) string {
+	return method + " " + host + path + " " + canonicalBody(body)
+}
+
+// canonicalBody gives a representation of a request body that doesn't depend
+// on formatting nor on JSON-RPC request IDs.
+//
+func canonicalBody(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return base64.StdEncoding.EncodeToString(body)
+	}
+
+	// the ID of a single call is meaningless, but not for batches: there,
+	// IDs are what correlate calls and results.
+	//
+	if envelope, ok := v.(map[string]interface{}); ok {
+		delete(envelope, "id")
+	}
+
+	// maps get marshaled with their keys sorted, which is what gives us a
+	// canonical form.
+	//
+	b, err := json.Marshal(v)
+	if err != nil {
+		return base64.StdEncoding.EncodeToString(body)
+	}
+
+	return string(b)
+}
+
+// drainBody reads the whole body, replacing it with one that can still be
+// read by whoever's next.
+//
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, fmt.Errorf("read all: %w", err)
+	}
+
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+func encodeBody(b []byte) (string, string) {
+	if utf8.Valid(b) {
+		return string(b), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(b), bodyEncodingBase64
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case bodyEncodingBase64:
+		b, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, fmt.Errorf("base64 decode: %w", err)
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown body encoding '%s'", encoding)
+	}
+}
+
+func redactHeader(header http.Header) http.Header {
+	res := header.Clone()
+
+	for _, name := range sensitiveHeaders {
+		if _, found := res[name]; found {
+			res.Set(name, redacted)
+		}
+	}
+
+	return res
+}
+
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+
+	return u.String()
+}
@@ -0,0 +1,212 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/jjsteel/go-monero/pkg/http"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestCassette(t *testing.T) {
+	spec.Run(t, "Cassette", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx      = context.Background()
+			cassette string
+			address  string
+		)
+
+		it.Before(func() {
+			cassette = filepath.Join(t.TempDir(), "cassette.json")
+		})
+
+		newClient := func(address string, rt http.RoundTripper) *daemon.Client {
+			c, err := rpc.NewClient(address,
+				rpc.WithHTTPClient(&http.Client{Transport: rt}),
+			)
+			require.NoError(t, err)
+
+			return daemon.NewClient(c)
+		}
+
+		when("recording daemon traffic", func() {
+			var info *daemon.GetInfoResult
+
+			it.Before(func() {
+				server := daemontest.NewServer(
+					daemontest.WithBlocks(5),
+					daemontest.WithDigestAuth("user", "pass"),
+				)
+				defer server.Close()
+
+				address = server.URL
+
+				httpClient := server.Client()
+				httpClient.Transport = mhttp.NewRecordTransport(cassette,
+					mhttp.NewDigestAuthTransport(
+						"user", "pass", httpClient.Transport,
+					),
+				)
+
+				c, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(httpClient))
+				require.NoError(t, err)
+
+				client := daemon.NewClient(c)
+
+				info, err = client.GetInfo(ctx)
+				require.NoError(t, err)
+
+				_, err = client.GetBlockHeaderByHeight(ctx, 2)
+				require.NoError(t, err)
+			})
+
+			it("captures only the authenticated exchanges", func() {
+				recorded, err := mhttp.LoadCassette(cassette)
+				require.NoError(t, err)
+				require.Len(t, recorded.Interactions, 2)
+
+				for _, interaction := range recorded.Interactions {
+					assert.Equal(t, http.StatusOK, interaction.Response.StatusCode)
+				}
+			})
+
+			it("replays the calls offline", func() {
+				replay, err := mhttp.NewReplayTransport(cassette)
+				require.NoError(t, err)
+
+				client := newClient(address, replay)
+
+				replayed, err := client.GetInfo(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, info, replayed)
+
+				header, err := client.GetBlockHeaderByHeight(ctx, 2)
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, header.BlockHeader.Height)
+			})
+
+			it("fails for calls not recorded", func() {
+				replay, err := mhttp.NewReplayTransport(cassette)
+				require.NoError(t, err)
+
+				_, err = newClient(address, replay).GetBlockHeaderByHeight(ctx, 3)
+				assert.True(t, errors.Is(err, mhttp.ErrInteractionNotFound))
+			})
+
+			it("fails for calls to other nodes", func() {
+				replay, err := mhttp.NewReplayTransport(cassette)
+				require.NoError(t, err)
+
+				_, err = newClient("http://node.invalid:18081", replay).GetInfo(ctx)
+				assert.True(t, errors.Is(err, mhttp.ErrInteractionNotFound))
+			})
+		})
+
+		when("recording traffic from several nodes", func() {
+			it("replays each node's own responses", func() {
+				servers := []*daemontest.Server{
+					daemontest.NewServer(daemontest.WithBlocks(5)),
+					daemontest.NewServer(daemontest.WithBlocks(8)),
+				}
+
+				record := mhttp.NewRecordTransport(cassette, http.DefaultTransport)
+
+				for _, server := range servers {
+					_, err := newClient(server.URL, record).GetInfo(ctx)
+					require.NoError(t, err)
+
+					server.Close()
+				}
+
+				replay, err := mhttp.NewReplayTransport(cassette)
+				require.NoError(t, err)
+
+				for idx, expected := range []uint64{5, 8} {
+					info, err := newClient(servers[idx].URL, replay).GetInfo(ctx)
+					require.NoError(t, err)
+					assert.Equal(t, expected, info.Height)
+				}
+			})
+		})
+
+		when("recording raw http traffic", func() {
+			var (
+				server *httptest.Server
+				served int
+			)
+
+			it.Before(func() {
+				served = 0
+				server = httptest.NewServer(http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						served++
+						w.Header().Set("Set-Cookie", "session=secret")
+						fmt.Fprintf(w, "\xff\x00%d", served)
+					},
+				))
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			do := func(rt http.RoundTripper, body string) []byte {
+				req, err := http.NewRequest(http.MethodPost,
+					server.URL+"/get_o_indexes.bin", bytes.NewBufferString(body))
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+				resp, err := rt.RoundTrip(req)
+				require.NoError(t, err)
+				defer resp.Body.Close()
+
+				b, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+
+				return b
+			}
+
+			it("redacts credentials", func() {
+				do(mhttp.NewRecordTransport(cassette, server.Client().Transport), "a")
+
+				b, err := os.ReadFile(cassette)
+				require.NoError(t, err)
+				assert.NotContains(t, string(b), "dXNlcjpwYXNz")
+				assert.NotContains(t, string(b), "secret")
+				assert.Contains(t, string(b), "REDACTED")
+			})
+
+			it("replays binary bodies in the order recorded", func() {
+				record := mhttp.NewRecordTransport(cassette, server.Client().Transport)
+				assert.Equal(t, []byte("\xff\x001"), do(record, "a"))
+				assert.Equal(t, []byte("\xff\x002"), do(record, "a"))
+				assert.Equal(t, []byte("\xff\x003"), do(record, "b"))
+
+				replay, err := mhttp.NewReplayTransport(cassette)
+				require.NoError(t, err)
+
+				assert.Equal(t, []byte("\xff\x003"), do(replay, "b"))
+				assert.Equal(t, []byte("\xff\x001"), do(replay, "a"))
+				assert.Equal(t, []byte("\xff\x002"), do(replay, "a"))
+				assert.Equal(t, []byte("\xff\x002"), do(replay, "a"))
+				assert.Equal(t, 3, served)
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
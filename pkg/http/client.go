@@ -61,6 +61,16 @@ type ClientConfig struct {
 	// body of every request and response will still be cleartext.
 	//
 	Password string
+
+	// RecordCassette is the path to a cassette file to record every
+	// request and response to (see `RecordTransport`).
+	//
+	RecordCassette string
+
+	// ReplayCassette is the path to a cassette file to serve responses
+	// from instead of reaching out to a server (see `ReplayTransport`).
+	//
+	ReplayCassette string
 }
 
 func (c ClientConfig) Validate() error {
@@ -82,6 +92,10 @@ func (c ClientConfig) Validate() error {
 		return fmt.Errorf("password specified but username not")
 	}
 
+	if c.RecordCassette != "" && c.ReplayCassette != "" {
+		return fmt.Errorf("can't both record and replay a cassette")
+	}
+
 	return nil
 }
 
@@ -122,6 +136,15 @@ func NewClient(cfg ClientConfig) (*http.Client, error) {
 		Transport: transport,
 	}
 
+	if cfg.ReplayCassette != "" {
+		replay, err := NewReplayTransport(cfg.ReplayCassette)
+		if err != nil {
+			return nil, fmt.Errorf("new replay transport: %w", err)
+		}
+
+		client.Transport = replay
+	}
+
 	if cfg.Verbose {
 		client.Transport = NewDumpTransport(client.Transport)
 	}
@@ -133,6 +156,15 @@ func NewClient(cfg ClientConfig) (*http.Client, error) {
 		)
 	}
 
+	// recording happens on top of digest auth so that only the final
+	// (authenticated) exchange gets captured, not the challenge.
+	//
+	if cfg.RecordCassette != "" {
+		client.Transport = NewRecordTransport(
+			cfg.RecordCassette, client.Transport,
+		)
+	}
+
 	return client, nil
 }
 
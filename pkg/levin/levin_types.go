@@ -72,3 +72,54 @@ func (v String) Bytes() []byte {
 
 	return append(b, append(varInB, []byte(v)...)...)
 }
+
+type Int64 int64
+
+func (v Int64) Bytes() []byte {
+	b := []byte{
+		TypeInt64,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	binary.LittleEndian.PutUint64(b[1:], uint64(v))
+
+	return b
+}
+
+type Bool bool
+
+func (v Bool) Bytes() []byte {
+	if v {
+		return []byte{TypeBool, 0x01}
+	}
+
+	return []byte{TypeBool, 0x00}
+}
+
+// Array is a sequence of values all of the same type `Type` (e.g.,
+// `TypeUint64` or `TypeObject`).
+//
+type Array struct {
+	Type   byte
+	Values []Serializable
+}
+
+func (v Array) Bytes() []byte {
+	b := []byte{v.Type | FlagArray}
+
+	varInB, err := VarIn(len(v.Values))
+	if err != nil {
+		panic(fmt.Errorf("varin '%d': %w", len(v.Values), err))
+	}
+
+	b = append(b, varInB...)
+	for _, value := range v.Values {
+		// values within an array are not individually tagged with
+		// their type.
+		//
+		b = append(b, value.Bytes()[1:]...)
+	}
+
+	return b
+}
@@ -82,22 +82,67 @@ func (e Entry) Bytes() []byte {
 	return nil
 }
 
+func (e Entry) Int64() int64 {
+	v, ok := e.Value.(int64)
+	if !ok {
+		panic(fmt.Errorf("interface couldnt be casted to int64"))
+	}
+
+	return v
+}
+
+func (e Entry) Bool() bool {
+	v, ok := e.Value.(bool)
+	if !ok {
+		panic(fmt.Errorf("interface couldnt be casted to bool"))
+	}
+
+	return v
+}
+
 type Entries []Entry
 
 func (e Entries) Bytes() []byte {
 	return nil
 }
 
+// Get retrieves the entry named `name`, if present.
+//
+func (e Entries) Get(name string) (Entry, bool) {
+	for _, entry := range e {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
 type PortableStorage struct {
 	Entries Entries
 }
 
-func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
+// NewPortableStorageFromBytes parses a portable storage payload (the format
+// used by both levin and the daemon's binary `.bin` endpoints), reporting
+// malformed ones as errors.
+//
+func NewPortableStorageFromBytes(bytes []byte) (ps *PortableStorage, err error) {
 	var (
 		size = 0
 		idx  = 0
 	)
 
+	defer func() {
+		if r := recover(); r != nil {
+			ps, err = nil, fmt.Errorf("malformed portable storage: %v", r)
+		}
+	}()
+
+	// limit the capacity so that reading past the end panics (and is thus
+	// reported as an error) rather than silently picking up stray bytes.
+	//
+	bytes = bytes[:len(bytes):len(bytes)]
+
 	{ // sig-a
 		size = 4
 
@@ -115,6 +160,11 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 
 	{ // sig-b
 		size = 4
+
+		if len(bytes[idx:]) < size {
+			return nil, fmt.Errorf("sig-b out of bounds")
+		}
+
 		sig := binary.LittleEndian.Uint32(bytes[idx : idx+size])
 		idx += size
 
@@ -125,6 +175,11 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 
 	{ // format ver
 		size = 1
+
+		if len(bytes[idx:]) < size {
+			return nil, fmt.Errorf("format ver out of bounds")
+		}
+
 		version := bytes[idx]
 		idx += size
 
@@ -133,7 +188,7 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 		}
 	}
 
-	ps := &PortableStorage{}
+	ps = &PortableStorage{}
 
 	_, ps.Entries = ReadObject(bytes[idx:])
 
@@ -147,6 +202,10 @@ func ReadString(bytes []byte) (int, string) {
 
 func ReadObject(bytes []byte) (int, Entries) {
 	idx, i := ReadVarInt(bytes[:])
+	if i > len(bytes) {
+		panic(fmt.Errorf("object size %d out of bounds", i))
+	}
+
 	entries := make(Entries, i)
 
 	for iter := 0; iter < i; iter++ {
@@ -180,6 +239,10 @@ func ReadArray(ttype byte, bytes []byte) (int, Entries) {
 	n, i := ReadVarInt(bytes[idx:])
 	idx += n
 
+	if i > len(bytes) {
+		panic(fmt.Errorf("array size %d out of bounds", i))
+	}
+
 	entries := make(Entries, i)
 
 	for iter := 0; iter < i; iter++ {
@@ -260,15 +323,10 @@ func ReadVarInt(b []byte) (int, int) {
 	case 2:
 		return 4, int((binary.LittleEndian.Uint32(b[0:4])) >> 2)
 	case 3:
-		panic("int64 not supported") // TODO
-		// return int((binary.LittleEndian.Uint64(b[0:8])) >> 2)
-		//         '-> bad
+		return 8, int((binary.LittleEndian.Uint64(b[0:8])) >> 2)
 	default:
 		panic(fmt.Errorf("malformed sizemask: %+v", sizeMask))
 	}
-
-	return -1, -1
-
 }
 
 func (s *PortableStorage) Bytes() []byte {
@@ -6,6 +6,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/jjsteel/go-monero/pkg/levin"
 )
@@ -123,6 +124,13 @@ func TestPortableStorage(t *testing.T) {
 			assert.Equal(t, n, 4)
 			assert.Equal(t, v, 16384)
 		})
+
+		it("1073741824 <= i", func() {
+			b := []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}
+			n, v := levin.ReadVarInt(b)
+			assert.Equal(t, n, 8)
+			assert.Equal(t, v, 1<<30)
+		})
 	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
 
 	spec.Run(t, "VarrIn", func(t *testing.T, when spec.G, it spec.S) {
@@ -222,4 +230,74 @@ func TestPortableStorage(t *testing.T) {
 			}, ps.Bytes())
 		})
 	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "RoundTrip", func(t *testing.T, when spec.G, it spec.S) {
+		it("arrays, bools and blobs", func() {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{
+						Name: "heights",
+						Serializable: levin.Array{
+							Type: levin.TypeUint64,
+							Values: []levin.Serializable{
+								levin.Uint64(1), levin.Uint64(2),
+							},
+						},
+					},
+					{
+						Name:         "prune",
+						Serializable: levin.Bool(true),
+					},
+					{
+						Name: "outputs",
+						Serializable: levin.Array{
+							Type: levin.TypeObject,
+							Values: []levin.Serializable{
+								levin.Section{
+									Entries: []levin.Entry{
+										{
+											Name:         "blob",
+											Serializable: levin.String("\x00\x01"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			decoded, err := levin.NewPortableStorageFromBytes(ps.Bytes())
+			require.NoError(t, err)
+
+			heights, ok := decoded.Entries.Get("heights")
+			require.True(t, ok)
+			assert.Equal(t, levin.Entries{
+				{Value: uint64(1)}, {Value: uint64(2)},
+			}, heights.Entries())
+
+			prune, ok := decoded.Entries.Get("prune")
+			require.True(t, ok)
+			assert.True(t, prune.Bool())
+
+			outputs, ok := decoded.Entries.Get("outputs")
+			require.True(t, ok)
+			assert.Equal(t, levin.Entries{
+				{Value: levin.Entries{{Name: "blob", Value: "\x00\x01"}}},
+			}, outputs.Entries())
+		})
+
+		it("fails w/ truncated payload", func() {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{Name: "foo", Serializable: levin.String("bar")},
+				},
+			}
+
+			b := ps.Bytes()
+
+			_, err := levin.NewPortableStorageFromBytes(b[:len(b)-2])
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
 }
@@ -0,0 +1,242 @@
+package monero
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidAddressLength is the error that parsing an address whose
+	// decoded form doesn't have the size expected for its kind fails
+	// with.
+	//
+	ErrInvalidAddressLength = errors.New("invalid address length")
+
+	// ErrInvalidChecksum is the error that parsing an address whose
+	// checksum doesn't match its content fails with.
+	//
+	ErrInvalidChecksum = errors.New("invalid checksum")
+
+	// ErrUnknownAddressPrefix is the error that parsing an address whose
+	// prefix isn't the one of any kind of address of any network fails
+	// with.
+	//
+	ErrUnknownAddressPrefix = errors.New("unknown address prefix")
+
+	// ErrInvalidKey is the error that parsing an address with a public
+	// key that isn't a point of the curve fails with.
+	//
+	ErrInvalidKey = errors.New("invalid key")
+
+	// ErrInvalidPaymentID is the error that making an integrated address
+	// out of a payment ID that isn't a short (8-byte) one fails with.
+	//
+	ErrInvalidPaymentID = errors.New("invalid payment id")
+)
+
+// addressChecksumSize is the size of the checksum that closes the binary
+// form of an address: the first bytes of the keccak256 of what comes before.
+//
+const addressChecksumSize = 4
+
+// AddressKind denotes the kind of a Monero address.
+//
+type AddressKind string
+
+const (
+	// AddressStandard is the kind of primary addresses, those derived
+	// straight out of a seed.
+	//
+	AddressStandard AddressKind = "standard"
+
+	// AddressSubaddress is the kind of the addresses that a wallet
+	// derives out of its keys and an (account, index) pair.
+	//
+	AddressSubaddress AddressKind = "subaddress"
+
+	// AddressIntegrated is the kind of standard addresses that carry a
+	// (short) payment ID along.
+	//
+	AddressIntegrated AddressKind = "integrated"
+)
+
+// Address is a decoded Monero address.
+//
+type Address struct {
+	// Network is the network that the address is meant for (fakechain
+	// addresses being indistinguishable from mainnet ones).
+	//
+	Network Network
+
+	// Kind is the kind of the address.
+	//
+	Kind AddressKind
+
+	// PublicSpendKey is the public spend key of the address.
+	//
+	PublicSpendKey []byte
+
+	// PublicViewKey is the public view key of the address.
+	//
+	PublicViewKey []byte
+
+	// PaymentID is the (8-byte) payment ID of integrated addresses.
+	//
+	PaymentID []byte
+}
+
+// ParseAddress decodes a base58-encoded address, validating its checksum
+// and keys.
+//
+// It fails with `ErrInvalidBase58`, `ErrInvalidChecksum`,
+// `ErrUnknownAddressPrefix`, `ErrInvalidAddressLength` or `ErrInvalidKey`
+// depending on what's wrong with it.
+//
+func ParseAddress(address string) (*Address, error) {
+	raw, err := DecodeBase58(address)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	if len(raw) <= addressChecksumSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidAddressLength, len(raw))
+	}
+
+	data, checksum := raw[:len(raw)-addressChecksumSize], raw[len(raw)-addressChecksumSize:]
+	if !bytes.Equal(keccak256(data)[:addressChecksumSize], checksum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	tag, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: invalid varint", ErrUnknownAddressPrefix)
+	}
+
+	network, kind, found := addressPrefix(tag)
+	if !found {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownAddressPrefix, tag)
+	}
+
+	body := data[n:]
+
+	size := 2 * KeySize
+	if kind == AddressIntegrated {
+		size += EncryptedPaymentIDSize
+	}
+
+	if len(body) != size {
+		return nil, fmt.Errorf("%w: %d bytes for a %s address, expected %d",
+			ErrInvalidAddressLength, len(body), kind, size)
+	}
+
+	addr := &Address{
+		Network:        network,
+		Kind:           kind,
+		PublicSpendKey: body[:KeySize],
+		PublicViewKey:  body[KeySize : 2*KeySize],
+	}
+
+	if kind == AddressIntegrated {
+		addr.PaymentID = body[2*KeySize:]
+	}
+
+	if !isPoint(addr.PublicSpendKey) {
+		return nil, fmt.Errorf("%w: public spend key", ErrInvalidKey)
+	}
+
+	if !isPoint(addr.PublicViewKey) {
+		return nil, fmt.Errorf("%w: public view key", ErrInvalidKey)
+	}
+
+	return addr, nil
+}
+
+// String gives the base58-encoded form of the address.
+//
+func (a *Address) String() string {
+	data := append([]byte{}, a.Network.addressPrefix(a.Kind)...)
+	data = append(data, a.PublicSpendKey...)
+	data = append(data, a.PublicViewKey...)
+
+	if a.Kind == AddressIntegrated {
+		data = append(data, a.PaymentID...)
+	}
+
+	data = append(data, keccak256(data)[:addressChecksumSize]...)
+
+	return EncodeBase58(data)
+}
+
+// Integrated gives the integrated address made of the standard address and
+// the (8-byte) payment ID `paymentID`.
+//
+func (a *Address) Integrated(paymentID []byte) (*Address, error) {
+	if a.Kind != AddressStandard {
+		return nil, fmt.Errorf("can't integrate a payment id into a %s address", a.Kind)
+	}
+
+	if len(paymentID) != EncryptedPaymentIDSize {
+		return nil, fmt.Errorf("%w: %d bytes, expected %d",
+			ErrInvalidPaymentID, len(paymentID), EncryptedPaymentIDSize)
+	}
+
+	return &Address{
+		Network:        a.Network,
+		Kind:           AddressIntegrated,
+		PublicSpendKey: a.PublicSpendKey,
+		PublicViewKey:  a.PublicViewKey,
+		PaymentID:      append([]byte{}, paymentID...),
+	}, nil
+}
+
+// Standard gives the standard address that an integrated address is made
+// of (its payment ID being in `PaymentID`), or the address itself if it's
+// not an integrated one.
+//
+func (a *Address) Standard() *Address {
+	if a.Kind != AddressIntegrated {
+		return a
+	}
+
+	return &Address{
+		Network:        a.Network,
+		Kind:           AddressStandard,
+		PublicSpendKey: a.PublicSpendKey,
+		PublicViewKey:  a.PublicViewKey,
+	}
+}
+
+func (n Network) addressPrefix(kind AddressKind) []byte {
+	switch kind {
+	case AddressStandard:
+		return n.PublicAddressBase58Prefix()
+	case AddressSubaddress:
+		return n.PublicSubaddressBase58Prefix()
+	case AddressIntegrated:
+		return n.PublicIntegratedAddressBase58Prefix()
+	}
+
+	panic(fmt.Errorf("'%s' is not a valid address kind", kind))
+}
+
+// addressPrefix finds out the network and kind of address that `tag` is the
+// prefix of.
+//
+func addressPrefix(tag uint64) (Network, AddressKind, bool) {
+	for _, network := range []Network{
+		NetworkMainnet, NetworkTestnet, NetworkStagenet,
+	} {
+		for _, kind := range []AddressKind{
+			AddressStandard, AddressSubaddress, AddressIntegrated,
+		} {
+			prefix, _ := binary.Uvarint(network.addressPrefix(kind))
+			if prefix == tag {
+				return network, kind, true
+			}
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,200 @@
+package monero_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func TestBase58(t *testing.T) {
+	for _, tc := range []struct {
+		data    string
+		encoded string
+	}{
+		{"", ""},
+		{"00", "11"},
+		{"ff", "5Q"},
+		{"0000000000000000", "11111111111"},
+		{"ffffffffffffffff", "jpXCZedGfVQ"},
+		{"06156013762879f7ffffffffff", "22222222222VtB5VXc"},
+		{"ffffffffffffffff00", "jpXCZedGfVQ11"},
+	} {
+		data, err := hex.DecodeString(tc.data)
+		require.NoError(t, err)
+
+		assert.Equal(t, tc.encoded, monero.EncodeBase58(data), tc.data)
+
+		decoded, err := monero.DecodeBase58(tc.encoded)
+		require.NoError(t, err, tc.encoded)
+		assert.Equal(t, tc.data, hex.EncodeToString(decoded))
+	}
+
+	for _, invalid := range []string{
+		"1",            // no block is encoded to a single character
+		"1111",         // nor to four
+		"0O",           // not in the alphabet
+		"5R",           // overflows a single byte
+		"jpXCZedGfVR",  // overflows 8 bytes
+		"zzzzzzzzzzz",  // overflows 64 bits
+		"11111111111I", // invalid character in the last block
+	} {
+		_, err := monero.DecodeBase58(invalid)
+		assert.ErrorIs(t, err, monero.ErrInvalidBase58, invalid)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		address        string
+		network        monero.Network
+		kind           monero.AddressKind
+		publicSpendKey string
+		publicViewKey  string
+		paymentID      string
+	}{
+		{name: "mainnet",
+			address:        "46w3n5EGhBeZkYmKvQRsd8UK9GhvcbYWQDobJape3NLMMFEjFZnJ3CnRmeKspubQGiP8iMTwFEX2QiBsjUkjKT4SSPd3fKp",
+			network:        monero.NetworkMainnet,
+			kind:           monero.AddressStandard,
+			publicSpendKey: "8c1a9d5ff5aaf1c3cdeb2a1be62f07a34ae6b15fe47a254c8bc240f348271679",
+			publicViewKey:  "0a29b163e392eb9416a52907fd7d3b84530f8d02ff70b1f63e72fdcb54cf7fe1",
+		},
+		{name: "mainnet, padding in the last block",
+			address:        "44grjkXtDHJVbZgtU1UKnrNXidcHfZ3HWToU5WjR3KgHMjgwrYLjXC6i5vm3HCp4vnBfYaNEyNiuZVwqtHD2SenS1JBRyco",
+			network:        monero.NetworkMainnet,
+			kind:           monero.AddressStandard,
+			publicSpendKey: "50defe92d88b19aaf6bf66f061dd4380b79866a4122b25a03bceb571767dbe7b",
+			publicViewKey:  "f8f6f28283921bf5a17f0bcf4306233fc25ce9b6276154ad0de22aebc5c67702",
+		},
+		{name: "testnet",
+			address:        "9xYZvCDf6aFdLd7Qawg5XHZitWLKoeFvcLHfe5GxsGCFLbXSWeQNKciXX9YN4T7nPPLcpqYLUdrFiY77nQYeH9RuK9bogZJ",
+			network:        monero.NetworkTestnet,
+			kind:           monero.AddressStandard,
+			publicSpendKey: "8de9cce254e60cd940abf6c77ef344c3a21fad74320e45734fbfcd5870e5c875",
+			publicViewKey:  "27024b45150037b677418fcf11ba9675494ffdf994f329b9f7a8f8402b7934a0",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := monero.ParseAddress(tc.address)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.network, addr.Network)
+			assert.Equal(t, tc.kind, addr.Kind)
+			assert.Equal(t, tc.publicSpendKey, hex.EncodeToString(addr.PublicSpendKey))
+			assert.Equal(t, tc.publicViewKey, hex.EncodeToString(addr.PublicViewKey))
+			assert.Equal(t, tc.paymentID, hex.EncodeToString(addr.PaymentID))
+
+			assert.Equal(t, tc.address, addr.String())
+		})
+	}
+}
+
+func TestAddressRoundTrip(t *testing.T) {
+	seed := monero.NewSeed(key(1))
+
+	for _, network := range []monero.Network{
+		monero.NetworkMainnet, monero.NetworkTestnet, monero.NetworkStagenet,
+	} {
+		for _, kind := range []monero.AddressKind{
+			monero.AddressStandard, monero.AddressSubaddress, monero.AddressIntegrated,
+		} {
+			addr := &monero.Address{
+				Network:        network,
+				Kind:           kind,
+				PublicSpendKey: seed.PublicSpendKey(),
+				PublicViewKey:  seed.PublicViewKey(),
+			}
+
+			if kind == monero.AddressIntegrated {
+				addr.PaymentID = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+			}
+
+			parsed, err := monero.ParseAddress(addr.String())
+			require.NoError(t, err, "%s %s", network, kind)
+			assert.Equal(t, addr, parsed, "%s %s", network, kind)
+		}
+	}
+
+	primary, err := monero.ParseAddress(seed.PrimaryAddress())
+	require.NoError(t, err)
+	assert.Equal(t, seed.PublicSpendKey(), primary.PublicSpendKey)
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	seed := monero.NewSeed(key(1))
+
+	encode := func(prefix byte, body ...[]byte) string {
+		data := append([]byte{prefix}, bytes.Join(body, nil)...)
+		return monero.EncodeBase58(append(data, keccak(data)[:4]...))
+	}
+
+	valid := seed.PrimaryAddress()
+
+	// there's no point of the curve with y = 2.
+	//
+	notAPoint := make([]byte, monero.KeySize)
+	notAPoint[0] = 2
+
+	for _, tc := range []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{name: "empty",
+			address: "",
+			err:     monero.ErrInvalidAddressLength,
+		},
+		{name: "invalid character",
+			address: valid[:10] + "0" + valid[11:],
+			err:     monero.ErrInvalidBase58,
+		},
+		{name: "truncated",
+			address: valid[:len(valid)-1],
+			err:     monero.ErrInvalidBase58,
+		},
+		{name: "typo",
+			address: valid[:20] + string(valid[20]^1) + valid[21:],
+			err:     monero.ErrInvalidChecksum,
+		},
+		{name: "unknown prefix",
+			address: encode(0x11, seed.PublicSpendKey(), seed.PublicViewKey()),
+			err:     monero.ErrUnknownAddressPrefix,
+		},
+		{name: "standard address with a payment id",
+			address: encode(18, seed.PublicSpendKey(), seed.PublicViewKey(), make([]byte, 8)),
+			err:     monero.ErrInvalidAddressLength,
+		},
+		{name: "integrated address without payment id",
+			address: encode(19, seed.PublicSpendKey(), seed.PublicViewKey()),
+			err:     monero.ErrInvalidAddressLength,
+		},
+		{name: "spend key not on the curve",
+			address: encode(18, notAPoint, seed.PublicViewKey()),
+			err:     monero.ErrInvalidKey,
+		},
+		{name: "view key not on the curve",
+			address: encode(42, seed.PublicSpendKey(), notAPoint),
+			err:     monero.ErrInvalidKey,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := monero.ParseAddress(tc.address)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
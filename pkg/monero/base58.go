@@ -0,0 +1,130 @@
+package monero
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// ErrInvalidBase58 is the error that decoding a string that isn't valid
+// Monero base58 fails with.
+//
+var ErrInvalidBase58 = errors.New("invalid base58")
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const (
+	// base58FullBlockSize is the size of the blocks that the data is
+	// split into, each encoded on its own.
+	//
+	base58FullBlockSize = 8
+
+	// base58FullEncodedBlockSize is the number of characters a full
+	// block is encoded to.
+	//
+	base58FullEncodedBlockSize = 11
+)
+
+// base58EncodedBlockSizes maps the size of a block to the number of
+// characters it's encoded to.
+//
+var base58EncodedBlockSizes = [base58FullBlockSize + 1]int{0, 2, 3, 5, 6, 7, 9, 10, 11}
+
+// EncodeBase58 encodes `data` with Monero's flavour of base58: rather than
+// as a whole big number, the data is encoded in blocks of 8 bytes, each
+// taking exactly 11 characters (the last, partial, one taking as many as
+// needed for its size), so that the length of the result only depends on
+// the length of the data.
+//
+func EncodeBase58(data []byte) string {
+	var sb strings.Builder
+
+	for len(data) > 0 {
+		n := len(data)
+		if n > base58FullBlockSize {
+			n = base58FullBlockSize
+		}
+
+		var num uint64
+		for _, b := range data[:n] {
+			num = num<<8 | uint64(b)
+		}
+
+		block := make([]byte, base58EncodedBlockSizes[n])
+		for i := len(block) - 1; i >= 0; i-- {
+			block[i] = base58Alphabet[num%58]
+			num /= 58
+		}
+
+		sb.Write(block)
+		data = data[n:]
+	}
+
+	return sb.String()
+}
+
+// DecodeBase58 decodes a string encoded with Monero's flavour of base58
+// (see `EncodeBase58`), failing with `ErrInvalidBase58` if it isn't one.
+//
+func DecodeBase58(s string) ([]byte, error) {
+	var res []byte
+
+	for off := 0; off < len(s); off += base58FullEncodedBlockSize {
+		end := off + base58FullEncodedBlockSize
+		if end > len(s) {
+			end = len(s)
+		}
+
+		block, err := decodeBase58Block(s[off:end])
+		if err != nil {
+			return nil, fmt.Errorf("%w: block at %d: %v", ErrInvalidBase58, off, err)
+		}
+
+		res = append(res, block...)
+	}
+
+	return res, nil
+}
+
+func decodeBase58Block(s string) ([]byte, error) {
+	size := -1
+	for n, encodedSize := range base58EncodedBlockSizes {
+		if encodedSize == len(s) {
+			size = n
+		}
+	}
+
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid length %d", len(s))
+	}
+
+	var num uint64
+	for idx := 0; idx < len(s); idx++ {
+		digit := strings.IndexByte(base58Alphabet, s[idx])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid character %q", s[idx])
+		}
+
+		hi, lo := bits.Mul64(num, 58)
+		lo, carry := bits.Add64(lo, uint64(digit), 0)
+
+		if hi != 0 || carry != 0 {
+			return nil, fmt.Errorf("overflow")
+		}
+
+		num = lo
+	}
+
+	if size < base58FullBlockSize && num>>(8*size) != 0 {
+		return nil, fmt.Errorf("overflow")
+	}
+
+	block := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		block[i] = byte(num)
+		num >>= 8
+	}
+
+	return block, nil
+}
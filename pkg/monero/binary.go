@@ -0,0 +1,231 @@
+package monero
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// decoder reads the fields of monero's binary serialization format off of a
+// blob, keeping the first error found (after which everything read is
+// zeroed) so that it only needs to be checked once done.
+//
+type decoder struct {
+	b   []byte
+	off int
+	err error
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("offset %d: %s", d.off, fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *decoder) remaining() int {
+	return len(d.b) - d.off
+}
+
+// bytes reads the next `n` bytes.
+//
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+
+	if n < 0 || n > d.remaining() {
+		d.fail("unexpected end of data reading %d bytes", n)
+		return nil
+	}
+
+	res := d.b[d.off : d.off+n]
+	d.off += n
+
+	return res
+}
+
+func (d *decoder) byte() byte {
+	b := d.bytes(1)
+	if b == nil {
+		return 0
+	}
+
+	return b[0]
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(b)
+}
+
+// uvarint reads a varint, rejecting non-canonical encodings (as monerod
+// does) so that whatever is decoded encodes back to the same bytes.
+//
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.b[d.off:])
+	if n <= 0 {
+		d.fail("malformed varint")
+		return 0
+	}
+
+	if n > 1 && d.b[d.off+n-1] == 0 {
+		d.fail("non-canonical varint")
+		return 0
+	}
+
+	d.off += n
+
+	return v
+}
+
+// count reads the size of a vector whose items take at least `itemSize`
+// bytes each (see `bound`).
+//
+func (d *decoder) count(itemSize int) int {
+	return d.bound(d.uvarint(), itemSize)
+}
+
+// bound makes sure that there's room enough left for `n` items taking at
+// least `itemSize` bytes each, so that corrupted sizes don't lead to huge
+// allocations.
+//
+func (d *decoder) bound(n uint64, itemSize int) int {
+	if d.err != nil {
+		return 0
+	}
+
+	if n > uint64(d.remaining()/itemSize) {
+		d.fail("count %d over what's left", n)
+		return 0
+	}
+
+	return int(n)
+}
+
+// hex reads `n` bytes, giving them hex-encoded.
+//
+func (d *decoder) hex(n int) string {
+	return hex.EncodeToString(d.bytes(n))
+}
+
+func (d *decoder) key() string {
+	return d.hex(KeySize)
+}
+
+func (d *decoder) keys(n int) []string {
+	if n > d.remaining()/KeySize {
+		d.fail("%d keys over what's left", n)
+		return nil
+	}
+
+	res := make([]string, n)
+	for idx := range res {
+		res[idx] = d.key()
+	}
+
+	return res
+}
+
+// keyVector reads a varint-prefixed vector of keys.
+//
+func (d *decoder) keyVector() []string {
+	return d.keys(d.count(KeySize))
+}
+
+// blob reads a varint-prefixed blob of bytes, copied so as not to hold on
+// to the one being decoded.
+//
+func (d *decoder) blob() []byte {
+	b := d.bytes(d.count(1))
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
+}
+
+// encoder writes fields in monero's binary serialization format, keeping the
+// first error found.
+//
+type encoder struct {
+	b   []byte
+	err error
+}
+
+func (e *encoder) fail(format string, args ...interface{}) {
+	if e.err == nil {
+		e.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (e *encoder) bytes(b []byte) {
+	e.b = append(e.b, b...)
+}
+
+func (e *encoder) byte(b byte) {
+	e.b = append(e.b, b)
+}
+
+func (e *encoder) uint32(v uint32) {
+	e.b = append(e.b, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(e.b[len(e.b)-4:], v)
+}
+
+func (e *encoder) uvarint(v uint64) {
+	e.b = appendUvarint(e.b, v)
+}
+
+// hex writes the hex-encoded `s`, which must decode to `n` bytes.
+//
+func (e *encoder) hex(s string, n int) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		e.fail("decode %q: %w", s, err)
+		return
+	}
+
+	if len(b) != n {
+		e.fail("expected %d bytes, got %d in %q", n, len(b), s)
+		return
+	}
+
+	e.bytes(b)
+}
+
+func (e *encoder) key(s string) {
+	e.hex(s, KeySize)
+}
+
+// keys writes the keys, of which there must be `n`.
+//
+func (e *encoder) keys(keys []string, n int) {
+	if len(keys) != n {
+		e.fail("expected %d keys, got %d", n, len(keys))
+		return
+	}
+
+	for _, key := range keys {
+		e.key(key)
+	}
+}
+
+// keyVector writes a varint-prefixed vector of keys.
+//
+func (e *encoder) keyVector(keys []string) {
+	e.uvarint(uint64(len(keys)))
+	e.keys(keys, len(keys))
+}
+
+// blob writes a varint-prefixed blob of bytes.
+//
+func (e *encoder) blob(b []byte) {
+	e.b = appendBlob(e.b, b)
+}
@@ -0,0 +1,50 @@
+package monero
+
+// Block is a block, as represented in the JSON form given by monerod both
+// through its RPC interface (e.g., the `json` field of `get_block`) and its
+// zmq publications (e.g., `json-full-chain_main`).
+//
+type Block struct {
+	// MajorVersion is the version of the consensus rules the block
+	// follows.
+	//
+	MajorVersion uint64 `json:"major_version"`
+
+	// MinorVersion is the hard fork the miner of the block voted for.
+	//
+	MinorVersion uint64 `json:"minor_version"`
+
+	// Timestamp is the unix timestamp set by the miner of the block.
+	//
+	Timestamp uint64 `json:"timestamp"`
+
+	// PrevID is the hash of the previous block.
+	//
+	PrevID string `json:"prev_id"`
+
+	// Nonce is the nonce found by the miner of the block.
+	//
+	Nonce uint32 `json:"nonce"`
+
+	// MinerTx is the coinbase transaction of the block.
+	//
+	MinerTx Transaction `json:"miner_tx"`
+
+	// TxHashes are the hashes of the non-coinbase transactions of the
+	// block.
+	//
+	TxHashes []string `json:"tx_hashes"`
+}
+
+// MinerOutputs gives the sum of the amounts of the outputs of the coinbase
+// transaction, i.e., the block reward (including fees).
+//
+func (b *Block) MinerOutputs() uint64 {
+	res := uint64(0)
+
+	for _, vout := range b.MinerTx.Vout {
+		res += vout.Amount
+	}
+
+	return res
+}
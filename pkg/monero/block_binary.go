@@ -0,0 +1,78 @@
+package monero
+
+import (
+	"fmt"
+)
+
+// DecodeBlock decodes a block from its binary serialization (e.g., the
+// `blob` field of `get_block`, or a block template blob).
+//
+func DecodeBlock(blob []byte) (*Block, error) {
+	d := &decoder{b: blob}
+
+	block := &Block{}
+	block.decode(d)
+
+	if d.err == nil && d.remaining() != 0 {
+		d.fail("%d trailing bytes", d.remaining())
+	}
+
+	if d.err != nil {
+		return nil, fmt.Errorf("decode block: %w", d.err)
+	}
+
+	return block, nil
+}
+
+// Encode gives the binary serialization of the block.
+//
+func (b *Block) Encode() ([]byte, error) {
+	e := &encoder{}
+	b.encode(e)
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode block: %w", e.err)
+	}
+
+	return e.b, nil
+}
+
+// EncodeHeader gives the binary serialization of the header of the block:
+// versions, timestamp, previous block hash and nonce.
+//
+func (b *Block) EncodeHeader() ([]byte, error) {
+	e := &encoder{}
+	b.encodeHeader(e)
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode block header: %w", e.err)
+	}
+
+	return e.b, nil
+}
+
+func (b *Block) decode(d *decoder) {
+	b.MajorVersion = d.uvarint()
+	b.MinorVersion = d.uvarint()
+	b.Timestamp = d.uvarint()
+	b.PrevID = d.key()
+	b.Nonce = d.uint32()
+
+	b.MinerTx.decode(d, false)
+
+	b.TxHashes = d.keyVector()
+}
+
+func (b *Block) encode(e *encoder) {
+	b.encodeHeader(e)
+	b.MinerTx.encode(e)
+	e.keyVector(b.TxHashes)
+}
+
+func (b *Block) encodeHeader(e *encoder) {
+	e.uvarint(b.MajorVersion)
+	e.uvarint(b.MinorVersion)
+	e.uvarint(b.Timestamp)
+	e.key(b.PrevID)
+	e.uint32(b.Nonce)
+}
@@ -1,6 +1,8 @@
 package monero
 
 import (
+	"fmt"
+
 	"github.com/paxos-bankchain/moneroutil"
 	"golang.org/x/crypto/sha3"
 )
@@ -24,3 +26,122 @@ func publicKeyFromPrivateKey(private []byte) []byte {
 
 	return public
 }
+
+// generateSignature produces a (non-ring) signature of `prefixHash` using the
+// private key `private` whose public counterpart is `public`, i.e., the pair
+// `c || r` where
+//
+// 	c = H(prefixHash || public || k*G)
+// 	r = k - c*private
+//
+// for a random scalar `k` (see `crypto::generate_signature` in monero's
+// source tree).
+//
+func generateSignature(prefixHash, public, private []byte) []byte {
+	k := moneroutil.RandomScalar()
+
+	comm := new(moneroutil.ExtendedGroupElement)
+	moneroutil.GeScalarMultBase(comm, k)
+
+	var commBytes moneroutil.Key
+	comm.ToBytes(&commBytes)
+
+	c := moneroutil.HashToScalar(prefixHash, public, commBytes[:])
+
+	r := new(moneroutil.Key)
+	moneroutil.ScMulSub(r, c, (*moneroutil.Key)(private), k)
+
+	return append(c[:], r[:]...)
+}
+
+// checkSignature verifies that `signature` (as produced by
+// `generateSignature`) is a signature of `prefixHash` by the owner of the
+// private key corresponding to `public`.
+//
+func checkSignature(prefixHash, public, signature []byte) bool {
+	if len(public) != KeySize || len(signature) != 2*KeySize {
+		return false
+	}
+
+	var pub, c, r moneroutil.Key
+
+	copy(pub[:], public)
+	copy(c[:], signature[:KeySize])
+	copy(r[:], signature[KeySize:])
+
+	point := new(moneroutil.ExtendedGroupElement)
+	if !point.FromBytes(&pub) {
+		return false
+	}
+
+	if !moneroutil.ScValid(&c) || !moneroutil.ScValid(&r) {
+		return false
+	}
+
+	// comm = c*public + r*G, which for a legit signature is k*G.
+	//
+	comm := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeDoubleScalarMultVartime(comm, &c, point, &r)
+
+	var commBytes moneroutil.Key
+	comm.ToBytes(&commBytes)
+
+	expected := moneroutil.HashToScalar(prefixHash, public, commBytes[:])
+
+	diff := new(moneroutil.Key)
+	moneroutil.ScSub(diff, expected, &c)
+
+	return moneroutil.ScIsZero(diff)
+}
+
+// isPoint tells whether `key` is the encoding of a point of the curve (see
+// `crypto::check_key` in monero's source tree).
+//
+func isPoint(key []byte) bool {
+	if len(key) != KeySize {
+		return false
+	}
+
+	var k moneroutil.Key
+	copy(k[:], key)
+
+	return new(moneroutil.ExtendedGroupElement).FromBytes(&k)
+}
+
+// keyDerivation gives the secret shared by the owners of the private keys
+// of (`public`, `private`) and of its counterpart, 8*private*public (see
+// `crypto::generate_key_derivation` in monero's source tree).
+//
+func keyDerivation(public, private []byte) ([]byte, error) {
+	if !isPoint(public) {
+		return nil, fmt.Errorf("%w: public key", ErrInvalidKey)
+	}
+
+	if len(private) != KeySize {
+		return nil, fmt.Errorf("%w: private key of %d bytes", ErrInvalidKey, len(private))
+	}
+
+	var pub, priv moneroutil.Key
+	copy(pub[:], public)
+	copy(priv[:], private)
+
+	point := new(moneroutil.ExtendedGroupElement)
+	point.FromBytes(&pub)
+
+	shared := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeScalarMult(shared, &priv, point)
+
+	// multiplying by the cofactor keeps the result in the prime-order
+	// subgroup.
+	//
+	mul8 := new(moneroutil.CompletedGroupElement)
+	moneroutil.GeMul8(mul8, shared)
+
+	res := new(moneroutil.ProjectiveGroupElement)
+	mul8.ToProjective(res)
+
+	var derivation moneroutil.Key
+	res.ToBytes(&derivation)
+
+	return derivation[:], nil
+}
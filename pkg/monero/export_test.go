@@ -0,0 +1,6 @@
+package monero
+
+// BlockID exposes how a block id is picked out of the hash of the block blob
+// and the id derived from its hashing blob.
+//
+var BlockID = blockID
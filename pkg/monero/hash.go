@@ -0,0 +1,273 @@
+package monero
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrPruned is the error that hashing a pruned transaction fails with when
+// it can't be done from what's left of it.
+//
+var ErrPruned = errors.New("transaction is pruned")
+
+// ErrBlock202612 is the error that hashing a block fails with when its content
+// gives it the id of block 202612 without it being that block.
+//
+var ErrBlock202612 = errors.New("block claims the id of block 202612")
+
+// Block 202612 got into the chain with a merkle root computed by a buggy
+// version of the tree hash, so its id can't be derived from its content with
+// the fixed one. Just like monerod does, it's recognized by the hash of its
+// blob and given the id it has on the chain, while any other block whose
+// content gives it that id is rejected.
+//
+const (
+	block202612BlobHash = "3a8a2b3a29b50fc86ff73dd087ea43c6f0d6b8f936c849194d5c84c737903966"
+	block202612ID       = "bbd604d2ba11ba27935e006ed39c9bfdd99b76bf4a50654bc1e1e61217962698"
+)
+
+// nullHash is the prunable hash of transactions with no RingCT data.
+//
+var nullHash = make([]byte, KeySize)
+
+// PrefixHash gives the (hex-encoded) hash of the prefix of the transaction,
+// which is what its signatures sign.
+//
+func (t *Transaction) PrefixHash() (string, error) {
+	prefix, err := t.EncodePrefix()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(keccak256(prefix)), nil
+}
+
+// PrunableHash gives the (hex-encoded) hash of the prunable part of the
+// RingCT data of a version 2 transaction, failing with `ErrPruned` if it's
+// been pruned.
+//
+func (t *Transaction) PrunableHash() (string, error) {
+	h, err := t.prunableHash()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h), nil
+}
+
+// Hash gives the (hex-encoded) hash of the transaction, failing with
+// `ErrPruned` if it's been pruned (see `HashWithPrunableHash`).
+//
+func (t *Transaction) Hash() (string, error) {
+	if t.Version == 1 {
+		if t.Signatures == nil && t.hasSignatures() {
+			return "", ErrPruned
+		}
+
+		blob, err := t.Encode()
+		if err != nil {
+			return "", err
+		}
+
+		return hex.EncodeToString(keccak256(blob)), nil
+	}
+
+	prunableHash, err := t.prunableHash()
+	if err != nil {
+		return "", err
+	}
+
+	return t.hash(prunableHash)
+}
+
+// HashWithPrunableHash gives the (hex-encoded) hash of a pruned version 2
+// transaction, given the hash of the part pruned from it (e.g., the
+// `prunable_hash` field of `/get_transactions`).
+//
+func (t *Transaction) HashWithPrunableHash(prunableHash string) (string, error) {
+	if t.Version == 1 {
+		return "", ErrPruned
+	}
+
+	h, err := hex.DecodeString(prunableHash)
+	if err != nil {
+		return "", fmt.Errorf("decode prunable hash: %w", err)
+	}
+
+	if len(h) != KeySize {
+		return "", fmt.Errorf("prunable hash of %d bytes", len(h))
+	}
+
+	return t.hash(h)
+}
+
+// hash gives the hash of a version 2 transaction: the hash of those of its
+// prefix, of the base of its RingCT data and of the prunable part of it.
+//
+func (t *Transaction) hash(prunableHash []byte) (string, error) {
+	prefix := &encoder{}
+	t.encodePrefix(prefix)
+
+	base := &encoder{}
+	t.RctSignatures.encode(base, len(t.Vin), len(t.Vout))
+
+	for _, e := range []*encoder{prefix, base} {
+		if e.err != nil {
+			return "", fmt.Errorf("encode transaction: %w", e.err)
+		}
+	}
+
+	return hex.EncodeToString(keccak256(
+		keccak256(prefix.b), keccak256(base.b), prunableHash,
+	)), nil
+}
+
+func (t *Transaction) prunableHash() ([]byte, error) {
+	if t.Version == 1 {
+		return nil, fmt.Errorf("version 1 transactions have no prunable data")
+	}
+
+	if t.RctSignatures.Type == RctTypeNull {
+		return nullHash, nil
+	}
+
+	if t.RctSigPrunable == nil {
+		return nil, ErrPruned
+	}
+
+	e := &encoder{}
+	t.RctSigPrunable.encode(e, t.RctSignatures.Type,
+		len(t.Vin), len(t.Vout), t.ringSize())
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode rct prunable: %w", e.err)
+	}
+
+	return keccak256(e.b), nil
+}
+
+// TreeHash gives the root of the merkle tree of `hashes` as computed by
+// monero: the tree is made complete by pairing up just enough of the
+// rightmost hashes first.
+//
+func TreeHash(hashes [][]byte) []byte {
+	switch len(hashes) {
+	case 0:
+		return nil
+	case 1:
+		return hashes[0]
+	case 2:
+		return keccak256(hashes[0], hashes[1])
+	}
+
+	cnt := 1
+	for cnt*2 < len(hashes) {
+		cnt *= 2
+	}
+
+	ints := make([][]byte, cnt)
+	untouched := 2*cnt - len(hashes)
+	copy(ints, hashes[:untouched])
+
+	for i, j := untouched, untouched; j < cnt; i, j = i+2, j+1 {
+		ints[j] = keccak256(hashes[i], hashes[i+1])
+	}
+
+	for ; cnt > 2; cnt /= 2 {
+		for i, j := 0, 0; j < cnt/2; i, j = i+2, j+1 {
+			ints[j] = keccak256(ints[i], ints[i+1])
+		}
+	}
+
+	return keccak256(ints[0], ints[1])
+}
+
+// MerkleRoot gives the (hex-encoded) root of the merkle tree of the hashes
+// of the transactions in the block, coinbase included.
+//
+func (b *Block) MerkleRoot() (string, error) {
+	root, err := b.merkleRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(root), nil
+}
+
+func (b *Block) merkleRoot() ([]byte, error) {
+	minerTxHash, err := b.MinerTx.Hash()
+	if err != nil {
+		return nil, fmt.Errorf("miner tx hash: %w", err)
+	}
+
+	hashes := make([][]byte, 0, 1+len(b.TxHashes))
+	for _, h := range append([]string{minerTxHash}, b.TxHashes...) {
+		decoded, err := hex.DecodeString(h)
+		if err != nil || len(decoded) != KeySize {
+			return nil, fmt.Errorf("invalid tx hash %q", h)
+		}
+
+		hashes = append(hashes, decoded)
+	}
+
+	return TreeHash(hashes), nil
+}
+
+// HashingBlob gives the blob that the id of the block (and its proof of
+// work) is computed from: its header, the merkle root of its transactions
+// and the number of them.
+//
+func (b *Block) HashingBlob() ([]byte, error) {
+	root, err := b.merkleRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &encoder{}
+	b.encodeHeader(e)
+	e.bytes(root)
+	e.uvarint(uint64(1 + len(b.TxHashes)))
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode block header: %w", e.err)
+	}
+
+	return e.b, nil
+}
+
+// Hash gives the (hex-encoded) id of the block.
+//
+func (b *Block) Hash() (string, error) {
+	blob, err := b.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	hashingBlob, err := b.HashingBlob()
+	if err != nil {
+		return "", err
+	}
+
+	return blockID(
+		hex.EncodeToString(keccak256(blob)),
+		hex.EncodeToString(keccak256(
+			appendUvarint(nil, uint64(len(hashingBlob))), hashingBlob,
+		)),
+	)
+}
+
+// blockID gives the id of a block out of the hash of its blob and the id
+// derived from its hashing blob, taking care of block 202612.
+//
+func blockID(blobHash, derivedID string) (string, error) {
+	if blobHash == block202612BlobHash {
+		return block202612ID, nil
+	}
+
+	if derivedID == block202612ID {
+		return "", ErrBlock202612
+	}
+
+	return derivedID, nil
+}
@@ -0,0 +1,217 @@
+package monero_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func keccak(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, v := range data {
+		h.Write(v)
+	}
+
+	return h.Sum(nil)
+}
+
+func TestTransactionHash(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		hash       string
+		prefixHash string
+	}{
+		{name: "v1 coinbase",
+			hash:       "e3a799da24d9f41aac231ba2efb853ae649283feaf5e1ba46b5fc2c194414c5d",
+			prefixHash: "e3a799da24d9f41aac231ba2efb853ae649283feaf5e1ba46b5fc2c194414c5d",
+		},
+		{name: "v1",
+			hash:       "ca9ea576d67af4926e31ebeb159aaee58950aea18e5e0ad0bae23b2d85ede8c1",
+			prefixHash: "aeecb4170b276d2ac69a7abca86f82621f56d943c8d4a8900cd56192da8d442d",
+		},
+		{name: "v2 coinbase",
+			hash:       "be30ee0ac38d83c86d84326c64b13eea5b40897a321004d17e589241d49199f7",
+			prefixHash: "bb8ffda930cbc20e5e8d8a7f52aae4770e4f627ff22903671c9e5b52a5832c59",
+		},
+		{name: "v2 simple",
+			hash:       "be9d2cf9b473dbbb2c59ffb07b5d812516f94d64121d87ad61956386a4bc3843",
+			prefixHash: "1bbfda600fa6affc80dae05b1124bf05ed0e20890aa42601441dbe0f6fa81f4b",
+		},
+		{name: "v2 simple, 2 inputs with ring size 3",
+			hash:       "7197cbfd111e8c2174d8833b3a87c723dc8b394abc1faa9175bd73ff5f4d16b5",
+			prefixHash: "25d4258c209eae157de84508cc35e5b9cb5369210e55d255a4b0feae4788e3f4",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx, err := monero.DecodeTransaction(fixture(t, "tx-"+tc.hash))
+			require.NoError(t, err)
+
+			hash, err := tx.Hash()
+			require.NoError(t, err)
+			assert.Equal(t, tc.hash, hash)
+
+			prefixHash, err := tx.PrefixHash()
+			require.NoError(t, err)
+			assert.Equal(t, tc.prefixHash, prefixHash)
+		})
+	}
+}
+
+func TestTransactionHashPruned(t *testing.T) {
+	t.Parallel()
+
+	const hash = "be9d2cf9b473dbbb2c59ffb07b5d812516f94d64121d87ad61956386a4bc3843"
+
+	tx, err := monero.DecodeTransaction(fixture(t, "tx-"+hash))
+	require.NoError(t, err)
+
+	prunableHash, err := tx.PrunableHash()
+	require.NoError(t, err)
+
+	tx.RctSigPrunable = nil
+
+	_, err = tx.Hash()
+	assert.ErrorIs(t, err, monero.ErrPruned)
+
+	_, err = tx.PrunableHash()
+	assert.ErrorIs(t, err, monero.ErrPruned)
+
+	prunedHash, err := tx.HashWithPrunableHash(prunableHash)
+	require.NoError(t, err)
+	assert.Equal(t, hash, prunedHash)
+
+	v1, err := monero.DecodeTransaction(fixture(t,
+		"tx-ca9ea576d67af4926e31ebeb159aaee58950aea18e5e0ad0bae23b2d85ede8c1"))
+	require.NoError(t, err)
+
+	v1.Signatures = nil
+
+	_, err = v1.Hash()
+	assert.ErrorIs(t, err, monero.ErrPruned)
+}
+
+func TestTreeHash(t *testing.T) {
+	t.Parallel()
+
+	h := make([][]byte, 9)
+	for idx := range h {
+		h[idx] = key(byte(idx))
+	}
+
+	for _, tc := range []struct {
+		count    int
+		expected []byte
+	}{
+		{1, h[0]},
+		{2, keccak(h[0], h[1])},
+		{3, keccak(h[0], keccak(h[1], h[2]))},
+		{4, keccak(keccak(h[0], h[1]), keccak(h[2], h[3]))},
+		{5, keccak(keccak(h[0], h[1]), keccak(h[2], keccak(h[3], h[4])))},
+		{8, keccak(
+			keccak(keccak(h[0], h[1]), keccak(h[2], h[3])),
+			keccak(keccak(h[4], h[5]), keccak(h[6], h[7])),
+		)},
+		{9, keccak(
+			keccak(keccak(h[0], h[1]), keccak(h[2], h[3])),
+			keccak(keccak(h[4], h[5]), keccak(h[6], keccak(h[7], h[8]))),
+		)},
+	} {
+		assert.Equal(t, tc.expected, monero.TreeHash(h[:tc.count]), "count %d", tc.count)
+	}
+}
+
+func TestBlockHash(t *testing.T) {
+	t.Parallel()
+
+	const hash = "418015bb9ae982a1975da7d79277c2705727a56894ba0fb246adaabb1f4632e3"
+
+	block, err := monero.DecodeBlock(fixture(t, "block-"+hash))
+	require.NoError(t, err)
+
+	id, err := block.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, id)
+
+	minerTxHash, err := block.MinerTx.Hash()
+	require.NoError(t, err)
+
+	root, err := block.MerkleRoot()
+	require.NoError(t, err)
+	assert.Equal(t, minerTxHash, root)
+
+	hashingBlob, err := block.HashingBlob()
+	require.NoError(t, err)
+
+	header, err := block.EncodeHeader()
+	require.NoError(t, err)
+	assert.Equal(t, header, hashingBlob[:len(header)])
+	assert.Equal(t, byte(1), hashingBlob[len(hashingBlob)-1])
+
+	block.TxHashes = []string{"zz"}
+
+	_, err = block.Hash()
+	assert.Error(t, err)
+}
+
+// TestBlockID checks the exception monerod makes for block 202612, whose id
+// can't be derived from its content.
+//
+func TestBlockID(t *testing.T) {
+	t.Parallel()
+
+	const (
+		blobHash202612 = "3a8a2b3a29b50fc86ff73dd087ea43c6f0d6b8f936c849194d5c84c737903966"
+		id202612       = "bbd604d2ba11ba27935e006ed39c9bfdd99b76bf4a50654bc1e1e61217962698"
+	)
+
+	for _, tc := range []struct {
+		name      string
+		blobHash  string
+		derivedID string
+		id        string
+		err       error
+	}{
+		{
+			name:      "block 202612",
+			blobHash:  blobHash202612,
+			derivedID: hexKey(0x42),
+			id:        id202612,
+		},
+		{
+			name:      "other block claiming the id of 202612",
+			blobHash:  hexKey(0x01),
+			derivedID: id202612,
+			err:       monero.ErrBlock202612,
+		},
+		{
+			name:      "any other block",
+			blobHash:  hexKey(0x01),
+			derivedID: hexKey(0x42),
+			id:        hexKey(0x42),
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			id, err := monero.BlockID(tc.blobHash, tc.derivedID)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.id, id)
+		})
+	}
+}
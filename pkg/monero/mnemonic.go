@@ -0,0 +1,185 @@
+package monero
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidMnemonicLength is the error that restoring from a mnemonic
+	// that has neither 24 nor 25 words fails with.
+	//
+	ErrInvalidMnemonicLength = errors.New("invalid mnemonic length")
+
+	// ErrUnknownWord is the error (wrapped in a `MnemonicWordError`) that
+	// restoring from a mnemonic with a word that isn't in the wordlist
+	// fails with.
+	//
+	ErrUnknownWord = errors.New("unknown word")
+
+	// ErrInvalidWordTriplet is the error (wrapped in a
+	// `MnemonicWordError`) that restoring from a mnemonic with a triplet of
+	// words that can't result from encoding any 4 bytes fails with.
+	//
+	ErrInvalidWordTriplet = errors.New("invalid word triplet")
+)
+
+const (
+	// mnemonicSize is the number of words of a mnemonic, checksum word
+	// included.
+	//
+	mnemonicSize = 25
+
+	// uniquePrefixLen is the number of characters of the words of the
+	// English wordlist that identify them uniquely, i.e., that are enough
+	// for restoring.
+	//
+	uniquePrefixLen = 3
+)
+
+// MnemonicWordError is the error that restoring from a mnemonic with a wrong
+// word fails with.
+//
+type MnemonicWordError struct {
+	// Position is the (1-based) position of the wrong word in the
+	// mnemonic.
+	//
+	Position int
+
+	// Word is the wrong word.
+	//
+	Word string
+
+	// Err is what's wrong with it (`ErrUnknownWord`,
+	// `ErrInvalidWordTriplet` or `ErrInvalidChecksum`).
+	//
+	Err error
+}
+
+func (e *MnemonicWordError) Error() string {
+	return fmt.Sprintf("word %d (%q): %v", e.Position, e.Word, e.Err)
+}
+
+func (e *MnemonicWordError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	wordlistEnglishPrefixes     map[string]int
+	wordlistEnglishPrefixesOnce sync.Once
+)
+
+// wordIndex gives the index in the English wordlist of the word that `word`
+// is (or starts like, given it has at least `uniquePrefixLen` characters).
+//
+func wordIndex(word string) (int, bool) {
+	wordlistEnglishPrefixesOnce.Do(func() {
+		wordlistEnglishPrefixes = make(map[string]int, len(WordlistEnglish))
+
+		for idx, w := range WordlistEnglish {
+			wordlistEnglishPrefixes[wordPrefix(w)] = idx
+		}
+	})
+
+	idx, found := wordlistEnglishPrefixes[wordPrefix(strings.ToLower(word))]
+	return idx, found
+}
+
+func wordPrefix(word string) string {
+	runes := []rune(word)
+	if len(runes) > uniquePrefixLen {
+		runes = runes[:uniquePrefixLen]
+	}
+
+	return string(runes)
+}
+
+// NewSeedFromMnemonic restores the seed whose mnemonic (see
+// `Seed.Mnemonic`) is `words`: either all the 25 words, or just the first 24
+// ones (leaving out the checksum one). Words can be abbreviated down to
+// their first 3 characters.
+//
+// A wrong word is reported through a `MnemonicWordError`.
+//
+func NewSeedFromMnemonic(words []string, opts ...SeedOption) (*Seed, error) {
+	if len(words) != mnemonicSize && len(words) != mnemonicSize-1 {
+		return nil, fmt.Errorf("%w: %d words, expected %d (or %d without checksum)",
+			ErrInvalidMnemonicLength, len(words), mnemonicSize, mnemonicSize-1)
+	}
+
+	indices := make([]uint32, len(words))
+	for pos, word := range words {
+		idx, found := wordIndex(word)
+		if !found {
+			return nil, &MnemonicWordError{
+				Position: pos + 1, Word: word, Err: ErrUnknownWord,
+			}
+		}
+
+		indices[pos] = uint32(idx)
+	}
+
+	if len(words) == mnemonicSize {
+		expected := words[mnemonicChecksumIndex(words[:mnemonicSize-1])]
+		if wordPrefix(strings.ToLower(expected)) != wordPrefix(strings.ToLower(words[mnemonicSize-1])) {
+			return nil, &MnemonicWordError{
+				Position: mnemonicSize,
+				Word:     words[mnemonicSize-1],
+				Err:      ErrInvalidChecksum,
+			}
+		}
+	}
+
+	n := uint32(len(WordlistEnglish))
+	privateSpendKey := make([]byte, KeySize)
+
+	for i := 0; i < KeySize/4; i++ {
+		w1, w2, w3 := indices[3*i], indices[3*i+1], indices[3*i+2]
+
+		x := w1 + n*((n-w1+w2)%n) + n*n*((n-w2+w3)%n)
+		if x%n != w1 {
+			return nil, &MnemonicWordError{
+				Position: 3*i + 1, Word: words[3*i], Err: ErrInvalidWordTriplet,
+			}
+		}
+
+		binary.LittleEndian.PutUint32(privateSpendKey[4*i:], x)
+	}
+
+	return NewSeed(privateSpendKey, opts...), nil
+}
+
+// NewSeedFromHex restores the seed whose hex-encoded private spend key is
+// `privateSpendKey`.
+//
+func NewSeedFromHex(privateSpendKey string, opts ...SeedOption) (*Seed, error) {
+	key, err := hex.DecodeString(privateSpendKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode private spend key: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("%w: private spend key of %d bytes",
+			ErrInvalidKey, len(key))
+	}
+
+	return NewSeed(key, opts...), nil
+}
+
+// mnemonicChecksumIndex gives the index of the word (among the first 24)
+// that the checksum word repeats: the CRC32 of the prefixes of the words,
+// modulo 24.
+//
+func mnemonicChecksumIndex(words []string) int {
+	hash := crc32.NewIEEE()
+	for _, word := range words {
+		hash.Write([]byte(wordPrefix(strings.ToLower(word))))
+	}
+
+	return int(hash.Sum32() % uint32(len(words)))
+}
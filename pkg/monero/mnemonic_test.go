@@ -0,0 +1,125 @@
+package monero_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func TestNewSeedFromMnemonic(t *testing.T) {
+	mnemonic := monero.NewSeed(concat(
+		[]byte{1, 2, 3, 4, 5, 6, 7, 8},
+		make([]byte, 16),
+		[]byte{8, 7, 6, 5, 4, 3, 2, 1},
+	)).Mnemonic()
+
+	abbreviated := make([]string, len(mnemonic))
+	for idx, word := range mnemonic {
+		abbreviated[idx] = strings.ToUpper(word[:3])
+	}
+
+	for _, tc := range []struct {
+		name  string
+		words []string
+	}{
+		{name: "full", words: mnemonic},
+		{name: "without checksum", words: mnemonic[:24]},
+		{name: "abbreviated, upper case", words: abbreviated},
+	} {
+		seed, err := monero.NewSeedFromMnemonic(tc.words,
+			monero.WithNetwork(monero.NetworkStagenet))
+		require.NoError(t, err, tc.name)
+
+		assert.Equal(t, mnemonic, seed.Mnemonic(), tc.name)
+		assert.Equal(t,
+			"0102030405060708000000000000000000000000000000000807060504030201",
+			hex.EncodeToString(seed.PrivateSpendKey()), tc.name)
+		assert.Equal(t, monero.NetworkStagenet,
+			seed.ViewOnly().PrimaryAddress().Network, tc.name)
+	}
+}
+
+func TestNewSeedFromMnemonicInvalid(t *testing.T) {
+	mnemonic := monero.NewSeed(key(1)).Mnemonic()
+
+	replace := func(pos int, word string) []string {
+		words := append([]string{}, mnemonic...)
+		words[pos-1] = word
+
+		return words
+	}
+
+	otherChecksum := "abbey"
+	if mnemonic[24] == otherChecksum {
+		otherChecksum = "zoom"
+	}
+
+	for _, tc := range []struct {
+		name  string
+		words []string
+
+		err      error
+		position int
+	}{
+		{name: "too short",
+			words: mnemonic[:23],
+			err:   monero.ErrInvalidMnemonicLength,
+		},
+		{name: "too long",
+			words: append(append([]string{}, mnemonic...), "abbey"),
+			err:   monero.ErrInvalidMnemonicLength,
+		},
+		{name: "unknown word",
+			words:    replace(7, "xylophone"),
+			err:      monero.ErrUnknownWord,
+			position: 7,
+		},
+		{name: "ambiguous abbreviation",
+			words:    replace(12, "ab"),
+			err:      monero.ErrUnknownWord,
+			position: 12,
+		},
+		{name: "wrong checksum",
+			words:    replace(25, otherChecksum),
+			err:      monero.ErrInvalidChecksum,
+			position: 25,
+		},
+		{name: "invalid triplet",
+			words: append(
+				[]string{"ability", "abducts", "abbey"}, mnemonic[3:24]...,
+			),
+			err:      monero.ErrInvalidWordTriplet,
+			position: 1,
+		},
+	} {
+		_, err := monero.NewSeedFromMnemonic(tc.words)
+		assert.ErrorIs(t, err, tc.err, tc.name)
+
+		if tc.position == 0 {
+			continue
+		}
+
+		var wordErr *monero.MnemonicWordError
+		require.True(t, errors.As(err, &wordErr), tc.name)
+		assert.Equal(t, tc.position, wordErr.Position, tc.name)
+		assert.Equal(t, tc.words[tc.position-1], wordErr.Word, tc.name)
+	}
+}
+
+func TestNewSeedFromHex(t *testing.T) {
+	seed, err := monero.NewSeedFromHex(hex.EncodeToString(key(1)))
+	require.NoError(t, err)
+	assert.Equal(t, monero.NewSeed(key(1)).PrimaryAddress(), seed.PrimaryAddress())
+
+	_, err = monero.NewSeedFromHex("zz")
+	assert.Error(t, err)
+
+	_, err = monero.NewSeedFromHex("0102")
+	assert.ErrorIs(t, err, monero.ErrInvalidKey)
+}
@@ -27,3 +27,39 @@ func (n Network) PublicAddressBase58Prefix() []byte {
 
 	panic(fmt.Errorf("'%s' is not a valid netowrk", n))
 }
+
+// PublicIntegratedAddressBase58Prefix gives the (varint-encoded) prefix of
+// the integrated addresses of the network.
+//
+func (n Network) PublicIntegratedAddressBase58Prefix() []byte {
+	switch n {
+	case NetworkMainnet:
+		return []byte{19}
+	case NetworkTestnet:
+		return []byte{54}
+	case NetworkStagenet:
+		return []byte{25}
+	case NetworkFakechain:
+		return NetworkMainnet.PublicIntegratedAddressBase58Prefix()
+	}
+
+	panic(fmt.Errorf("'%s' is not a valid netowrk", n))
+}
+
+// PublicSubaddressBase58Prefix gives the (varint-encoded) prefix of the
+// subaddresses of the network.
+//
+func (n Network) PublicSubaddressBase58Prefix() []byte {
+	switch n {
+	case NetworkMainnet:
+		return []byte{42}
+	case NetworkTestnet:
+		return []byte{63}
+	case NetworkStagenet:
+		return []byte{36}
+	case NetworkFakechain:
+		return NetworkMainnet.PublicSubaddressBase58Prefix()
+	}
+
+	panic(fmt.Errorf("'%s' is not a valid netowrk", n))
+}
@@ -0,0 +1,95 @@
+package monero
+
+import (
+	"fmt"
+)
+
+// encryptedPaymentIDTail is the byte appended to the key derivation to get
+// the key that short payment IDs are encrypted with.
+//
+const encryptedPaymentIDTail = 0x8d
+
+// EncryptPaymentID encrypts a short (8-byte) payment ID with the secret
+// shared between the sender and the receiver of a transaction: the key
+// derivation of (`publicKey`, `privateKey`), being either the receiver's
+// public view key and the transaction private key (sender), or the
+// transaction public key and the receiver's private view key (receiver).
+//
+// The encryption being a xor with a key derived from that secret,
+// decrypting is the exact same operation (see `DecryptPaymentID`).
+//
+func EncryptPaymentID(paymentID, publicKey, privateKey []byte) ([]byte, error) {
+	if len(paymentID) != EncryptedPaymentIDSize {
+		return nil, fmt.Errorf("%w: %d bytes, expected %d",
+			ErrInvalidPaymentID, len(paymentID), EncryptedPaymentIDSize)
+	}
+
+	derivation, err := keyDerivation(publicKey, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("key derivation: %w", err)
+	}
+
+	key := keccak256(derivation, []byte{encryptedPaymentIDTail})
+
+	res := make([]byte, EncryptedPaymentIDSize)
+	for idx := range res {
+		res[idx] = paymentID[idx] ^ key[idx]
+	}
+
+	return res, nil
+}
+
+// DecryptPaymentID decrypts a short (8-byte) payment ID encrypted with
+// `EncryptPaymentID`.
+//
+func DecryptPaymentID(encrypted, publicKey, privateKey []byte) ([]byte, error) {
+	return EncryptPaymentID(encrypted, publicKey, privateKey)
+}
+
+// NewEncryptedPaymentIDNonce gives the extra nonce carrying an encrypted
+// short payment ID.
+//
+func NewEncryptedPaymentIDNonce(encrypted []byte) *TxExtraNonce {
+	return &TxExtraNonce{
+		Data: append([]byte{TxExtraNonceEncryptedPaymentID}, encrypted...),
+	}
+}
+
+// DecryptPaymentID decrypts a short payment ID sent to the wallet in a
+// transaction whose public key is `txPublicKey`.
+//
+func (k *ViewOnlyKeys) DecryptPaymentID(encrypted, txPublicKey []byte) ([]byte, error) {
+	return DecryptPaymentID(encrypted, txPublicKey, k.privateViewKey)
+}
+
+// TxPaymentID gives the payment ID carried by the extra of a transaction
+// sent to the wallet, decrypting it if it's a short one, or nil if there's
+// none.
+//
+func (k *ViewOnlyKeys) TxPaymentID(extra TxExtra) ([]byte, error) {
+	fields, err := extra.Fields()
+	if err != nil {
+		return nil, fmt.Errorf("parse extra: %w", err)
+	}
+
+	nonce := fields.Nonce()
+	if nonce == nil {
+		return nil, nil
+	}
+
+	if paymentID := nonce.PaymentID(); paymentID != nil {
+		return paymentID, nil
+	}
+
+	encrypted := nonce.EncryptedPaymentID()
+	if encrypted == nil {
+		return nil, nil
+	}
+
+	txPublicKey := fields.PublicKey()
+	if txPublicKey == nil {
+		return nil, fmt.Errorf("encrypted payment id without tx public key")
+	}
+
+	return k.DecryptPaymentID(encrypted, txPublicKey)
+}
@@ -0,0 +1,187 @@
+package monero_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/paxos-bankchain/moneroutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func TestIntegratedAddress(t *testing.T) {
+	seed := monero.NewSeed(key(1))
+	paymentID := []byte{0xde, 0xad, 0xbe, 0xef, 0, 1, 2, 3}
+
+	for _, network := range []monero.Network{
+		monero.NetworkMainnet, monero.NetworkTestnet, monero.NetworkStagenet,
+	} {
+		primary := monero.NewSeed(key(1), monero.WithNetwork(network)).
+			ViewOnly().PrimaryAddress()
+
+		integrated, err := primary.Integrated(paymentID)
+		require.NoError(t, err, network)
+
+		parsed, err := monero.ParseAddress(integrated.String())
+		require.NoError(t, err, network)
+
+		assert.Equal(t, network, parsed.Network)
+		assert.Equal(t, monero.AddressIntegrated, parsed.Kind)
+		assert.Equal(t, paymentID, parsed.PaymentID)
+		assert.Equal(t, primary, parsed.Standard())
+	}
+
+	primary := seed.ViewOnly().PrimaryAddress()
+	assert.Same(t, primary, primary.Standard())
+
+	_, err := primary.Integrated(paymentID[1:])
+	assert.ErrorIs(t, err, monero.ErrInvalidPaymentID)
+
+	_, err = seed.Subaddress(0, 1).Integrated(paymentID)
+	assert.Error(t, err)
+}
+
+// TestIntegratedAddressLayout checks an integrated address made out of a
+// known one (that of the seed of monero's functional tests) against its
+// binary layout: the integrated address prefix (19 on mainnet), the keys, the
+// payment id and the checksum.
+//
+func TestIntegratedAddressLayout(t *testing.T) {
+	primary, err := monero.ParseAddress(
+		"42ey1afDFnn4886T7196doS9GPMzexD9gXpsZJDwVjeRVdFCSoHnv7KPbBeGpzJBzHRCAs9UxqeoyFQMYbqSWYTfJJQAWDm",
+	)
+	require.NoError(t, err)
+
+	paymentID := []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+
+	integrated, err := primary.Integrated(paymentID)
+	require.NoError(t, err)
+
+	data := concat([]byte{19}, primary.PublicSpendKey, primary.PublicViewKey, paymentID)
+
+	raw, err := monero.DecodeBase58(integrated.String())
+	require.NoError(t, err)
+	assert.Equal(t, concat(data, keccak(data)[:4]), raw)
+}
+
+// TestDecryptPaymentIDKnownAnswer decrypts a payment id with the keys of a
+// key derivation vector from monero's `tests/crypto/tests.txt`, so that the
+// shared secret is a known one.
+//
+func TestDecryptPaymentIDKnownAnswer(t *testing.T) {
+	var (
+		publicKey  = mustHex(t, "fdfd97d2ea9f1c25df773ff2c973d885653a3ee643157eb0ae2b6dd98f0b6984")
+		privateKey = mustHex(t, "eb2bd1cf0c5e074f9dbf38ebbc99c316f54e21803048c687a3bb359f7a713b02")
+		derivation = mustHex(t, "4e0bd2c41325a1b89a9f7413d4d05e0a5a4936f241dccc3c7d0c539ffe00ef67")
+		paymentID  = []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	)
+
+	encrypted := make([]byte, len(paymentID))
+	for idx, b := range keccak(derivation, []byte{0x8d})[:len(paymentID)] {
+		encrypted[idx] = paymentID[idx] ^ b
+	}
+
+	decrypted, err := monero.DecryptPaymentID(encrypted, publicKey, privateKey)
+	require.NoError(t, err)
+	assert.Equal(t, paymentID, decrypted)
+}
+
+func TestEncryptPaymentID(t *testing.T) {
+	receiver := monero.NewSeed(key(1))
+	paymentID := []byte{0xde, 0xad, 0xbe, 0xef, 0, 1, 2, 3}
+
+	txPrivateKey := moneroutil.RandomScalar()
+	txPublicKey := txPrivateKey.PubKey()
+
+	// sender: shared secret out of the receiver's public view key and the
+	// transaction private key.
+	//
+	encrypted, err := monero.EncryptPaymentID(
+		paymentID, receiver.PublicViewKey(), txPrivateKey[:],
+	)
+	require.NoError(t, err)
+	assert.NotEqual(t, paymentID, encrypted)
+
+	// receiver: shared secret out of the transaction public key and its
+	// private view key.
+	//
+	decrypted, err := monero.DecryptPaymentID(
+		encrypted, txPublicKey[:], receiver.PrivateViewKey(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, paymentID, decrypted)
+
+	extra, err := monero.TxExtraFields{
+		{Tag: monero.TxExtraTagPublicKey, PublicKey: txPublicKey[:]},
+		{Tag: monero.TxExtraTagNonce, Nonce: monero.NewEncryptedPaymentIDNonce(encrypted)},
+	}.Bytes()
+	require.NoError(t, err)
+
+	fromExtra, err := receiver.ViewOnly().TxPaymentID(extra)
+	require.NoError(t, err)
+	assert.Equal(t, paymentID, fromExtra)
+
+	other, err := monero.NewSeed(key(2)).ViewOnly().TxPaymentID(extra)
+	require.NoError(t, err)
+	assert.NotEqual(t, paymentID, other)
+
+	_, err = monero.EncryptPaymentID(paymentID[1:], receiver.PublicViewKey(), txPrivateKey[:])
+	assert.ErrorIs(t, err, monero.ErrInvalidPaymentID)
+
+	notAPoint := make([]byte, monero.KeySize)
+	notAPoint[0] = 2
+
+	_, err = monero.EncryptPaymentID(paymentID, notAPoint, txPrivateKey[:])
+	assert.ErrorIs(t, err, monero.ErrInvalidKey)
+}
+
+func TestTxPaymentID(t *testing.T) {
+	keys := monero.NewSeed(key(1)).ViewOnly()
+
+	for _, tc := range []struct {
+		name string
+
+		extra     []byte
+		paymentID []byte
+		err       bool
+	}{
+		{name: "no nonce",
+			extra: concat([]byte{0x01}, key(1)),
+		},
+		{name: "unencrypted payment id",
+			extra:     concat([]byte{0x01}, key(1), []byte{0x02, 33, 0x00}, key(7)),
+			paymentID: key(7),
+		},
+		{name: "arbitrary nonce",
+			extra: concat([]byte{0x01}, key(1), []byte{0x02, 2, 0xaa, 0xbb}),
+		},
+		{name: "encrypted payment id without tx public key",
+			extra: []byte{0x02, 9, 0x01, 1, 2, 3, 4, 5, 6, 7, 8},
+			err:   true,
+		},
+		{name: "malformed extra",
+			extra: []byte{0x01, 0x01},
+			err:   true,
+		},
+	} {
+		paymentID, err := keys.TxPaymentID(tc.extra)
+		if tc.err {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.paymentID, paymentID, tc.name)
+	}
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
@@ -0,0 +1,82 @@
+package monero
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/paxos-bankchain/moneroutil"
+)
+
+// rpcPaymentTimestampSize is the number of hex characters that the timestamp
+// in an rpc payment signature takes.
+//
+const rpcPaymentTimestampSize = 16
+
+// RPCPaymentSignature generates the value of the `client` field that nodes
+// offering rpc for credits (`--rpc-payment-address`) identify (and
+// authenticate) clients with: the hex-encoded public key corresponding to
+// `privateKey`, followed by the timestamp `t` (in microseconds) and a
+// signature of it.
+//
+// Nodes reject signatures whose timestamps they have seen already, so a new
+// one must be generated for each request.
+//
+func RPCPaymentSignature(privateKey []byte, t time.Time) (string, error) {
+	if len(privateKey) != KeySize {
+		return "", fmt.Errorf("expected private key of %d bytes, got %d",
+			KeySize, len(privateKey))
+	}
+
+	ts := fmt.Sprintf("%016x", uint64(t.UnixNano()/int64(time.Microsecond)))
+	public := publicKeyFromPrivateKey(privateKey)
+	signature := generateSignature(keccak256([]byte(ts)), public, privateKey)
+
+	return hex.EncodeToString(public) + ts + hex.EncodeToString(signature), nil
+}
+
+// VerifyRPCPaymentSignature verifies an rpc payment signature (see
+// `RPCPaymentSignature`), giving back the hex-encoded public key identifying
+// the client and the time at which the signature has been produced.
+//
+func VerifyRPCPaymentSignature(signature string) (string, time.Time, error) {
+	const (
+		keyEnd = 2 * KeySize
+		tsEnd  = keyEnd + rpcPaymentTimestampSize
+	)
+
+	if len(signature) != tsEnd+4*KeySize {
+		return "", time.Time{}, fmt.Errorf("invalid signature length %d",
+			len(signature))
+	}
+
+	public, err := hex.DecodeString(signature[:keyEnd])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("decode public key: %w", err)
+	}
+
+	ts, err := strconv.ParseUint(signature[keyEnd:tsEnd], 16, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("parse timestamp: %w", err)
+	}
+
+	sig, err := hex.DecodeString(signature[tsEnd:])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("decode signature: %w", err)
+	}
+
+	if !checkSignature(keccak256([]byte(signature[keyEnd:tsEnd])), public, sig) {
+		return "", time.Time{}, fmt.Errorf("invalid signature")
+	}
+
+	return signature[:keyEnd], time.Unix(0, int64(ts)*int64(time.Microsecond)), nil
+}
+
+// NewRPCPaymentKey generates a random private key for a client to identify
+// itself with to nodes offering rpc for credits (see `RPCPaymentSignature`).
+//
+func NewRPCPaymentKey() []byte {
+	key := moneroutil.RandomScalar()
+	return key[:]
+}
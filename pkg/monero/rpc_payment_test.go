@@ -0,0 +1,64 @@
+package monero_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func TestRPCPaymentSignature(t *testing.T) {
+	// 1 as a scalar, whose public key is the base point itself.
+	//
+	privateKey := make([]byte, monero.KeySize)
+	privateKey[0] = 1
+
+	now := time.Unix(1600000000, 123456000)
+
+	signature, err := monero.RPCPaymentSignature(privateKey, now)
+	require.NoError(t, err)
+	require.Len(t, signature, 208)
+
+	assert.Equal(t,
+		"5866666666666666666666666666666666666666666666666666666666666666",
+		signature[:64])
+	assert.Equal(t, "0005af3107a5e240", signature[64:80])
+
+	t.Run("verifies", func(t *testing.T) {
+		public, ts, err := monero.VerifyRPCPaymentSignature(signature)
+		require.NoError(t, err)
+
+		assert.Equal(t, signature[:64], public)
+		assert.True(t, now.Equal(ts))
+	})
+
+	t.Run("fails with a tampered timestamp", func(t *testing.T) {
+		tampered := signature[:79] + "1" + signature[80:]
+
+		_, _, err := monero.VerifyRPCPaymentSignature(tampered)
+		assert.Error(t, err)
+	})
+
+	t.Run("fails with a tampered signature", func(t *testing.T) {
+		last := "0"
+		if signature[207] == '0' {
+			last = "1"
+		}
+
+		_, _, err := monero.VerifyRPCPaymentSignature(signature[:207] + last)
+		assert.Error(t, err)
+	})
+
+	t.Run("fails with a malformed signature", func(t *testing.T) {
+		_, _, err := monero.VerifyRPCPaymentSignature("abcd")
+		assert.Error(t, err)
+	})
+
+	t.Run("fails with a bad private key", func(t *testing.T) {
+		_, err := monero.RPCPaymentSignature([]byte{1}, now)
+		assert.Error(t, err)
+	})
+}
@@ -2,7 +2,6 @@ package monero
 
 import (
 	"encoding/binary"
-	"hash/crc32"
 
 	"github.com/paxos-bankchain/moneroutil"
 )
@@ -84,6 +83,8 @@ func (s *Seed) deriveKeys() {
 	moneroutil.ScReduce32((*moneroutil.Key)(s.privateSpendKey))
 
 	s.privateViewKey = keccak256(s.privateSpendKey)
+	moneroutil.ScReduce32((*moneroutil.Key)(s.privateViewKey))
+
 	s.publicSpendKey = publicKeyFromPrivateKey(s.privateSpendKey)
 	s.publicViewKey = publicKeyFromPrivateKey(s.privateViewKey)
 }
@@ -114,15 +115,7 @@ func (s *Seed) Mnemonic() []string {
 		mnemonic[i/4*3+2] = WordlistEnglish[w3]
 	}
 
-	hash := crc32.NewIEEE()
-	for _, word := range mnemonic[:24] {
-		rrune := string([]rune(word)[:3])
-		hash.Write([]byte(rrune))
-	}
-
-	sum := hash.Sum32()
-	idx := sum % 24
-	mnemonic[24] = mnemonic[idx]
+	mnemonic[24] = mnemonic[mnemonicChecksumIndex(mnemonic[:24])]
 
 	return mnemonic
 }
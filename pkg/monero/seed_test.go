@@ -54,7 +54,7 @@ func TestSeed(t *testing.T) {
 
 				"abbey",
 			},
-			primaryAddress: "4953Se8CDGeZHr8sWmL61WNhKJatXZRSv6eJHB4hbBXF2RFmmKcxHQDR9i8nDkk94uHddmZohDAaPMcoqWgj4oMa748VFsf",
+			primaryAddress: "4953Se8CDGeZHr8sWmL61WNhKJatXZRSv6eJHB4hbBXF2TrCpey9RheYrjQsWpyYjQVTRn8Mcbns4VzidsRUMDfF584woZc",
 		},
 		{name: "full 1-s",
 
@@ -74,7 +74,7 @@ func TestSeed(t *testing.T) {
 
 				"vector",
 			},
-			primaryAddress: "42Lxp5b63YJ8mVZTzcioVnCk9WQCPAMk4RH7e7ygPTkzEexugYvy5PvQS1MoWJE5ugbSCx9jYHgdbWhd8tQNvDbwFXdWDQC",
+			primaryAddress: "42Lxp5b63YJ8mVZTzcioVnCk9WQCPAMk4RH7e7ygPTkzEiHB86MJkRbb9c4uyE3bV8fuu7ggU2XUYDFT4SxB7pbNC6PwL6c",
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,253 @@
+package monero
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/paxos-bankchain/moneroutil"
+)
+
+// subaddressSecretPrefix is the domain separator of the hash that derives
+// the secret of a subaddress (null terminator included).
+//
+const subaddressSecretPrefix = "SubAddr\x00"
+
+// SubaddressIndex identifies a subaddress of a wallet: `Major` being the
+// index of the account, and `Minor` the one of the subaddress within it.
+//
+// (0, 0) is the primary address.
+//
+type SubaddressIndex struct {
+	Major uint32 `json:"major"`
+	Minor uint32 `json:"minor"`
+}
+
+// ViewOnlyKeys are the keys of a view-only wallet: the private view key and
+// the public spend key, enough to derive the subaddresses of the wallet and
+// to recognize the outputs it receives, but not to spend them.
+//
+type ViewOnlyKeys struct {
+	network Network
+
+	privateViewKey []byte
+	publicSpendKey []byte
+	publicViewKey  []byte
+}
+
+// NewViewOnlyKeys instantiates the view-only keys of a wallet of `network`
+// out of its private view key and public spend key.
+//
+func NewViewOnlyKeys(
+	privateViewKey, publicSpendKey []byte, network Network,
+) (*ViewOnlyKeys, error) {
+	if len(privateViewKey) != KeySize {
+		return nil, fmt.Errorf("%w: private view key of %d bytes",
+			ErrInvalidKey, len(privateViewKey))
+	}
+
+	var k moneroutil.Key
+	copy(k[:], privateViewKey)
+
+	if !moneroutil.ScValid(&k) {
+		return nil, fmt.Errorf("%w: private view key not reduced", ErrInvalidKey)
+	}
+
+	if !isPoint(publicSpendKey) {
+		return nil, fmt.Errorf("%w: public spend key", ErrInvalidKey)
+	}
+
+	return &ViewOnlyKeys{
+		network:        network,
+		privateViewKey: privateViewKey,
+		publicSpendKey: publicSpendKey,
+		publicViewKey:  publicKeyFromPrivateKey(privateViewKey),
+	}, nil
+}
+
+// ViewOnly gives the view-only keys of the seed.
+//
+func (s *Seed) ViewOnly() *ViewOnlyKeys {
+	return &ViewOnlyKeys{
+		network:        s.network,
+		privateViewKey: s.privateViewKey,
+		publicSpendKey: s.publicSpendKey,
+		publicViewKey:  s.publicViewKey,
+	}
+}
+
+// Subaddress derives the subaddress (`major`, `minor`) of the seed (see
+// `ViewOnlyKeys.Subaddress`).
+//
+func (s *Seed) Subaddress(major, minor uint32) *Address {
+	return s.ViewOnly().Subaddress(major, minor)
+}
+
+// Subaddresses derives `count` consecutive subaddresses of the seed (see
+// `ViewOnlyKeys.Subaddresses`).
+//
+func (s *Seed) Subaddresses(major, minor, count uint32) []*Address {
+	return s.ViewOnly().Subaddresses(major, minor, count)
+}
+
+// SubaddressTable builds the lookup table of the subaddresses of the seed
+// (see `ViewOnlyKeys.SubaddressTable`).
+//
+func (s *Seed) SubaddressTable(accounts, indices uint32) SubaddressTable {
+	return s.ViewOnly().SubaddressTable(accounts, indices)
+}
+
+func (k *ViewOnlyKeys) PrivateViewKey() []byte {
+	return k.privateViewKey
+}
+
+func (k *ViewOnlyKeys) PublicSpendKey() []byte {
+	return k.publicSpendKey
+}
+
+func (k *ViewOnlyKeys) PublicViewKey() []byte {
+	return k.publicViewKey
+}
+
+// PrimaryAddress gives the primary address of the wallet.
+//
+func (k *ViewOnlyKeys) PrimaryAddress() *Address {
+	return &Address{
+		Network:        k.network,
+		Kind:           AddressStandard,
+		PublicSpendKey: k.publicSpendKey,
+		PublicViewKey:  k.publicViewKey,
+	}
+}
+
+// Subaddress derives the subaddress (`major`, `minor`) of the wallet, that
+// is, out of the secret
+//
+// 	m = Hs("SubAddr\0" || a || major || minor)
+//
+// the public keys
+//
+// 	D = B + m*G	(spend)
+// 	C = a*D		(view)
+//
+// where `a` is the private view key and `B` the public spend key. (0, 0) is
+// the primary address.
+//
+func (k *ViewOnlyKeys) Subaddress(major, minor uint32) *Address {
+	if major == 0 && minor == 0 {
+		return k.PrimaryAddress()
+	}
+
+	spend := k.subaddressSpendKey(major, minor)
+
+	var a, d moneroutil.Key
+	copy(a[:], k.privateViewKey)
+	copy(d[:], spend)
+
+	point := new(moneroutil.ExtendedGroupElement)
+	point.FromBytes(&d)
+
+	view := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeScalarMult(view, &a, point)
+
+	var c moneroutil.Key
+	view.ToBytes(&c)
+
+	return &Address{
+		Network:        k.network,
+		Kind:           AddressSubaddress,
+		PublicSpendKey: spend,
+		PublicViewKey:  c[:],
+	}
+}
+
+// Subaddresses derives the `count` subaddresses of account `major` starting
+// at `minor`.
+//
+func (k *ViewOnlyKeys) Subaddresses(major, minor, count uint32) []*Address {
+	addrs := make([]*Address, 0, count)
+
+	for idx := uint32(0); idx < count; idx++ {
+		addrs = append(addrs, k.Subaddress(major, minor+idx))
+	}
+
+	return addrs
+}
+
+// SubaddressTable builds the lookup table of the first `indices`
+// subaddresses of the first `accounts` accounts of the wallet.
+//
+func (k *ViewOnlyKeys) SubaddressTable(accounts, indices uint32) SubaddressTable {
+	table := make(SubaddressTable, int(accounts)*int(indices))
+
+	for major := uint32(0); major < accounts; major++ {
+		for minor := uint32(0); minor < indices; minor++ {
+			var spend [KeySize]byte
+			copy(spend[:], k.subaddressSpendKey(major, minor))
+
+			table[spend] = SubaddressIndex{Major: major, Minor: minor}
+		}
+	}
+
+	return table
+}
+
+// subaddressSecret gives the secret `m` that the subaddress (`major`,
+// `minor`) is derived from.
+//
+func (k *ViewOnlyKeys) subaddressSecret(major, minor uint32) *moneroutil.Key {
+	indices := make([]byte, 8)
+	binary.LittleEndian.PutUint32(indices, major)
+	binary.LittleEndian.PutUint32(indices[4:], minor)
+
+	return moneroutil.HashToScalar(
+		[]byte(subaddressSecretPrefix), k.privateViewKey, indices,
+	)
+}
+
+// subaddressSpendKey gives the public spend key `D` of the subaddress
+// (`major`, `minor`).
+//
+func (k *ViewOnlyKeys) subaddressSpendKey(major, minor uint32) []byte {
+	if major == 0 && minor == 0 {
+		return k.publicSpendKey
+	}
+
+	var b, one moneroutil.Key
+	copy(b[:], k.publicSpendKey)
+	one[0] = 1
+
+	point := new(moneroutil.ExtendedGroupElement)
+	point.FromBytes(&b)
+
+	// D = 1*B + m*G
+	//
+	spend := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeDoubleScalarMultVartime(spend, &one, point,
+		k.subaddressSecret(major, minor))
+
+	var d moneroutil.Key
+	spend.ToBytes(&d)
+
+	return d[:]
+}
+
+// SubaddressTable maps the public spend keys of subaddresses to their
+// indices so that, when scanning, the subaddress an output has been sent to
+// can be found out.
+//
+type SubaddressTable map[[KeySize]byte]SubaddressIndex
+
+// Lookup gives the index of the subaddress whose public spend key is
+// `publicSpendKey`, if it's in the table.
+//
+func (t SubaddressTable) Lookup(publicSpendKey []byte) (SubaddressIndex, bool) {
+	if len(publicSpendKey) != KeySize {
+		return SubaddressIndex{}, false
+	}
+
+	var spend [KeySize]byte
+	copy(spend[:], publicSpendKey)
+
+	idx, found := t[spend]
+	return idx, found
+}
@@ -0,0 +1,217 @@
+package monero_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paxos-bankchain/moneroutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func TestSubaddress(t *testing.T) {
+	seed := monero.NewSeed(key(1), monero.WithNetwork(monero.NetworkStagenet))
+
+	primary := seed.Subaddress(0, 0)
+	assert.Equal(t, monero.AddressStandard, primary.Kind)
+	assert.Equal(t, seed.PrimaryAddress(), primary.String())
+
+	seen := map[string]bool{primary.String(): true}
+
+	for _, idx := range []monero.SubaddressIndex{
+		{Major: 0, Minor: 1},
+		{Major: 1, Minor: 0},
+		{Major: 1, Minor: 1},
+		{Major: 7, Minor: 1234},
+	} {
+		addr := seed.Subaddress(idx.Major, idx.Minor)
+		assert.Equal(t, monero.AddressSubaddress, addr.Kind, idx)
+		assert.Equal(t, monero.NetworkStagenet, addr.Network, idx)
+
+		parsed, err := monero.ParseAddress(addr.String())
+		require.NoError(t, err, idx)
+		assert.Equal(t, addr, parsed, idx)
+
+		assert.False(t, seen[addr.String()], idx)
+		seen[addr.String()] = true
+	}
+}
+
+// TestSubaddressKnownAnswers checks the addresses derived out of the seed of
+// monero's functional tests (`tests/functional_tests/wallet_address.py`)
+// against those that monero-wallet-rpc gives for it.
+//
+func TestSubaddressKnownAnswers(t *testing.T) {
+	seed, err := monero.NewSeedFromMnemonic(strings.Fields(
+		"velvet lymph giddy number token physics poetry unquoted nibs useful " +
+			"sabotage limits benches lifestyle eden nitrogen anvil fewest avoid " +
+			"batch vials washing fences goat unquoted",
+	))
+	require.NoError(t, err)
+
+	keys, err := monero.NewViewOnlyKeys(
+		seed.PrivateViewKey(), seed.PublicSpendKey(), monero.NetworkMainnet,
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		index    monero.SubaddressIndex
+		expected string
+	}{
+		{index: monero.SubaddressIndex{Major: 0, Minor: 0},
+			expected: "42ey1afDFnn4886T7196doS9GPMzexD9gXpsZJDwVjeRVdFCSoHnv7KPbBeGpzJBzHRCAs9UxqeoyFQMYbqSWYTfJJQAWDm",
+		},
+		{index: monero.SubaddressIndex{Major: 0, Minor: 1},
+			expected: "84QRUYawRNrU3NN1VpFRndSukeyEb3Xpv8qZjjsoJZnTYpDYceuUTpog13D7qPxpviS7J29bSgSkR11hFFoXWk2yNdsR9WF",
+		},
+		{index: monero.SubaddressIndex{Major: 1, Minor: 0},
+			expected: "82pP87g1Vkd3LUMssBCumk3MfyEsFqLAaGDf6oxddu61EgSFzt8gCwUD4tr3kp9TUfdPs2CnpD7xLZzyC1Ei9UsW3oyCWDf",
+		},
+	} {
+		assert.Equal(t, tc.expected,
+			seed.Subaddress(tc.index.Major, tc.index.Minor).String(), tc.index)
+		assert.Equal(t, tc.expected,
+			keys.Subaddress(tc.index.Major, tc.index.Minor).String(), tc.index)
+	}
+}
+
+func TestSubaddresses(t *testing.T) {
+	seed := monero.NewSeed(key(1))
+
+	addrs := seed.Subaddresses(2, 10, 5)
+	require.Len(t, addrs, 5)
+
+	for idx, addr := range addrs {
+		assert.Equal(t, seed.Subaddress(2, uint32(10+idx)), addr)
+	}
+}
+
+func TestViewOnlyKeys(t *testing.T) {
+	seed := monero.NewSeed(key(1), monero.WithNetwork(monero.NetworkTestnet))
+
+	keys, err := monero.NewViewOnlyKeys(
+		seed.PrivateViewKey(), seed.PublicSpendKey(), monero.NetworkTestnet,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, seed.PublicViewKey(), keys.PublicViewKey())
+	assert.Equal(t, seed.PrimaryAddress(), keys.PrimaryAddress().String())
+	assert.Equal(t, seed.Subaddresses(3, 0, 10), keys.Subaddresses(3, 0, 10))
+
+	_, err = monero.NewViewOnlyKeys(
+		seed.PrivateViewKey()[1:], seed.PublicSpendKey(), monero.NetworkTestnet,
+	)
+	assert.ErrorIs(t, err, monero.ErrInvalidKey)
+
+	_, err = monero.NewViewOnlyKeys(
+		key(0xff), seed.PublicSpendKey(), monero.NetworkTestnet,
+	)
+	assert.ErrorIs(t, err, monero.ErrInvalidKey)
+
+	notAPoint := make([]byte, monero.KeySize)
+	notAPoint[0] = 2
+
+	_, err = monero.NewViewOnlyKeys(
+		seed.PrivateViewKey(), notAPoint, monero.NetworkTestnet,
+	)
+	assert.ErrorIs(t, err, monero.ErrInvalidKey)
+}
+
+// TestSubaddressTable goes through what a sender and the receiving wallet
+// do with a subaddress: the former derives a one-time output key out of its
+// keys, and the latter recovers the subaddress spend key from that output
+// key and finds out (through the table) which subaddress it was sent to.
+//
+func TestSubaddressTable(t *testing.T) {
+	seed := monero.NewSeed(key(1))
+	table := seed.SubaddressTable(3, 20)
+
+	assert.Len(t, table, 3*20)
+
+	idx, found := table.Lookup(seed.PublicSpendKey())
+	assert.True(t, found)
+	assert.Equal(t, monero.SubaddressIndex{}, idx)
+
+	_, found = table.Lookup(seed.Subaddress(3, 0).PublicSpendKey)
+	assert.False(t, found)
+
+	_, found = table.Lookup([]byte{1, 2, 3})
+	assert.False(t, found)
+
+	for _, expected := range []monero.SubaddressIndex{
+		{Major: 0, Minor: 19},
+		{Major: 2, Minor: 0},
+		{Major: 1, Minor: 7},
+	} {
+		addr := seed.Subaddress(expected.Major, expected.Minor)
+
+		// sender: R = r*D, P = Hs(8*r*C)*G + D
+		//
+		r := moneroutil.RandomScalar()
+		txPublicKey := scalarMult(r, addr.PublicSpendKey)
+		derivation := scalarMult(r, addr.PublicViewKey)
+		outputKey := addScalarMultBase(
+			addr.PublicSpendKey, moneroutil.HashToScalar(mul8(derivation)),
+		)
+
+		// receiver: D = P - Hs(8*a*R)*G
+		//
+		var a moneroutil.Key
+		copy(a[:], seed.PrivateViewKey())
+
+		secret := moneroutil.HashToScalar(mul8(scalarMult(&a, txPublicKey)))
+
+		var zero, negSecret moneroutil.Key
+		moneroutil.ScSub(&negSecret, &zero, secret)
+
+		idx, found := table.Lookup(addScalarMultBase(outputKey, &negSecret))
+		assert.True(t, found, expected)
+		assert.Equal(t, expected, idx)
+	}
+}
+
+func scalarMult(s *moneroutil.Key, point []byte) []byte {
+	var p moneroutil.Key
+	copy(p[:], point)
+
+	ext := new(moneroutil.ExtendedGroupElement)
+	ext.FromBytes(&p)
+
+	res := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeScalarMult(res, s, ext)
+
+	var out moneroutil.Key
+	res.ToBytes(&out)
+
+	return out[:]
+}
+
+// addScalarMultBase gives point + s*G.
+//
+func addScalarMultBase(point []byte, s *moneroutil.Key) []byte {
+	var one moneroutil.Key
+	one[0] = 1
+
+	var p moneroutil.Key
+	copy(p[:], point)
+
+	ext := new(moneroutil.ExtendedGroupElement)
+	ext.FromBytes(&p)
+
+	res := new(moneroutil.ProjectiveGroupElement)
+	moneroutil.GeDoubleScalarMultVartime(res, &one, ext, s)
+
+	var out moneroutil.Key
+	res.ToBytes(&out)
+
+	return out[:]
+}
+
+func mul8(point []byte) []byte {
+	var eight moneroutil.Key
+	eight[0] = 8
+
+	return scalarMult(&eight, point)
+}
@@ -0,0 +1,689 @@
+package monero
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RctType is the type of the RingCT signatures of a transaction, telling the
+// kind of range proofs and ring signatures used.
+//
+type RctType uint8
+
+const (
+	// RctTypeNull is the type of transactions with no RingCT data, i.e.,
+	// coinbase transactions of version 2 onwards.
+	//
+	RctTypeNull RctType = iota
+
+	// RctTypeFull has a single MLSAG for all of the inputs and Borromean
+	// range proofs.
+	//
+	RctTypeFull
+
+	// RctTypeSimple has an MLSAG per input and Borromean range proofs.
+	//
+	RctTypeSimple
+
+	// RctTypeBulletproof has an MLSAG per input and one Bulletproof per
+	// output.
+	//
+	RctTypeBulletproof
+
+	// RctTypeBulletproof2 has an MLSAG per input, a single aggregated
+	// Bulletproof and compact (8 bytes) encrypted amounts.
+	//
+	RctTypeBulletproof2
+
+	// RctTypeCLSAG has a CLSAG per input and a single aggregated
+	// Bulletproof.
+	//
+	RctTypeCLSAG
+
+	// RctTypeBulletproofPlus has a CLSAG per input and a single
+	// aggregated Bulletproof+.
+	//
+	RctTypeBulletproofPlus
+)
+
+func (t RctType) String() string {
+	switch t {
+	case RctTypeNull:
+		return "Null"
+	case RctTypeFull:
+		return "Full"
+	case RctTypeSimple:
+		return "Simple"
+	case RctTypeBulletproof:
+		return "Bulletproof"
+	case RctTypeBulletproof2:
+		return "Bulletproof2"
+	case RctTypeCLSAG:
+		return "CLSAG"
+	case RctTypeBulletproofPlus:
+		return "BulletproofPlus"
+	}
+
+	return fmt.Sprintf("RctType(%d)", uint8(t))
+}
+
+// Transaction is a transaction of any version, as represented in the JSON
+// form given by monerod, either through its RPC interface (e.g., the
+// `as_json` field of `/get_transactions`), or through its zmq publications
+// (e.g., `json-full-txpool_add`), which lay it out differently. Both forms
+// are decoded into the same fields, while encoding always produces the RPC
+// form.
+//
+type Transaction struct {
+	// Version is the version of the transaction: 1 for those prior to
+	// RingCT, 2 for RingCT ones.
+	//
+	Version uint64 `json:"version"`
+
+	// UnlockTime is the block height (or, if larger than 500000000, the
+	// unix timestamp) before which the outputs can't be spent.
+	//
+	UnlockTime uint64 `json:"unlock_time"`
+
+	// Vin are the inputs of the transaction.
+	//
+	Vin []TxInput `json:"vin"`
+
+	// Vout are the outputs of the transaction.
+	//
+	Vout []TxOutput `json:"vout"`
+
+	// Extra is the free-form `tx_extra` field (holding, for instance,
+	// the transaction public key).
+	//
+	Extra TxExtra `json:"extra"`
+
+	// Signatures are the (hex-encoded) ring signatures of the inputs of
+	// version 1 transactions, one per input.
+	//
+	Signatures []string `json:"signatures,omitempty"`
+
+	// RctSignatures is the non-prunable part of the RingCT data of
+	// version 2 transactions.
+	//
+	RctSignatures RctSignatures `json:"rct_signatures"`
+
+	// RctSigPrunable is the prunable part of the RingCT data of version
+	// 2 transactions (nil for coinbase or pruned transactions).
+	//
+	RctSigPrunable *RctSigPrunable `json:"rctsig_prunable,omitempty"`
+}
+
+// Fee gives the fee paid by the transaction: the one explicitly set for
+// RingCT transactions, or the difference between the amounts going in and
+// out for those prior to it.
+//
+func (t *Transaction) Fee() uint64 {
+	if t.Version >= 2 {
+		return t.RctSignatures.TxnFee
+	}
+
+	var in, out uint64
+
+	for _, vin := range t.Vin {
+		if vin.Key != nil {
+			in += vin.Key.Amount
+		}
+	}
+
+	for _, vout := range t.Vout {
+		out += vout.Amount
+	}
+
+	if in < out {
+		return 0
+	}
+
+	return in - out
+}
+
+// IsCoinbase tells whether the transaction is a coinbase one (i.e., it has
+// a single `gen` input).
+//
+func (t *Transaction) IsCoinbase() bool {
+	return len(t.Vin) == 1 && t.Vin[0].Gen != nil
+}
+
+// zmqTransaction is the layout of transactions published over zmq.
+//
+type zmqTransaction struct {
+	Version    uint64     `json:"version"`
+	UnlockTime uint64     `json:"unlock_time"`
+	Inputs     []TxInput  `json:"inputs"`
+	Outputs    []TxOutput `json:"outputs"`
+	Extra      TxExtra    `json:"extra"`
+	Signatures [][]string `json:"signatures"`
+	Ringct     *zmqRctSig `json:"ringct"`
+}
+
+type zmqRctSig struct {
+	Type        RctType     `json:"type"`
+	Encrypted   []EcdhTuple `json:"encrypted"`
+	Commitments []string    `json:"commitments"`
+	Fee         uint64      `json:"fee"`
+	Prunable    *struct {
+		RangeProofs      []RangeSig        `json:"range_proofs"`
+		Bulletproofs     []Bulletproof     `json:"bulletproofs"`
+		BulletproofsPlus []BulletproofPlus `json:"bulletproofs_plus"`
+		MLSAGs           []MGSig           `json:"mlsags"`
+		CLSAGs           []CLSAG           `json:"clsags"`
+		PseudoOuts       []string          `json:"pseudo_outs"`
+	} `json:"prunable"`
+}
+
+// UnmarshalJSON decodes a transaction laid out either as in RPC responses or
+// as in zmq publications.
+//
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	_, hasInputs := fields["inputs"]
+	_, hasVin := fields["vin"]
+
+	if !hasInputs || hasVin {
+		type plain Transaction
+
+		return json.Unmarshal(data, (*plain)(t))
+	}
+
+	v := &zmqTransaction{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	*t = Transaction{
+		Version:    v.Version,
+		UnlockTime: v.UnlockTime,
+		Vin:        v.Inputs,
+		Vout:       v.Outputs,
+		Extra:      v.Extra,
+	}
+
+	for _, sigs := range v.Signatures {
+		t.Signatures = append(t.Signatures, strings.Join(sigs, ""))
+	}
+
+	if v.Ringct == nil {
+		return nil
+	}
+
+	t.RctSignatures = RctSignatures{
+		Type:     v.Ringct.Type,
+		TxnFee:   v.Ringct.Fee,
+		EcdhInfo: v.Ringct.Encrypted,
+		OutPk:    v.Ringct.Commitments,
+	}
+
+	if p := v.Ringct.Prunable; p != nil {
+		t.RctSigPrunable = &RctSigPrunable{
+			RangeSigs:  p.RangeProofs,
+			Bp:         p.Bulletproofs,
+			Bpp:        p.BulletproofsPlus,
+			MGs:        p.MLSAGs,
+			CLSAGs:     p.CLSAGs,
+			PseudoOuts: p.PseudoOuts,
+		}
+
+		t.RctSigPrunable.Nbp = uint64(len(p.Bulletproofs) + len(p.BulletproofsPlus))
+
+		if t.RctSignatures.Type == RctTypeSimple {
+			t.RctSignatures.PseudoOuts = p.PseudoOuts
+			t.RctSigPrunable.PseudoOuts = nil
+		}
+	}
+
+	return nil
+}
+
+// TxExtra is the raw content of the `tx_extra` field of a transaction. It's
+// represented in JSON as an array of bytes (as monerod's RPC does), but may
+// also be decoded from a hex-encoded string (as monerod's zmq does).
+//
+type TxExtra []byte
+
+// MarshalJSON encodes the field as an array of bytes.
+//
+func (e TxExtra) MarshalJSON() ([]byte, error) {
+	ints := make([]int, len(e))
+	for idx, b := range e {
+		ints[idx] = int(b)
+	}
+
+	return json.Marshal(ints)
+}
+
+// UnmarshalJSON decodes either an array of bytes or a hex-encoded string.
+//
+func (e *TxExtra) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("decode hex: %w", err)
+		}
+
+		*e = b
+		return nil
+	}
+
+	b := []byte{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+
+	*e = b
+	return nil
+}
+
+// TxInput is an input of a transaction, with a single one of its fields set
+// depending on its kind.
+//
+type TxInput struct {
+	// Gen is set for the input of coinbase transactions.
+	//
+	Gen *TxInputGen `json:"gen,omitempty"`
+
+	// Key is set for inputs spending a previous output.
+	//
+	Key *TxInputToKey `json:"key,omitempty"`
+
+	// Script and ScriptHash are set for the script-based inputs defined
+	// by the protocol but never used on the chain.
+	//
+	Script     *TxInputToScript     `json:"script,omitempty"`
+	ScriptHash *TxInputToScriptHash `json:"scripthash,omitempty"`
+}
+
+// UnmarshalJSON decodes an input laid out either as in RPC responses or as
+// in zmq publications (where it's named `to_key` rather than `key`).
+//
+func (i *TxInput) UnmarshalJSON(data []byte) error {
+	v := &struct {
+		Gen        *TxInputGen          `json:"gen"`
+		Key        *TxInputToKey        `json:"key"`
+		ToKey      *TxInputToKey        `json:"to_key"`
+		Script     *TxInputToScript     `json:"script"`
+		ScriptHash *TxInputToScriptHash `json:"scripthash"`
+	}{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	*i = TxInput{
+		Gen:        v.Gen,
+		Key:        v.Key,
+		Script:     v.Script,
+		ScriptHash: v.ScriptHash,
+	}
+
+	if i.Key == nil {
+		i.Key = v.ToKey
+	}
+
+	return nil
+}
+
+// TxInputGen is the input of a coinbase transaction.
+//
+type TxInputGen struct {
+	// Height is the height of the block the transaction is the coinbase
+	// of.
+	//
+	Height uint64 `json:"height"`
+}
+
+// TxInputToKey is an input spending one of the outputs of a ring.
+//
+type TxInputToKey struct {
+	// Amount is the amount spent (always 0 for RingCT inputs).
+	//
+	Amount uint64 `json:"amount"`
+
+	// KeyOffsets are the global indices of the ring members, each one
+	// relative to the previous one.
+	//
+	KeyOffsets []uint64 `json:"key_offsets"`
+
+	// KeyImage is the key image of the output spent.
+	//
+	KeyImage string `json:"k_image"`
+}
+
+// UnmarshalJSON decodes the input, taking the key image either from
+// `k_image` (RPC) or `key_image` (zmq).
+//
+func (i *TxInputToKey) UnmarshalJSON(data []byte) error {
+	type plain TxInputToKey
+
+	v := &struct {
+		*plain
+		KeyImage string `json:"key_image"`
+	}{plain: (*plain)(i)}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if i.KeyImage == "" {
+		i.KeyImage = v.KeyImage
+	}
+
+	return nil
+}
+
+// TxInputToScript is an input spending a script output.
+//
+type TxInputToScript struct {
+	Prev    string  `json:"prev"`
+	Prevout uint64  `json:"prevout"`
+	Sigset  TxExtra `json:"sigset"`
+}
+
+// TxInputToScriptHash is an input spending a script hash output.
+//
+type TxInputToScriptHash struct {
+	Prev    string           `json:"prev"`
+	Prevout uint64           `json:"prevout"`
+	Script  TxOutputToScript `json:"script"`
+	Sigset  TxExtra          `json:"sigset"`
+}
+
+// TxOutput is an output of a transaction.
+//
+type TxOutput struct {
+	// Amount is the amount of the output (always 0 for RingCT outputs
+	// other than coinbase ones).
+	//
+	Amount uint64 `json:"amount"`
+
+	// Target is who the output can be spent by.
+	//
+	Target TxOutputTarget `json:"target"`
+}
+
+// Key gives the one-time public key of the output, whether tagged or not.
+//
+func (o *TxOutput) Key() string {
+	if o.Target.TaggedKey != nil {
+		return o.Target.TaggedKey.Key
+	}
+
+	return o.Target.Key
+}
+
+// UnmarshalJSON decodes an output laid out either as in RPC responses or as
+// in zmq publications (where the target is set at the top level, named
+// `to_key` or `to_tagged_key`).
+//
+func (o *TxOutput) UnmarshalJSON(data []byte) error {
+	v := &struct {
+		Amount      uint64               `json:"amount"`
+		Target      *TxOutputTarget      `json:"target"`
+		ToKey       *TxOutputTarget      `json:"to_key"`
+		ToTaggedKey *TxOutputToTaggedKey `json:"to_tagged_key"`
+	}{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	*o = TxOutput{Amount: v.Amount}
+
+	switch {
+	case v.Target != nil:
+		o.Target = *v.Target
+	case v.ToKey != nil:
+		o.Target.Key = v.ToKey.Key
+	case v.ToTaggedKey != nil:
+		o.Target.TaggedKey = v.ToTaggedKey
+	}
+
+	return nil
+}
+
+// TxOutputTarget is the target of an output, with a single one of its fields
+// set depending on its kind.
+//
+type TxOutputTarget struct {
+	// Key is the one-time public key of outputs prior to view tags.
+	//
+	Key string `json:"key,omitempty"`
+
+	// TaggedKey is set for outputs with a view tag (since the view tags
+	// hard fork, i.e., version 15).
+	//
+	TaggedKey *TxOutputToTaggedKey `json:"tagged_key,omitempty"`
+
+	// Script and ScriptHash are set for the script-based outputs defined
+	// by the protocol but never used on the chain.
+	//
+	Script     *TxOutputToScript     `json:"script,omitempty"`
+	ScriptHash *TxOutputToScriptHash `json:"scripthash,omitempty"`
+}
+
+// TxOutputToTaggedKey is the target of an output with a view tag.
+//
+type TxOutputToTaggedKey struct {
+	// Key is the one-time public key of the output.
+	//
+	Key string `json:"key"`
+
+	// ViewTag is the (hex-encoded) first byte of the hash of the shared
+	// secret, letting wallets skip most outputs not theirs cheaply.
+	//
+	ViewTag string `json:"view_tag"`
+}
+
+// TxOutputToScript is the target of a script output.
+//
+type TxOutputToScript struct {
+	Keys   []string `json:"keys"`
+	Script TxExtra  `json:"script"`
+}
+
+// TxOutputToScriptHash is the target of a script hash output.
+//
+type TxOutputToScriptHash struct {
+	Hash string `json:"hash"`
+}
+
+// RctSignatures is the non-prunable part of the RingCT data of a
+// transaction.
+//
+type RctSignatures struct {
+	// Type is the type of RingCT data.
+	//
+	Type RctType `json:"type"`
+
+	// TxnFee is the fee paid by the transaction.
+	//
+	TxnFee uint64 `json:"txnFee,omitempty"`
+
+	// PseudoOuts are the pseudo output commitments of the inputs of
+	// `RctTypeSimple` transactions (later types carry them in the
+	// prunable part instead).
+	//
+	PseudoOuts []string `json:"pseudoOuts,omitempty"`
+
+	// EcdhInfo are the encrypted amounts (and, prior to
+	// `RctTypeBulletproof2`, masks) of the outputs.
+	//
+	EcdhInfo []EcdhTuple `json:"ecdhInfo,omitempty"`
+
+	// OutPk are the Pedersen commitments to the amounts of the outputs.
+	//
+	OutPk []string `json:"outPk,omitempty"`
+}
+
+// EcdhTuple is the encrypted amount (and mask) of an output.
+//
+type EcdhTuple struct {
+	// Mask is the encrypted mask, unset from `RctTypeBulletproof2`
+	// onwards (where it's deterministically derived instead).
+	//
+	Mask string `json:"mask,omitempty"`
+
+	// Amount is the encrypted amount: 32 bytes prior to
+	// `RctTypeBulletproof2`, 8 bytes from it onwards.
+	//
+	Amount string `json:"amount"`
+}
+
+// RctSigPrunable is the prunable part of the RingCT data of a transaction:
+// range proofs and ring signatures.
+//
+type RctSigPrunable struct {
+	// Nbp is the number of Bulletproofs (or Bulletproofs+).
+	//
+	Nbp uint64 `json:"nbp,omitempty"`
+
+	// RangeSigs are the Borromean range proofs of `RctTypeFull` and
+	// `RctTypeSimple` transactions, one per output.
+	//
+	RangeSigs []RangeSig `json:"rangeSigs,omitempty"`
+
+	// Bp are the Bulletproofs of `RctTypeBulletproof`,
+	// `RctTypeBulletproof2` and `RctTypeCLSAG` transactions.
+	//
+	Bp []Bulletproof `json:"bp,omitempty"`
+
+	// Bpp are the Bulletproofs+ of `RctTypeBulletproofPlus`
+	// transactions.
+	//
+	Bpp []BulletproofPlus `json:"bpp,omitempty"`
+
+	// MGs are the MLSAG ring signatures of transactions prior to
+	// `RctTypeCLSAG`.
+	//
+	MGs []MGSig `json:"MGs,omitempty"`
+
+	// CLSAGs are the CLSAG ring signatures from `RctTypeCLSAG` onwards.
+	//
+	CLSAGs []CLSAG `json:"CLSAGs,omitempty"`
+
+	// PseudoOuts are the pseudo output commitments of the inputs (from
+	// `RctTypeBulletproof` onwards).
+	//
+	PseudoOuts []string `json:"pseudoOuts,omitempty"`
+}
+
+// RangeSig is a Borromean range proof.
+//
+type RangeSig struct {
+	// Asig is the (hex-encoded) Borromean signature: `s0 || s1 || ee`.
+	//
+	Asig string `json:"asig"`
+
+	// Ci are the (hex-encoded, concatenated) commitments to each bit of
+	// the amount.
+	//
+	Ci string `json:"Ci"`
+}
+
+// UnmarshalJSON decodes the range proof either as blobs (RPC) or as its
+// separate keys (zmq).
+//
+func (r *RangeSig) UnmarshalJSON(data []byte) error {
+	v := &struct {
+		Asig json.RawMessage `json:"asig"`
+		Ci   json.RawMessage `json:"Ci"`
+	}{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(v.Asig, []byte(`{`)) {
+		asig := &struct {
+			S0 []string `json:"s0"`
+			S1 []string `json:"s1"`
+			EE string   `json:"ee"`
+		}{}
+		if err := json.Unmarshal(v.Asig, asig); err != nil {
+			return fmt.Errorf("asig: %w", err)
+		}
+
+		r.Asig = strings.Join(asig.S0, "") + strings.Join(asig.S1, "") + asig.EE
+	} else if err := json.Unmarshal(v.Asig, &r.Asig); err != nil {
+		return fmt.Errorf("asig: %w", err)
+	}
+
+	if bytes.HasPrefix(v.Ci, []byte(`[`)) {
+		ci := []string{}
+		if err := json.Unmarshal(v.Ci, &ci); err != nil {
+			return fmt.Errorf("ci: %w", err)
+		}
+
+		r.Ci = strings.Join(ci, "")
+	} else if err := json.Unmarshal(v.Ci, &r.Ci); err != nil {
+		return fmt.Errorf("ci: %w", err)
+	}
+
+	return nil
+}
+
+// Bulletproof is an (aggregated) Bulletproof range proof.
+//
+type Bulletproof struct {
+	// V are the commitments proven, not part of the serialized form
+	// (they're the output commitments) and hence only present in zmq
+	// publications.
+	//
+	V []string `json:"V,omitempty"`
+
+	A    string   `json:"A"`
+	S    string   `json:"S"`
+	T1   string   `json:"T1"`
+	T2   string   `json:"T2"`
+	Taux string   `json:"taux"`
+	Mu   string   `json:"mu"`
+	L    []string `json:"L"`
+	R    []string `json:"R"`
+	LowA string   `json:"a"`
+	B    string   `json:"b"`
+	T    string   `json:"t"`
+}
+
+// BulletproofPlus is an (aggregated) Bulletproof+ range proof.
+//
+type BulletproofPlus struct {
+	// V are the commitments proven, only present in zmq publications.
+	//
+	V []string `json:"V,omitempty"`
+
+	A  string   `json:"A"`
+	A1 string   `json:"A1"`
+	B  string   `json:"B"`
+	R1 string   `json:"r1"`
+	S1 string   `json:"s1"`
+	D1 string   `json:"d1"`
+	L  []string `json:"L"`
+	R  []string `json:"R"`
+}
+
+// MGSig is an MLSAG ring signature.
+//
+type MGSig struct {
+	Ss [][]string `json:"ss"`
+	Cc string     `json:"cc"`
+}
+
+// CLSAG is a CLSAG ring signature.
+//
+type CLSAG struct {
+	S  []string `json:"s"`
+	C1 string   `json:"c1"`
+	D  string   `json:"D"`
+}
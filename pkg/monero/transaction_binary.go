@@ -0,0 +1,588 @@
+package monero
+
+import (
+	"fmt"
+)
+
+// Tags identifying the variant of inputs and outputs in the binary
+// serialization.
+//
+const (
+	txInTagToScript     byte = 0x00
+	txInTagToScriptHash byte = 0x01
+	txInTagToKey        byte = 0x02
+	txInTagGen          byte = 0xff
+
+	txOutTagToScript     byte = 0x00
+	txOutTagToScriptHash byte = 0x01
+	txOutTagToKey        byte = 0x02
+	txOutTagToTaggedKey  byte = 0x03
+)
+
+const (
+	// signatureSize is the size of each of the ring members' signature
+	// of version 1 transactions.
+	//
+	signatureSize = 2 * KeySize
+
+	// rangeSigAsigSize and rangeSigCiSize are the sizes of the parts of a
+	// Borromean range proof (64 bits, each with its own keys).
+	//
+	rangeSigAsigSize = (64 + 64 + 1) * KeySize
+	rangeSigCiSize   = 64 * KeySize
+
+	// ecdhCompactAmountSize is the size of the encrypted amounts from
+	// `RctTypeBulletproof2` onwards.
+	//
+	ecdhCompactAmountSize = 8
+
+	viewTagSize = 1
+)
+
+// DecodeTransaction decodes a transaction from its binary serialization
+// (e.g., the `as_hex` field of `/get_transactions`).
+//
+// Pruned transactions (e.g., `pruned_as_hex`) are decoded as well, having no
+// `Signatures` (version 1) or `RctSigPrunable` (version 2).
+//
+func DecodeTransaction(blob []byte) (*Transaction, error) {
+	d := &decoder{b: blob}
+
+	tx := &Transaction{}
+	tx.decode(d, true)
+
+	if d.err == nil && d.remaining() != 0 {
+		d.fail("%d trailing bytes", d.remaining())
+	}
+
+	if d.err != nil {
+		return nil, fmt.Errorf("decode transaction: %w", d.err)
+	}
+
+	return tx, nil
+}
+
+// Encode gives the binary serialization of the transaction, pruned if it
+// lacks the signatures (version 1) or the prunable RingCT data (version 2).
+//
+func (t *Transaction) Encode() ([]byte, error) {
+	e := &encoder{}
+	t.encode(e)
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode transaction: %w", e.err)
+	}
+
+	return e.b, nil
+}
+
+// EncodePrefix gives the binary serialization of the prefix of the
+// transaction: everything but the signatures.
+//
+func (t *Transaction) EncodePrefix() ([]byte, error) {
+	e := &encoder{}
+	t.encodePrefix(e)
+
+	if e.err != nil {
+		return nil, fmt.Errorf("encode transaction prefix: %w", e.err)
+	}
+
+	return e.b, nil
+}
+
+func (t *Transaction) decode(d *decoder, allowPruned bool) {
+	t.decodePrefix(d)
+
+	if t.Version == 1 {
+		if !t.hasSignatures() || (allowPruned && d.remaining() == 0) {
+			return
+		}
+
+		t.Signatures = make([]string, len(t.Vin))
+		for idx, vin := range t.Vin {
+			t.Signatures[idx] = d.hex(signatureSize * vin.ringSize())
+		}
+
+		return
+	}
+
+	t.RctSignatures.decode(d, len(t.Vin), len(t.Vout))
+
+	if t.RctSignatures.Type == RctTypeNull || (allowPruned && d.remaining() == 0) {
+		return
+	}
+
+	t.RctSigPrunable = &RctSigPrunable{}
+	t.RctSigPrunable.decode(d, t.RctSignatures.Type,
+		len(t.Vin), len(t.Vout), t.ringSize())
+}
+
+func (t *Transaction) encode(e *encoder) {
+	t.encodePrefix(e)
+
+	if t.Version == 1 {
+		if !t.hasSignatures() || t.Signatures == nil {
+			return
+		}
+
+		if len(t.Signatures) != len(t.Vin) {
+			e.fail("%d signatures for %d inputs", len(t.Signatures), len(t.Vin))
+			return
+		}
+
+		for idx, vin := range t.Vin {
+			e.hex(t.Signatures[idx], signatureSize*vin.ringSize())
+		}
+
+		return
+	}
+
+	t.RctSignatures.encode(e, len(t.Vin), len(t.Vout))
+
+	if t.RctSignatures.Type == RctTypeNull || t.RctSigPrunable == nil {
+		return
+	}
+
+	t.RctSigPrunable.encode(e, t.RctSignatures.Type,
+		len(t.Vin), len(t.Vout), t.ringSize())
+}
+
+func (t *Transaction) decodePrefix(d *decoder) {
+	t.Version = d.uvarint()
+	if d.err == nil && t.Version != 1 && t.Version != 2 {
+		d.fail("unsupported version %d", t.Version)
+		return
+	}
+
+	t.UnlockTime = d.uvarint()
+
+	t.Vin = make([]TxInput, d.count(1))
+	for idx := range t.Vin {
+		t.Vin[idx].decode(d)
+	}
+
+	t.Vout = make([]TxOutput, d.count(1))
+	for idx := range t.Vout {
+		t.Vout[idx].decode(d)
+	}
+
+	t.Extra = d.blob()
+}
+
+func (t *Transaction) encodePrefix(e *encoder) {
+	e.uvarint(t.Version)
+	e.uvarint(t.UnlockTime)
+
+	e.uvarint(uint64(len(t.Vin)))
+	for idx := range t.Vin {
+		t.Vin[idx].encode(e)
+	}
+
+	e.uvarint(uint64(len(t.Vout)))
+	for idx := range t.Vout {
+		t.Vout[idx].encode(e)
+	}
+
+	e.blob(t.Extra)
+}
+
+// hasSignatures tells whether a version 1 transaction carries signatures,
+// i.e., whether any of its inputs spends a ring.
+//
+func (t *Transaction) hasSignatures() bool {
+	for _, vin := range t.Vin {
+		if vin.ringSize() != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ringSize gives the ring size that the RingCT signatures are laid out for,
+// which, as all inputs must have the same, is taken from the first one.
+//
+func (t *Transaction) ringSize() int {
+	if len(t.Vin) == 0 || t.Vin[0].Key == nil {
+		return 1
+	}
+
+	return len(t.Vin[0].Key.KeyOffsets)
+}
+
+// ringSize gives the number of ring members of the input (none for those
+// other than `Key`).
+//
+func (i *TxInput) ringSize() int {
+	if i.Key == nil {
+		return 0
+	}
+
+	return len(i.Key.KeyOffsets)
+}
+
+func (i *TxInput) decode(d *decoder) {
+	switch tag := d.byte(); tag {
+	case txInTagGen:
+		i.Gen = &TxInputGen{Height: d.uvarint()}
+
+	case txInTagToKey:
+		i.Key = &TxInputToKey{Amount: d.uvarint()}
+
+		i.Key.KeyOffsets = make([]uint64, d.count(1))
+		for idx := range i.Key.KeyOffsets {
+			i.Key.KeyOffsets[idx] = d.uvarint()
+		}
+
+		i.Key.KeyImage = d.key()
+
+	case txInTagToScript:
+		i.Script = &TxInputToScript{Prev: d.key()}
+		i.Script.Prevout = d.uvarint()
+		i.Script.Sigset = d.blob()
+
+	case txInTagToScriptHash:
+		i.ScriptHash = &TxInputToScriptHash{Prev: d.key()}
+		i.ScriptHash.Prevout = d.uvarint()
+		i.ScriptHash.Script.decode(d)
+		i.ScriptHash.Sigset = d.blob()
+
+	default:
+		d.fail("unknown input tag 0x%02x", tag)
+	}
+}
+
+func (i *TxInput) encode(e *encoder) {
+	switch {
+	case i.Gen != nil:
+		e.byte(txInTagGen)
+		e.uvarint(i.Gen.Height)
+
+	case i.Key != nil:
+		e.byte(txInTagToKey)
+		e.uvarint(i.Key.Amount)
+
+		e.uvarint(uint64(len(i.Key.KeyOffsets)))
+		for _, offset := range i.Key.KeyOffsets {
+			e.uvarint(offset)
+		}
+
+		e.key(i.Key.KeyImage)
+
+	case i.Script != nil:
+		e.byte(txInTagToScript)
+		e.key(i.Script.Prev)
+		e.uvarint(i.Script.Prevout)
+		e.blob(i.Script.Sigset)
+
+	case i.ScriptHash != nil:
+		e.byte(txInTagToScriptHash)
+		e.key(i.ScriptHash.Prev)
+		e.uvarint(i.ScriptHash.Prevout)
+		i.ScriptHash.Script.encode(e)
+		e.blob(i.ScriptHash.Sigset)
+
+	default:
+		e.fail("input of no kind")
+	}
+}
+
+func (o *TxOutput) decode(d *decoder) {
+	o.Amount = d.uvarint()
+
+	switch tag := d.byte(); tag {
+	case txOutTagToKey:
+		o.Target.Key = d.key()
+
+	case txOutTagToTaggedKey:
+		o.Target.TaggedKey = &TxOutputToTaggedKey{Key: d.key()}
+		o.Target.TaggedKey.ViewTag = d.hex(viewTagSize)
+
+	case txOutTagToScript:
+		o.Target.Script = &TxOutputToScript{}
+		o.Target.Script.decode(d)
+
+	case txOutTagToScriptHash:
+		o.Target.ScriptHash = &TxOutputToScriptHash{Hash: d.key()}
+
+	default:
+		d.fail("unknown output tag 0x%02x", tag)
+	}
+}
+
+func (o *TxOutput) encode(e *encoder) {
+	e.uvarint(o.Amount)
+
+	switch target := o.Target; {
+	case target.TaggedKey != nil:
+		e.byte(txOutTagToTaggedKey)
+		e.key(target.TaggedKey.Key)
+		e.hex(target.TaggedKey.ViewTag, viewTagSize)
+
+	case target.Key != "":
+		e.byte(txOutTagToKey)
+		e.key(target.Key)
+
+	case target.Script != nil:
+		e.byte(txOutTagToScript)
+		target.Script.encode(e)
+
+	case target.ScriptHash != nil:
+		e.byte(txOutTagToScriptHash)
+		e.key(target.ScriptHash.Hash)
+
+	default:
+		e.fail("output of no kind")
+	}
+}
+
+func (s *TxOutputToScript) decode(d *decoder) {
+	s.Keys = d.keyVector()
+	s.Script = d.blob()
+}
+
+func (s *TxOutputToScript) encode(e *encoder) {
+	e.keyVector(s.Keys)
+	e.blob(s.Script)
+}
+
+// compactEcdh tells whether the encrypted amounts are in the compact form
+// (just the 8 bytes of the amount, no mask).
+//
+func (t RctType) compactEcdh() bool {
+	return t >= RctTypeBulletproof2
+}
+
+func (r *RctSignatures) decode(d *decoder, inputs, outputs int) {
+	r.Type = RctType(d.byte())
+	if d.err != nil || r.Type == RctTypeNull {
+		return
+	}
+
+	if r.Type > RctTypeBulletproofPlus {
+		d.fail("unknown rct type %d", r.Type)
+		return
+	}
+
+	r.TxnFee = d.uvarint()
+
+	if r.Type == RctTypeSimple {
+		r.PseudoOuts = d.keys(inputs)
+	}
+
+	r.EcdhInfo = make([]EcdhTuple, outputs)
+	for idx := range r.EcdhInfo {
+		if r.Type.compactEcdh() {
+			r.EcdhInfo[idx].Amount = d.hex(ecdhCompactAmountSize)
+			continue
+		}
+
+		r.EcdhInfo[idx].Mask = d.key()
+		r.EcdhInfo[idx].Amount = d.key()
+	}
+
+	r.OutPk = d.keys(outputs)
+}
+
+func (r *RctSignatures) encode(e *encoder, inputs, outputs int) {
+	e.byte(byte(r.Type))
+	if r.Type == RctTypeNull {
+		return
+	}
+
+	e.uvarint(r.TxnFee)
+
+	if r.Type == RctTypeSimple {
+		e.keys(r.PseudoOuts, inputs)
+	}
+
+	if len(r.EcdhInfo) != outputs {
+		e.fail("%d ecdh tuples for %d outputs", len(r.EcdhInfo), outputs)
+		return
+	}
+
+	for _, ecdh := range r.EcdhInfo {
+		if r.Type.compactEcdh() {
+			e.hex(ecdh.Amount, ecdhCompactAmountSize)
+			continue
+		}
+
+		e.key(ecdh.Mask)
+		e.key(ecdh.Amount)
+	}
+
+	e.keys(r.OutPk, outputs)
+}
+
+// mgLayout gives the number of MLSAGs and the number of columns in each of
+// their rows for the (pre-CLSAG) type.
+//
+func (t RctType) mgLayout(inputs int) (int, int) {
+	if t == RctTypeFull {
+		return 1, inputs + 1
+	}
+
+	return inputs, 2
+}
+
+// nolint:gocyclo
+func (p *RctSigPrunable) decode(d *decoder, typ RctType, inputs, outputs, ringSize int) {
+	switch typ {
+	case RctTypeBulletproofPlus:
+		p.Nbp = d.uvarint()
+
+		p.Bpp = make([]BulletproofPlus, d.bound(p.Nbp, 8*KeySize))
+		for idx := range p.Bpp {
+			p.Bpp[idx].decode(d)
+		}
+
+	case RctTypeBulletproof, RctTypeBulletproof2, RctTypeCLSAG:
+		if typ == RctTypeBulletproof {
+			p.Nbp = uint64(d.uint32())
+		} else {
+			p.Nbp = d.uvarint()
+		}
+
+		p.Bp = make([]Bulletproof, d.bound(p.Nbp, 11*KeySize))
+		for idx := range p.Bp {
+			p.Bp[idx].decode(d)
+		}
+
+	default:
+		p.RangeSigs = make([]RangeSig, outputs)
+		for idx := range p.RangeSigs {
+			p.RangeSigs[idx].Asig = d.hex(rangeSigAsigSize)
+			p.RangeSigs[idx].Ci = d.hex(rangeSigCiSize)
+		}
+	}
+
+	if typ >= RctTypeCLSAG {
+		p.CLSAGs = make([]CLSAG, d.bound(uint64(inputs), (ringSize+2)*KeySize))
+		for idx := range p.CLSAGs {
+			p.CLSAGs[idx].S = d.keys(ringSize)
+			p.CLSAGs[idx].C1 = d.key()
+			p.CLSAGs[idx].D = d.key()
+		}
+	} else {
+		mgs, cols := typ.mgLayout(inputs)
+
+		p.MGs = make([]MGSig, d.bound(uint64(mgs), (ringSize*cols+1)*KeySize))
+		for idx := range p.MGs {
+			p.MGs[idx].Ss = make([][]string, ringSize)
+			for row := range p.MGs[idx].Ss {
+				p.MGs[idx].Ss[row] = d.keys(cols)
+			}
+
+			p.MGs[idx].Cc = d.key()
+		}
+	}
+
+	if typ >= RctTypeBulletproof {
+		p.PseudoOuts = d.keys(inputs)
+	}
+}
+
+// nolint:gocyclo
+func (p *RctSigPrunable) encode(e *encoder, typ RctType, inputs, outputs, ringSize int) {
+	switch typ {
+	case RctTypeBulletproofPlus:
+		e.uvarint(uint64(len(p.Bpp)))
+		for idx := range p.Bpp {
+			p.Bpp[idx].encode(e)
+		}
+
+	case RctTypeBulletproof, RctTypeBulletproof2, RctTypeCLSAG:
+		if typ == RctTypeBulletproof {
+			e.uint32(uint32(len(p.Bp)))
+		} else {
+			e.uvarint(uint64(len(p.Bp)))
+		}
+
+		for idx := range p.Bp {
+			p.Bp[idx].encode(e)
+		}
+
+	default:
+		if len(p.RangeSigs) != outputs {
+			e.fail("%d range proofs for %d outputs", len(p.RangeSigs), outputs)
+			return
+		}
+
+		for _, sig := range p.RangeSigs {
+			e.hex(sig.Asig, rangeSigAsigSize)
+			e.hex(sig.Ci, rangeSigCiSize)
+		}
+	}
+
+	if typ >= RctTypeCLSAG {
+		if len(p.CLSAGs) != inputs {
+			e.fail("%d clsags for %d inputs", len(p.CLSAGs), inputs)
+			return
+		}
+
+		for _, clsag := range p.CLSAGs {
+			e.keys(clsag.S, ringSize)
+			e.key(clsag.C1)
+			e.key(clsag.D)
+		}
+	} else {
+		mgs, cols := typ.mgLayout(inputs)
+
+		if len(p.MGs) != mgs {
+			e.fail("%d mlsags where %d expected", len(p.MGs), mgs)
+			return
+		}
+
+		for _, mg := range p.MGs {
+			if len(mg.Ss) != ringSize {
+				e.fail("mlsag of %d rows for ring size %d", len(mg.Ss), ringSize)
+				return
+			}
+
+			for _, row := range mg.Ss {
+				e.keys(row, cols)
+			}
+
+			e.key(mg.Cc)
+		}
+	}
+
+	if typ >= RctTypeBulletproof {
+		e.keys(p.PseudoOuts, inputs)
+	}
+}
+
+func (b *Bulletproof) decode(d *decoder) {
+	b.A, b.S, b.T1, b.T2 = d.key(), d.key(), d.key(), d.key()
+	b.Taux, b.Mu = d.key(), d.key()
+	b.L, b.R = d.keyVector(), d.keyVector()
+	b.LowA, b.B, b.T = d.key(), d.key(), d.key()
+}
+
+func (b *Bulletproof) encode(e *encoder) {
+	for _, key := range []string{b.A, b.S, b.T1, b.T2, b.Taux, b.Mu} {
+		e.key(key)
+	}
+
+	e.keyVector(b.L)
+	e.keyVector(b.R)
+
+	for _, key := range []string{b.LowA, b.B, b.T} {
+		e.key(key)
+	}
+}
+
+func (b *BulletproofPlus) decode(d *decoder) {
+	b.A, b.A1, b.B = d.key(), d.key(), d.key()
+	b.R1, b.S1, b.D1 = d.key(), d.key(), d.key()
+	b.L, b.R = d.keyVector(), d.keyVector()
+}
+
+func (b *BulletproofPlus) encode(e *encoder) {
+	for _, key := range []string{b.A, b.A1, b.B, b.R1, b.S1, b.D1} {
+		e.key(key)
+	}
+
+	e.keyVector(b.L)
+	e.keyVector(b.R)
+}
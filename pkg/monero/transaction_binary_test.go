@@ -0,0 +1,608 @@
+package monero_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+// fixture gives the blob in the hex-encoded testdata file `name`.
+//
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", name+".hex"))
+	require.NoError(t, err)
+
+	blob, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	require.NoError(t, err)
+
+	return blob
+}
+
+func hexKey(b byte) string {
+	return hex.EncodeToString(key(b))
+}
+
+func hexKeys(bs ...byte) []string {
+	res := make([]string, len(bs))
+	for idx, b := range bs {
+		res[idx] = hexKey(b)
+	}
+
+	return res
+}
+
+// nolint:funlen
+func TestDecodeTransactionMainnet(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		fixture string
+		check   func(t *testing.T, tx *monero.Transaction)
+	}{
+		{name: "v1 coinbase (block 15)",
+
+			fixture: "tx-e3a799da24d9f41aac231ba2efb853ae649283feaf5e1ba46b5fc2c194414c5d",
+			check: func(t *testing.T, tx *monero.Transaction) {
+				assert.EqualValues(t, 1, tx.Version)
+				assert.True(t, tx.IsCoinbase())
+				assert.EqualValues(t, 15, tx.Vin[0].Gen.Height)
+				assert.Len(t, tx.Vout, 9)
+				assert.Equal(t, "a3ef0df2a51b14891676b39f18d6c0b8c52bfc3f6ba5f63f792e993f73b900df",
+					tx.Vout[0].Key())
+				assert.Empty(t, tx.Signatures)
+
+				sum := uint64(0)
+				for _, vout := range tx.Vout {
+					sum += vout.Amount
+				}
+				assert.EqualValues(t, 17591934387855, sum)
+			},
+		},
+
+		{name: "v2 coinbase (block 1302238)",
+
+			fixture: "tx-be30ee0ac38d83c86d84326c64b13eea5b40897a321004d17e589241d49199f7",
+			check: func(t *testing.T, tx *monero.Transaction) {
+				assert.EqualValues(t, 2, tx.Version)
+				assert.True(t, tx.IsCoinbase())
+				assert.EqualValues(t, 1302238, tx.Vin[0].Gen.Height)
+				assert.Equal(t, monero.RctTypeNull, tx.RctSignatures.Type)
+				assert.Nil(t, tx.RctSigPrunable)
+			},
+		},
+
+		{name: "v1 with 17 inputs (block 40646)",
+
+			fixture: "tx-ca9ea576d67af4926e31ebeb159aaee58950aea18e5e0ad0bae23b2d85ede8c1",
+			check: func(t *testing.T, tx *monero.Transaction) {
+				assert.EqualValues(t, 1, tx.Version)
+				assert.Len(t, tx.Vin, 17)
+				assert.Equal(t, "c9679ba9ca8a6fa87a1352985e46ea3723489d3699ab1af075532f711739b9c5",
+					tx.Vin[0].Key.KeyImage)
+				require.Len(t, tx.Signatures, 17)
+				assert.Len(t, tx.Signatures[0], 2*64*len(tx.Vin[0].Key.KeyOffsets))
+			},
+		},
+
+		{name: "v2 simple with borromean range proofs (block 1302238)",
+
+			fixture: "tx-be9d2cf9b473dbbb2c59ffb07b5d812516f94d64121d87ad61956386a4bc3843",
+			check: func(t *testing.T, tx *monero.Transaction) {
+				assert.Equal(t, monero.RctTypeSimple, tx.RctSignatures.Type)
+				assert.Len(t, tx.RctSignatures.PseudoOuts, len(tx.Vin))
+				assert.Len(t, tx.RctSignatures.EcdhInfo, len(tx.Vout))
+
+				require.NotNil(t, tx.RctSigPrunable)
+				assert.Len(t, tx.RctSigPrunable.RangeSigs, len(tx.Vout))
+				assert.Len(t, tx.RctSigPrunable.MGs, len(tx.Vin))
+				assert.Len(t, tx.RctSigPrunable.MGs[0].Ss, 3)
+			},
+		},
+
+		{name: "v2 simple with 2 inputs (ring size 3)",
+
+			fixture: "tx-7197cbfd111e8c2174d8833b3a87c723dc8b394abc1faa9175bd73ff5f4d16b5",
+			check: func(t *testing.T, tx *monero.Transaction) {
+				assert.Equal(t, monero.RctTypeSimple, tx.RctSignatures.Type)
+				assert.EqualValues(t, 24700000000, tx.RctSignatures.TxnFee)
+
+				require.Len(t, tx.Vin, 2)
+				assert.Equal(t, []uint64{460253, 125368, 181217}, tx.Vin[0].Key.KeyOffsets)
+				assert.Equal(t, "101168bda2047709a950de3473adc8775883e9442beb6dfa0cca023d82ea8958",
+					tx.Vin[0].Key.KeyImage)
+				assert.Len(t, tx.RctSignatures.PseudoOuts, 2)
+
+				require.Len(t, tx.Vout, 3)
+				assert.Equal(t, "64d3ed9b6ca4a0ddb4f1eca8360d37dbbb9da7c807caa327037799538c9d6cc5",
+					tx.Vout[0].Key())
+
+				require.NotNil(t, tx.RctSigPrunable)
+				assert.Len(t, tx.RctSigPrunable.RangeSigs, 3)
+				assert.Len(t, tx.RctSigPrunable.MGs, 2)
+				assert.Len(t, tx.RctSigPrunable.MGs[0].Ss, 3)
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blob := fixture(t, tc.fixture)
+
+			tx, err := monero.DecodeTransaction(blob)
+			require.NoError(t, err)
+			tc.check(t, tx)
+
+			encoded, err := tx.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, blob, encoded)
+		})
+	}
+}
+
+func TestDecodeBlockMainnet(t *testing.T) {
+	t.Parallel()
+
+	blob := fixture(t, "block-418015bb9ae982a1975da7d79277c2705727a56894ba0fb246adaabb1f4632e3")
+
+	block, err := monero.DecodeBlock(blob)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, block.MajorVersion)
+	assert.EqualValues(t, 10000, block.Nonce)
+	assert.Equal(t, hexKey(0), block.PrevID)
+	assert.EqualValues(t, 17592186044415, block.MinerOutputs())
+	assert.Empty(t, block.TxHashes)
+
+	encoded, err := block.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, blob, encoded)
+
+	header, err := block.EncodeHeader()
+	require.NoError(t, err)
+	assert.Equal(t, blob[:3+32+4], header)
+}
+
+// nolint:funlen
+func TestTransactionBinaryRoundTrip(t *testing.T) {
+	vin := []monero.TxInput{
+		{Key: &monero.TxInputToKey{KeyOffsets: []uint64{300, 2}, KeyImage: hexKey(1)}},
+		{Key: &monero.TxInputToKey{KeyOffsets: []uint64{7, 1}, KeyImage: hexKey(2)}},
+	}
+
+	for _, tc := range []struct {
+		name string
+
+		tx monero.Transaction
+	}{
+		{name: "bulletproof",
+
+			tx: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout:    []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(3)}}},
+				Extra:   monero.TxExtra{1, 2, 3},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeBulletproof,
+					TxnFee:   1000,
+					EcdhInfo: []monero.EcdhTuple{{Mask: hexKey(4), Amount: hexKey(5)}},
+					OutPk:    hexKeys(6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp: 1,
+					Bp: []monero.Bulletproof{{
+						A: hexKey(10), S: hexKey(11), T1: hexKey(12), T2: hexKey(13),
+						Taux: hexKey(14), Mu: hexKey(15), L: hexKeys(16, 17), R: hexKeys(18, 19),
+						LowA: hexKey(20), B: hexKey(21), T: hexKey(22),
+					}},
+					MGs: []monero.MGSig{
+						{Ss: [][]string{hexKeys(30, 31), hexKeys(32, 33)}, Cc: hexKey(34)},
+						{Ss: [][]string{hexKeys(35, 36), hexKeys(37, 38)}, Cc: hexKey(39)},
+					},
+					PseudoOuts: hexKeys(40, 41),
+				},
+			},
+		},
+
+		{name: "clsag",
+
+			tx: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout:    []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(3)}}},
+				Extra:   monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeCLSAG,
+					TxnFee:   1000,
+					EcdhInfo: []monero.EcdhTuple{{Amount: "0102030405060708"}},
+					OutPk:    hexKeys(6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp: 1,
+					Bp: []monero.Bulletproof{{
+						A: hexKey(10), S: hexKey(11), T1: hexKey(12), T2: hexKey(13),
+						Taux: hexKey(14), Mu: hexKey(15), L: hexKeys(16), R: hexKeys(18),
+						LowA: hexKey(20), B: hexKey(21), T: hexKey(22),
+					}},
+					CLSAGs: []monero.CLSAG{
+						{S: hexKeys(30, 31), C1: hexKey(32), D: hexKey(33)},
+						{S: hexKeys(34, 35), C1: hexKey(36), D: hexKey(37)},
+					},
+					PseudoOuts: hexKeys(40, 41),
+				},
+			},
+		},
+
+		{name: "bulletproof+ with view tags",
+
+			tx: monero.Transaction{
+				Version:    2,
+				UnlockTime: 10,
+				Vin:        vin,
+				Vout: []monero.TxOutput{
+					{Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: hexKey(3), ViewTag: "a1"},
+					}},
+					{Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: hexKey(4), ViewTag: "00"},
+					}},
+				},
+				Extra: monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeBulletproofPlus,
+					TxnFee:   30000000,
+					EcdhInfo: []monero.EcdhTuple{{Amount: "0102030405060708"}, {Amount: "0807060504030201"}},
+					OutPk:    hexKeys(5, 6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp: 1,
+					Bpp: []monero.BulletproofPlus{{
+						A: hexKey(10), A1: hexKey(11), B: hexKey(12),
+						R1: hexKey(13), S1: hexKey(14), D1: hexKey(15),
+						L: hexKeys(16, 17, 18), R: hexKeys(19, 20, 21),
+					}},
+					CLSAGs: []monero.CLSAG{
+						{S: hexKeys(30, 31), C1: hexKey(32), D: hexKey(33)},
+						{S: hexKeys(34, 35), C1: hexKey(36), D: hexKey(37)},
+					},
+					PseudoOuts: hexKeys(40, 41),
+				},
+			},
+		},
+
+		{name: "full",
+
+			tx: monero.Transaction{
+				Version: 2,
+				Vin:     vin[:1],
+				Vout:    []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(3)}}},
+				Extra:   monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeFull,
+					TxnFee:   1000,
+					EcdhInfo: []monero.EcdhTuple{{Mask: hexKey(4), Amount: hexKey(5)}},
+					OutPk:    hexKeys(6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					RangeSigs: []monero.RangeSig{{
+						Asig: strings.Repeat(hexKey(7), 129),
+						Ci:   strings.Repeat(hexKey(8), 64),
+					}},
+					MGs: []monero.MGSig{
+						{Ss: [][]string{hexKeys(30, 31), hexKeys(32, 33)}, Cc: hexKey(34)},
+					},
+				},
+			},
+		},
+
+		{name: "pruned",
+
+			tx: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout:    []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(3)}}},
+				Extra:   monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeCLSAG,
+					TxnFee:   1000,
+					EcdhInfo: []monero.EcdhTuple{{Amount: "0102030405060708"}},
+					OutPk:    hexKeys(6),
+				},
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blob, err := tc.tx.Encode()
+			require.NoError(t, err)
+
+			tx, err := monero.DecodeTransaction(blob)
+			require.NoError(t, err)
+			assert.Equal(t, &tc.tx, tx)
+
+			encoded, err := tx.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, blob, encoded)
+
+			prefix, err := tx.EncodePrefix()
+			require.NoError(t, err)
+			assert.Equal(t, blob[:len(prefix)], prefix)
+		})
+	}
+}
+
+// TestTransactionBinaryLayout checks the formats of the transactions since
+// bulletproofs against blobs spelled out byte by byte after monerod's
+// serialization (`cryptonote_basic.h`, `rctTypes.h`), so that a mistake
+// shared by the encoder and the decoder (which a round trip wouldn't tell)
+// shows up.
+//
+// These blobs are written from monerod's sources, not captured from the
+// chain, so they'd share a misreading of those sources. Real mainnet
+// transactions for bulletproof, bulletproof2, clsag and bulletproof+ (with
+// view tags) still have to be added to `TestDecodeTransactionMainnet`, as
+// does a block past v15 to `TestDecodeBlockMainnet`.
+//
+// nolint:funlen
+func TestTransactionBinaryLayout(t *testing.T) {
+	prefix := func(outputs byte, vout ...[]byte) []byte {
+		return concat(
+			[]byte{0x02, 0x00},               // version, unlock time
+			[]byte{0x01, 0x02, 0x00, 0x02},   // 1 input: to_key, amount, 2 offsets
+			[]byte{0xac, 0x02, 0x01}, key(1), // offsets 300 and 1, key image
+			[]byte{outputs}, concat(vout...),
+			[]byte{0x21, 0x01}, key(2), // extra: tx public key
+		)
+	}
+
+	vin := []monero.TxInput{
+		{Key: &monero.TxInputToKey{KeyOffsets: []uint64{300, 1}, KeyImage: hexKey(1)}},
+	}
+	extra := monero.TxExtra(concat([]byte{0x01}, key(2)))
+
+	bulletproof := concat(
+		key(10), key(11), key(12), key(13), key(14), key(15), // A, S, T1, T2, taux, mu
+		[]byte{0x02}, key(16), key(17), // L
+		[]byte{0x02}, key(18), key(19), // R
+		key(20), key(21), key(22), // a, b, t
+	)
+	bp := monero.Bulletproof{
+		A: hexKey(10), S: hexKey(11), T1: hexKey(12), T2: hexKey(13),
+		Taux: hexKey(14), Mu: hexKey(15), L: hexKeys(16, 17), R: hexKeys(18, 19),
+		LowA: hexKey(20), B: hexKey(21), T: hexKey(22),
+	}
+
+	for _, tc := range []struct {
+		name string
+
+		blob     []byte
+		expected monero.Transaction
+	}{
+		{name: "bulletproof: uint32 nbp, full ecdh",
+
+			blob: concat(
+				prefix(1, []byte{0x00, 0x02}, key(3)),
+				[]byte{0x03, 0xc0, 0x84, 0x3d}, // type, fee (1000000)
+				key(4), key(5),                 // ecdh: mask, amount
+				key(6),                         // out pk
+				[]byte{0x01, 0x00, 0x00, 0x00}, // nbp
+				bulletproof,
+				key(30), key(31), key(32), key(33), key(34), // mg: ss (2x2), cc
+				key(40), // pseudo out
+			),
+			expected: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout:    []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(3)}}},
+				Extra:   extra,
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeBulletproof,
+					TxnFee:   1000000,
+					EcdhInfo: []monero.EcdhTuple{{Mask: hexKey(4), Amount: hexKey(5)}},
+					OutPk:    hexKeys(6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp:        1,
+					Bp:         []monero.Bulletproof{bp},
+					MGs:        []monero.MGSig{{Ss: [][]string{hexKeys(30, 31), hexKeys(32, 33)}, Cc: hexKey(34)}},
+					PseudoOuts: hexKeys(40),
+				},
+			},
+		},
+
+		{name: "clsag: varint nbp, compact ecdh",
+
+			blob: concat(
+				prefix(2, []byte{0x00, 0x02}, key(3), []byte{0x00, 0x02}, key(4)),
+				[]byte{0x05, 0xc0, 0x84, 0x3d},
+				[]byte{1, 2, 3, 4, 5, 6, 7, 8}, // ecdh: 8-byte amounts
+				[]byte{8, 7, 6, 5, 4, 3, 2, 1},
+				key(6), key(7),
+				[]byte{0x01}, // nbp
+				bulletproof,
+				key(30), key(31), key(32), key(33), // clsag: s (2), c1, D
+				key(40),
+			),
+			expected: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout: []monero.TxOutput{
+					{Target: monero.TxOutputTarget{Key: hexKey(3)}},
+					{Target: monero.TxOutputTarget{Key: hexKey(4)}},
+				},
+				Extra: extra,
+				RctSignatures: monero.RctSignatures{
+					Type:   monero.RctTypeCLSAG,
+					TxnFee: 1000000,
+					EcdhInfo: []monero.EcdhTuple{
+						{Amount: "0102030405060708"}, {Amount: "0807060504030201"},
+					},
+					OutPk: hexKeys(6, 7),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp:        1,
+					Bp:         []monero.Bulletproof{bp},
+					CLSAGs:     []monero.CLSAG{{S: hexKeys(30, 31), C1: hexKey(32), D: hexKey(33)}},
+					PseudoOuts: hexKeys(40),
+				},
+			},
+		},
+
+		{name: "bulletproof+ with view tags",
+
+			blob: concat(
+				prefix(1, []byte{0x00, 0x03}, key(3), []byte{0xa1}), // tagged_key, view tag
+				[]byte{0x06, 0xc0, 0x84, 0x3d},
+				[]byte{1, 2, 3, 4, 5, 6, 7, 8},
+				key(6),
+				[]byte{0x01},
+				key(10), key(11), key(12), key(13), key(14), key(15), // A, A1, B, r1, s1, d1
+				[]byte{0x01}, key(16), // L
+				[]byte{0x01}, key(17), // R
+				key(30), key(31), key(32), key(33),
+				key(40),
+			),
+			expected: monero.Transaction{
+				Version: 2,
+				Vin:     vin,
+				Vout: []monero.TxOutput{
+					{Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: hexKey(3), ViewTag: "a1"},
+					}},
+				},
+				Extra: extra,
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeBulletproofPlus,
+					TxnFee:   1000000,
+					EcdhInfo: []monero.EcdhTuple{{Amount: "0102030405060708"}},
+					OutPk:    hexKeys(6),
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp: 1,
+					Bpp: []monero.BulletproofPlus{{
+						A: hexKey(10), A1: hexKey(11), B: hexKey(12),
+						R1: hexKey(13), S1: hexKey(14), D1: hexKey(15),
+						L: hexKeys(16), R: hexKeys(17),
+					}},
+					CLSAGs:     []monero.CLSAG{{S: hexKeys(30, 31), C1: hexKey(32), D: hexKey(33)}},
+					PseudoOuts: hexKeys(40),
+				},
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx, err := monero.DecodeTransaction(tc.blob)
+			require.NoError(t, err)
+			assert.Equal(t, &tc.expected, tx)
+
+			encoded, err := tc.expected.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, tc.blob, encoded)
+		})
+	}
+}
+
+// TestBlockBinaryLayout is the counterpart of `TestTransactionBinaryLayout`
+// for blocks past v1, whose coinbase is a v2 transaction.
+//
+func TestBlockBinaryLayout(t *testing.T) {
+	t.Parallel()
+
+	blob := concat(
+		[]byte{0x10, 0x10, 0xe8, 0x07}, // major, minor, timestamp (1000)
+		key(9),                         // prev id
+		[]byte{0x78, 0x56, 0x34, 0x12}, // nonce
+		[]byte{0x02, 0xa0, 0x01},       // miner tx: version, unlock time (160)
+		[]byte{0x01, 0xff, 0x64},       // 1 input: gen, height (100)
+		[]byte{0x01, 0xe8, 0x07, 0x03}, // 1 output: amount (1000), tagged_key
+		key(3), []byte{0xa1},           // key, view tag
+		[]byte{0x21, 0x01}, key(2), // extra
+		[]byte{0x00},                 // rct type (null)
+		[]byte{0x02}, key(7), key(8), // tx hashes
+	)
+
+	expected := &monero.Block{
+		MajorVersion: 16,
+		MinorVersion: 16,
+		Timestamp:    1000,
+		PrevID:       hexKey(9),
+		Nonce:        0x12345678,
+		MinerTx: monero.Transaction{
+			Version:    2,
+			UnlockTime: 160,
+			Vin:        []monero.TxInput{{Gen: &monero.TxInputGen{Height: 100}}},
+			Vout: []monero.TxOutput{{Amount: 1000, Target: monero.TxOutputTarget{
+				TaggedKey: &monero.TxOutputToTaggedKey{Key: hexKey(3), ViewTag: "a1"},
+			}}},
+			Extra: concat([]byte{0x01}, key(2)),
+		},
+		TxHashes: hexKeys(7, 8),
+	}
+
+	block, err := monero.DecodeBlock(blob)
+	require.NoError(t, err)
+	assert.Equal(t, expected, block)
+
+	encoded, err := expected.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, blob, encoded)
+}
+
+func TestDecodeTransactionMalformed(t *testing.T) {
+	blob := fixture(t, "tx-ca9ea576d67af4926e31ebeb159aaee58950aea18e5e0ad0bae23b2d85ede8c1")
+
+	for _, tc := range []struct {
+		name string
+
+		blob []byte
+	}{
+		{name: "empty", blob: []byte{}},
+		{name: "truncated", blob: blob[:len(blob)-1]},
+		{name: "trailing bytes", blob: append(append([]byte{}, blob...), 0)},
+		{name: "unsupported version", blob: []byte{0x03, 0x00, 0x00, 0x00, 0x00}},
+		{name: "non-canonical varint", blob: []byte{0x81, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{name: "unknown input tag", blob: []byte{0x01, 0x00, 0x01, 0x05}},
+		{name: "huge count", blob: []byte{0x01, 0x00, 0xff, 0xff, 0xff, 0xff, 0x0f}},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := monero.DecodeTransaction(tc.blob)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTransactionEncodeInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tx := range []monero.Transaction{
+		{Version: 1, Vout: []monero.TxOutput{{Target: monero.TxOutputTarget{Key: "zz"}}}},
+		{Version: 1, Vout: []monero.TxOutput{{Target: monero.TxOutputTarget{Key: "aabb"}}}},
+		{Version: 1, Vin: []monero.TxInput{{}}},
+		{Version: 2, Vout: []monero.TxOutput{{Target: monero.TxOutputTarget{Key: hexKey(1)}}},
+			RctSignatures: monero.RctSignatures{Type: monero.RctTypeCLSAG}},
+	} {
+		_, err := tx.Encode()
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,423 @@
+package monero_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+// nolint:funlen
+func TestTransactionJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		input    string
+		expected monero.Transaction
+	}{
+		{name: "rpc, pre-ringct",
+
+			input: `{
+				"version": 1, "unlock_time": 0,
+				"vin": [{"key": {"amount": 9000, "key_offsets": [10, 3], "k_image": "ki"}}],
+				"vout": [{"amount": 7000, "target": {"key": "k0"}}],
+				"extra": [1, 170, 187],
+				"signatures": ["sigsig"]
+			}`,
+			expected: monero.Transaction{
+				Version: 1,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{Amount: 9000, KeyOffsets: []uint64{10, 3}, KeyImage: "ki"}},
+				},
+				Vout: []monero.TxOutput{
+					{Amount: 7000, Target: monero.TxOutputTarget{Key: "k0"}},
+				},
+				Extra:      monero.TxExtra{1, 0xaa, 0xbb},
+				Signatures: []string{"sigsig"},
+			},
+		},
+
+		{name: "rpc, coinbase with view tags",
+
+			input: `{
+				"version": 2, "unlock_time": 70,
+				"vin": [{"gen": {"height": 10}}],
+				"vout": [{"amount": 600, "target": {"tagged_key": {"key": "k0", "view_tag": "a1"}}}],
+				"extra": [],
+				"rct_signatures": {"type": 0}
+			}`,
+			expected: monero.Transaction{
+				Version:    2,
+				UnlockTime: 70,
+				Vin:        []monero.TxInput{{Gen: &monero.TxInputGen{Height: 10}}},
+				Vout: []monero.TxOutput{
+					{Amount: 600, Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: "k0", ViewTag: "a1"},
+					}},
+				},
+				Extra: monero.TxExtra{},
+			},
+		},
+
+		{name: "rpc, full with borromean range proofs",
+
+			input: `{
+				"version": 2, "unlock_time": 0,
+				"vin": [{"key": {"amount": 0, "key_offsets": [1], "k_image": "ki"}}],
+				"vout": [{"amount": 0, "target": {"key": "k0"}}],
+				"extra": [],
+				"rct_signatures": {
+					"type": 1, "txnFee": 30,
+					"ecdhInfo": [{"mask": "m0", "amount": "a0"}],
+					"outPk": ["c0"]
+				},
+				"rctsig_prunable": {
+					"rangeSigs": [{"asig": "s0s1ee", "Ci": "ci"}],
+					"MGs": [{"ss": [["x", "y"]], "cc": "cc"}]
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 2,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{KeyOffsets: []uint64{1}, KeyImage: "ki"}},
+				},
+				Vout:  []monero.TxOutput{{Target: monero.TxOutputTarget{Key: "k0"}}},
+				Extra: monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeFull,
+					TxnFee:   30,
+					EcdhInfo: []monero.EcdhTuple{{Mask: "m0", Amount: "a0"}},
+					OutPk:    []string{"c0"},
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					RangeSigs: []monero.RangeSig{{Asig: "s0s1ee", Ci: "ci"}},
+					MGs:       []monero.MGSig{{Ss: [][]string{{"x", "y"}}, Cc: "cc"}},
+				},
+			},
+		},
+
+		{name: "rpc, simple with pseudo outputs in the base",
+
+			input: `{
+				"version": 2, "unlock_time": 0,
+				"vin": [{"key": {"amount": 0, "key_offsets": [4, 2], "k_image": "ki"}}],
+				"vout": [{"amount": 0, "target": {"key": "k0"}}],
+				"extra": [],
+				"rct_signatures": {
+					"type": 2, "txnFee": 20,
+					"pseudoOuts": ["p0"],
+					"ecdhInfo": [{"mask": "m0", "amount": "a0"}],
+					"outPk": ["c0"]
+				},
+				"rctsig_prunable": {
+					"rangeSigs": [{"asig": "s0s1ee", "Ci": "ci"}],
+					"MGs": [{"ss": [["x", "y"], ["z", "w"]], "cc": "cc"}]
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 2,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{KeyOffsets: []uint64{4, 2}, KeyImage: "ki"}},
+				},
+				Vout:  []monero.TxOutput{{Target: monero.TxOutputTarget{Key: "k0"}}},
+				Extra: monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:       monero.RctTypeSimple,
+					TxnFee:     20,
+					PseudoOuts: []string{"p0"},
+					EcdhInfo:   []monero.EcdhTuple{{Mask: "m0", Amount: "a0"}},
+					OutPk:      []string{"c0"},
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					RangeSigs: []monero.RangeSig{{Asig: "s0s1ee", Ci: "ci"}},
+					MGs:       []monero.MGSig{{Ss: [][]string{{"x", "y"}, {"z", "w"}}, Cc: "cc"}},
+				},
+			},
+		},
+
+		{name: "rpc, bulletproofs+ and clsags",
+
+			input: `{
+				"version": 2, "unlock_time": 0,
+				"vin": [{"key": {"amount": 0, "key_offsets": [5, 1], "k_image": "ki"}}],
+				"vout": [{"amount": 0, "target": {"tagged_key": {"key": "k0", "view_tag": "0f"}}}],
+				"extra": [2],
+				"rct_signatures": {
+					"type": 6, "txnFee": 40,
+					"ecdhInfo": [{"amount": "a0"}],
+					"outPk": ["c0"]
+				},
+				"rctsig_prunable": {
+					"nbp": 1,
+					"bpp": [{"A": "A", "A1": "A1", "B": "B", "r1": "r1", "s1": "s1",
+						"d1": "d1", "L": ["l"], "R": ["r"]}],
+					"CLSAGs": [{"s": ["s0", "s1"], "c1": "c1", "D": "D"}],
+					"pseudoOuts": ["p0"]
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 2,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{KeyOffsets: []uint64{5, 1}, KeyImage: "ki"}},
+				},
+				Vout: []monero.TxOutput{
+					{Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: "k0", ViewTag: "0f"},
+					}},
+				},
+				Extra: monero.TxExtra{2},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeBulletproofPlus,
+					TxnFee:   40,
+					EcdhInfo: []monero.EcdhTuple{{Amount: "a0"}},
+					OutPk:    []string{"c0"},
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp: 1,
+					Bpp: []monero.BulletproofPlus{{
+						A: "A", A1: "A1", B: "B", R1: "r1", S1: "s1", D1: "d1",
+						L: []string{"l"}, R: []string{"r"},
+					}},
+					CLSAGs:     []monero.CLSAG{{S: []string{"s0", "s1"}, C1: "c1", D: "D"}},
+					PseudoOuts: []string{"p0"},
+				},
+			},
+		},
+
+		{name: "zmq, bulletproofs and clsags",
+
+			input: `{
+				"version": 2, "unlock_time": 0,
+				"inputs": [{"to_key": {"amount": 0, "key_offsets": [5], "key_image": "ki"}}],
+				"outputs": [
+					{"amount": 0, "to_key": {"key": "k0"}},
+					{"amount": 0, "to_tagged_key": {"key": "k1", "view_tag": "ff"}}
+				],
+				"extra": "01aabb",
+				"signatures": [],
+				"ringct": {
+					"type": 5,
+					"encrypted": [{"mask": "m0", "amount": "a0"}],
+					"commitments": ["c0", "c1"],
+					"fee": 50,
+					"prunable": {
+						"range_proofs": [],
+						"bulletproofs": [{"V": ["v"], "A": "A", "S": "S", "T1": "T1",
+							"T2": "T2", "taux": "taux", "mu": "mu", "L": ["l"],
+							"R": ["r"], "a": "a", "b": "b", "t": "t"}],
+						"mlsags": [],
+						"clsags": [{"s": ["s0"], "c1": "c1", "D": "D"}],
+						"pseudo_outs": ["p0"]
+					}
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 2,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{KeyOffsets: []uint64{5}, KeyImage: "ki"}},
+				},
+				Vout: []monero.TxOutput{
+					{Target: monero.TxOutputTarget{Key: "k0"}},
+					{Target: monero.TxOutputTarget{
+						TaggedKey: &monero.TxOutputToTaggedKey{Key: "k1", ViewTag: "ff"},
+					}},
+				},
+				Extra: monero.TxExtra{1, 0xaa, 0xbb},
+				RctSignatures: monero.RctSignatures{
+					Type:     monero.RctTypeCLSAG,
+					TxnFee:   50,
+					EcdhInfo: []monero.EcdhTuple{{Mask: "m0", Amount: "a0"}},
+					OutPk:    []string{"c0", "c1"},
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					Nbp:       1,
+					RangeSigs: []monero.RangeSig{},
+					Bp: []monero.Bulletproof{{
+						V: []string{"v"}, A: "A", S: "S", T1: "T1", T2: "T2",
+						Taux: "taux", Mu: "mu", L: []string{"l"}, R: []string{"r"},
+						LowA: "a", B: "b", T: "t",
+					}},
+					MGs:        []monero.MGSig{},
+					CLSAGs:     []monero.CLSAG{{S: []string{"s0"}, C1: "c1", D: "D"}},
+					PseudoOuts: []string{"p0"},
+				},
+			},
+		},
+
+		{name: "zmq, simple with pseudo outputs moved to the base",
+
+			input: `{
+				"version": 2, "unlock_time": 0,
+				"inputs": [{"to_key": {"amount": 0, "key_offsets": [4, 2], "key_image": "ki"}}],
+				"outputs": [{"amount": 0, "to_key": {"key": "k0"}}],
+				"extra": "",
+				"signatures": [],
+				"ringct": {
+					"type": 2,
+					"encrypted": [{"mask": "m0", "amount": "a0"}],
+					"commitments": ["c0"],
+					"fee": 20,
+					"prunable": {
+						"range_proofs": [{"asig": {"s0": ["1"], "s1": ["2"], "ee": "3"},
+							"Ci": ["4"]}],
+						"bulletproofs": [],
+						"mlsags": [{"ss": [["x", "y"], ["z", "w"]], "cc": "cc"}],
+						"clsags": [],
+						"pseudo_outs": ["p0"]
+					}
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 2,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{KeyOffsets: []uint64{4, 2}, KeyImage: "ki"}},
+				},
+				Vout:  []monero.TxOutput{{Target: monero.TxOutputTarget{Key: "k0"}}},
+				Extra: monero.TxExtra{},
+				RctSignatures: monero.RctSignatures{
+					Type:       monero.RctTypeSimple,
+					TxnFee:     20,
+					PseudoOuts: []string{"p0"},
+					EcdhInfo:   []monero.EcdhTuple{{Mask: "m0", Amount: "a0"}},
+					OutPk:      []string{"c0"},
+				},
+				RctSigPrunable: &monero.RctSigPrunable{
+					RangeSigs: []monero.RangeSig{{Asig: "123", Ci: "4"}},
+					Bp:        []monero.Bulletproof{},
+					MGs:       []monero.MGSig{{Ss: [][]string{{"x", "y"}, {"z", "w"}}, Cc: "cc"}},
+					CLSAGs:    []monero.CLSAG{},
+				},
+			},
+		},
+
+		{name: "zmq, pre-ringct with borromean range proofs",
+
+			input: `{
+				"version": 1, "unlock_time": 0,
+				"inputs": [{"to_key": {"amount": 100, "key_offsets": [5], "key_image": "ki"}}],
+				"outputs": [{"amount": 90, "to_key": {"key": "k0"}}],
+				"extra": "",
+				"signatures": [["aa", "bb"]],
+				"ringct": {
+					"type": 0, "encrypted": [], "commitments": [], "fee": 0,
+					"prunable": {
+						"range_proofs": [{"asig": {"s0": ["1", "2"], "s1": ["3"], "ee": "4"},
+							"Ci": ["5", "6"]}]
+					}
+				}
+			}`,
+			expected: monero.Transaction{
+				Version: 1,
+				Vin: []monero.TxInput{
+					{Key: &monero.TxInputToKey{Amount: 100, KeyOffsets: []uint64{5}, KeyImage: "ki"}},
+				},
+				Vout:          []monero.TxOutput{{Amount: 90, Target: monero.TxOutputTarget{Key: "k0"}}},
+				Extra:         monero.TxExtra{},
+				Signatures:    []string{"aabb"},
+				RctSignatures: monero.RctSignatures{EcdhInfo: []monero.EcdhTuple{}, OutPk: []string{}},
+				RctSigPrunable: &monero.RctSigPrunable{
+					RangeSigs: []monero.RangeSig{{Asig: "1234", Ci: "56"}},
+				},
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := monero.Transaction{}
+			require.NoError(t, json.Unmarshal([]byte(tc.input), &tx))
+			assert.Equal(t, tc.expected, tx)
+
+			// encoding always gives the rpc form, which must survive
+			// being decoded and encoded again.
+			//
+			encoded, err := json.Marshal(tx)
+			require.NoError(t, err)
+			assert.NotContains(t, string(encoded), `"ringct"`)
+
+			decoded := monero.Transaction{}
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+
+			reencoded, err := json.Marshal(decoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(encoded), string(reencoded))
+		})
+	}
+}
+
+func TestTransactionHelpers(t *testing.T) {
+	t.Parallel()
+
+	v1 := monero.Transaction{
+		Version: 1,
+		Vin: []monero.TxInput{
+			{Key: &monero.TxInputToKey{Amount: 100}},
+			{Key: &monero.TxInputToKey{Amount: 50}},
+		},
+		Vout: []monero.TxOutput{{Amount: 120}},
+	}
+	assert.EqualValues(t, 30, v1.Fee())
+	assert.False(t, v1.IsCoinbase())
+
+	v2 := monero.Transaction{
+		Version:       2,
+		RctSignatures: monero.RctSignatures{Type: monero.RctTypeCLSAG, TxnFee: 7},
+	}
+	assert.EqualValues(t, 7, v2.Fee())
+	assert.Equal(t, "CLSAG", v2.RctSignatures.Type.String())
+
+	coinbase := monero.Block{
+		MinerTx: monero.Transaction{
+			Vin: []monero.TxInput{{Gen: &monero.TxInputGen{Height: 1}}},
+			Vout: []monero.TxOutput{
+				{Amount: 10, Target: monero.TxOutputTarget{Key: "k0"}},
+				{Amount: 20, Target: monero.TxOutputTarget{
+					TaggedKey: &monero.TxOutputToTaggedKey{Key: "k1"},
+				}},
+			},
+		},
+	}
+	assert.True(t, coinbase.MinerTx.IsCoinbase())
+	assert.EqualValues(t, 30, coinbase.MinerOutputs())
+	assert.Equal(t, "k0", coinbase.MinerTx.Vout[0].Key())
+	assert.Equal(t, "k1", coinbase.MinerTx.Vout[1].Key())
+}
+
+func TestBlockJSON(t *testing.T) {
+	t.Parallel()
+
+	// zmq publishes blocks laid out as in rpc responses, other than for
+	// the coinbase transaction.
+	//
+	for _, input := range []string{
+		`{"major_version": 16, "minor_version": 16, "timestamp": 1700000000,
+		  "prev_id": "pp", "nonce": 4294967295,
+		  "miner_tx": {"version": 2, "unlock_time": 70, "vin": [{"gen": {"height": 10}}],
+		    "vout": [{"amount": 600, "target": {"key": "k0"}}], "extra": [1],
+		    "rct_signatures": {"type": 0}},
+		  "tx_hashes": ["aa"]}`,
+		`{"major_version": 16, "minor_version": 16, "timestamp": 1700000000,
+		  "prev_id": "pp", "nonce": 4294967295,
+		  "miner_tx": {"version": 2, "unlock_time": 70, "inputs": [{"gen": {"height": 10}}],
+		    "outputs": [{"amount": 600, "to_key": {"key": "k0"}}], "extra": "01",
+		    "signatures": [], "ringct": {"type": 0, "encrypted": [], "commitments": [], "fee": 0}},
+		  "tx_hashes": ["aa"]}`,
+	} {
+		block := monero.Block{}
+		require.NoError(t, json.Unmarshal([]byte(input), &block))
+
+		assert.EqualValues(t, 16, block.MajorVersion)
+		assert.EqualValues(t, 1700000000, block.Timestamp)
+		assert.EqualValues(t, uint32(4294967295), block.Nonce)
+		assert.True(t, block.MinerTx.IsCoinbase())
+		assert.EqualValues(t, 10, block.MinerTx.Vin[0].Gen.Height)
+		assert.EqualValues(t, 600, block.MinerOutputs())
+		assert.Equal(t, monero.TxExtra{1}, block.MinerTx.Extra)
+		assert.Equal(t, []string{"aa"}, block.TxHashes)
+	}
+}
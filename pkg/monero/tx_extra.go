@@ -0,0 +1,427 @@
+package monero
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Tags identifying the kind of each field in `tx_extra`.
+//
+const (
+	// TxExtraTagPadding marks zero-filled padding running up to the end
+	// of `tx_extra`.
+	//
+	TxExtraTagPadding byte = 0x00
+
+	// TxExtraTagPublicKey marks the transaction public key.
+	//
+	TxExtraTagPublicKey byte = 0x01
+
+	// TxExtraTagNonce marks an arbitrary blob of data, also used for
+	// carrying payment IDs (and by pools for their extra nonces).
+	//
+	TxExtraTagNonce byte = 0x02
+
+	// TxExtraTagMergeMining marks the merkle root of the chains merge
+	// mined with the block the (coinbase) transaction belongs to.
+	//
+	TxExtraTagMergeMining byte = 0x03
+
+	// TxExtraTagAdditionalPublicKeys marks the per-output public keys of
+	// transactions sending to subaddresses.
+	//
+	TxExtraTagAdditionalPublicKeys byte = 0x04
+
+	// TxExtraTagMinergate marks the field that the minergate pool used
+	// to add to its blocks.
+	//
+	TxExtraTagMinergate byte = 0xde
+)
+
+// Prefixes of the data of an extra nonce telling the kind of payment ID it
+// carries, if any.
+//
+const (
+	TxExtraNoncePaymentID          byte = 0x00
+	TxExtraNonceEncryptedPaymentID byte = 0x01
+)
+
+const (
+	// TxExtraMaxPadding is the maximum number of bytes (tag included)
+	// that padding may take.
+	//
+	TxExtraMaxPadding = 255
+
+	// TxExtraMaxNonce is the maximum number of bytes of an extra nonce.
+	//
+	TxExtraMaxNonce = 255
+
+	// PaymentIDSize is the size of an (unencrypted, long) payment ID.
+	//
+	PaymentIDSize = 32
+
+	// EncryptedPaymentIDSize is the size of an encrypted (short)
+	// payment ID.
+	//
+	EncryptedPaymentIDSize = 8
+)
+
+// TxExtraField is a field of `tx_extra`, with the fields set depending on
+// its `Tag`.
+//
+type TxExtraField struct {
+	// Tag identifies the kind of the field.
+	//
+	Tag byte
+
+	// Padding is the number of zeroes following the tag of a padding
+	// field.
+	//
+	Padding int
+
+	// PublicKey is the transaction public key.
+	//
+	PublicKey []byte
+
+	// AdditionalPublicKeys are the per-output public keys.
+	//
+	AdditionalPublicKeys [][]byte
+
+	// Nonce is the extra nonce.
+	//
+	Nonce *TxExtraNonce
+
+	// MergeMining is the merge mining tag.
+	//
+	MergeMining *TxExtraMergeMining
+
+	// Data is the content of a minergate field, or, for tags not known
+	// of, everything following the tag (as there's no telling where an
+	// unknown field ends).
+	//
+	Data []byte
+}
+
+// TxExtraNonce is an extra nonce: an arbitrary blob of data, which may carry
+// a payment ID.
+//
+type TxExtraNonce struct {
+	Data []byte
+}
+
+// PaymentID gives the (unencrypted) payment ID carried by the nonce, if any.
+//
+func (n *TxExtraNonce) PaymentID() []byte {
+	if len(n.Data) != 1+PaymentIDSize || n.Data[0] != TxExtraNoncePaymentID {
+		return nil
+	}
+
+	return n.Data[1:]
+}
+
+// EncryptedPaymentID gives the encrypted payment ID carried by the nonce, if
+// any.
+//
+func (n *TxExtraNonce) EncryptedPaymentID() []byte {
+	if len(n.Data) != 1+EncryptedPaymentIDSize || n.Data[0] != TxExtraNonceEncryptedPaymentID {
+		return nil
+	}
+
+	return n.Data[1:]
+}
+
+// TxExtraMergeMining is the merge mining tag of a coinbase transaction.
+//
+type TxExtraMergeMining struct {
+	// Depth is the depth of the merkle tree of the merge mined chains.
+	//
+	Depth uint64
+
+	// MerkleRoot is the root of the merkle tree of the merge mined
+	// chains.
+	//
+	MerkleRoot []byte
+}
+
+// TxExtraFields are the fields of `tx_extra`, in the order they appear in.
+//
+type TxExtraFields []TxExtraField
+
+// Fields parses the content of `tx_extra` (see `ParseTxExtra`).
+//
+func (e TxExtra) Fields() (TxExtraFields, error) {
+	return ParseTxExtra(e)
+}
+
+// ParseTxExtra parses the content of `tx_extra` into its fields.
+//
+// Fields with unknown tags are kept with their raw content (along with
+// everything after it, as there's no telling where they end). In case of a
+// malformed field, the fields parsed until then are given back along with
+// the error.
+//
+func ParseTxExtra(extra []byte) (TxExtraFields, error) {
+	fields := TxExtraFields{}
+
+	for offset := 0; offset < len(extra); {
+		field := TxExtraField{Tag: extra[offset]}
+		rest := extra[offset+1:]
+
+		n, err := field.parse(rest)
+		if err != nil {
+			return fields, fmt.Errorf("field 0x%02x at offset %d: %w",
+				field.Tag, offset, err)
+		}
+
+		fields = append(fields, field)
+		offset += 1 + n
+	}
+
+	return fields, nil
+}
+
+// parse parses the content of the field following its tag from `b`, giving
+// back how many bytes it took.
+//
+// nolint:gocyclo
+func (f *TxExtraField) parse(b []byte) (int, error) {
+	switch f.Tag {
+	case TxExtraTagPadding:
+		if 1+len(b) > TxExtraMaxPadding {
+			return 0, fmt.Errorf("padding of %d bytes over max of %d",
+				1+len(b), TxExtraMaxPadding)
+		}
+
+		for _, c := range b {
+			if c != 0 {
+				return 0, fmt.Errorf("non-zero padding")
+			}
+		}
+
+		f.Padding = len(b)
+		return len(b), nil
+
+	case TxExtraTagPublicKey:
+		if len(b) < KeySize {
+			return 0, fmt.Errorf("truncated public key")
+		}
+
+		f.PublicKey = b[:KeySize]
+		return KeySize, nil
+
+	case TxExtraTagNonce:
+		data, n, err := readBlob(b)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(data) > TxExtraMaxNonce {
+			return 0, fmt.Errorf("nonce of %d bytes over max of %d",
+				len(data), TxExtraMaxNonce)
+		}
+
+		f.Nonce = &TxExtraNonce{Data: data}
+		return n, nil
+
+	case TxExtraTagMergeMining:
+		data, n, err := readBlob(b)
+		if err != nil {
+			return 0, err
+		}
+
+		depth, m := binary.Uvarint(data)
+		if m <= 0 || len(data)-m != KeySize {
+			return 0, fmt.Errorf("malformed merge mining tag")
+		}
+
+		f.MergeMining = &TxExtraMergeMining{Depth: depth, MerkleRoot: data[m:]}
+		return n, nil
+
+	case TxExtraTagAdditionalPublicKeys:
+		count, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, fmt.Errorf("malformed count")
+		}
+
+		if count > uint64(len(b)-n)/KeySize {
+			return 0, fmt.Errorf("truncated additional public keys")
+		}
+
+		f.AdditionalPublicKeys = make([][]byte, count)
+		for idx := range f.AdditionalPublicKeys {
+			f.AdditionalPublicKeys[idx] = b[n : n+KeySize]
+			n += KeySize
+		}
+
+		return n, nil
+
+	case TxExtraTagMinergate:
+		data, n, err := readBlob(b)
+		if err != nil {
+			return 0, err
+		}
+
+		f.Data = data
+		return n, nil
+	}
+
+	f.Data = b
+	return len(b), nil
+}
+
+// readBlob reads a varint-prefixed blob from `b`, giving back how many bytes
+// it took.
+//
+func readBlob(b []byte) ([]byte, int, error) {
+	size, n := binary.Uvarint(b)
+	if n <= 0 {
+		return nil, 0, fmt.Errorf("malformed size")
+	}
+
+	if size > uint64(len(b)-n) {
+		return nil, 0, fmt.Errorf("truncated: size %d with %d bytes left",
+			size, len(b)-n)
+	}
+
+	return b[n : n+int(size)], n + int(size), nil
+}
+
+// Bytes serializes the fields back into the content of `tx_extra`.
+//
+func (f TxExtraFields) Bytes() (TxExtra, error) {
+	res := TxExtra{}
+
+	for idx, field := range f {
+		b, err := field.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("field %d (0x%02x): %w", idx, field.Tag, err)
+		}
+
+		res = append(res, b...)
+	}
+
+	return res, nil
+}
+
+func (f *TxExtraField) bytes() ([]byte, error) {
+	res := []byte{f.Tag}
+
+	switch f.Tag {
+	case TxExtraTagPadding:
+		if 1+f.Padding > TxExtraMaxPadding {
+			return nil, fmt.Errorf("padding of %d bytes over max of %d",
+				1+f.Padding, TxExtraMaxPadding)
+		}
+
+		return append(res, make([]byte, f.Padding)...), nil
+
+	case TxExtraTagPublicKey:
+		if len(f.PublicKey) != KeySize {
+			return nil, fmt.Errorf("public key of %d bytes", len(f.PublicKey))
+		}
+
+		return append(res, f.PublicKey...), nil
+
+	case TxExtraTagNonce:
+		if f.Nonce == nil {
+			return nil, fmt.Errorf("nil nonce")
+		}
+
+		if len(f.Nonce.Data) > TxExtraMaxNonce {
+			return nil, fmt.Errorf("nonce of %d bytes over max of %d",
+				len(f.Nonce.Data), TxExtraMaxNonce)
+		}
+
+		return appendBlob(res, f.Nonce.Data), nil
+
+	case TxExtraTagMergeMining:
+		if f.MergeMining == nil {
+			return nil, fmt.Errorf("nil merge mining tag")
+		}
+
+		if len(f.MergeMining.MerkleRoot) != KeySize {
+			return nil, fmt.Errorf("merkle root of %d bytes",
+				len(f.MergeMining.MerkleRoot))
+		}
+
+		data := appendUvarint(nil, f.MergeMining.Depth)
+		data = append(data, f.MergeMining.MerkleRoot...)
+
+		return appendBlob(res, data), nil
+
+	case TxExtraTagAdditionalPublicKeys:
+		res = appendUvarint(res, uint64(len(f.AdditionalPublicKeys)))
+		for idx, key := range f.AdditionalPublicKeys {
+			if len(key) != KeySize {
+				return nil, fmt.Errorf("additional public key %d of %d bytes",
+					idx, len(key))
+			}
+
+			res = append(res, key...)
+		}
+
+		return res, nil
+
+	case TxExtraTagMinergate:
+		return appendBlob(res, f.Data), nil
+	}
+
+	return append(res, f.Data...), nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutUvarint(buf, v)]...)
+}
+
+func appendBlob(b, data []byte) []byte {
+	return append(appendUvarint(b, uint64(len(data))), data...)
+}
+
+// PublicKey gives the transaction public key, if any.
+//
+func (f TxExtraFields) PublicKey() []byte {
+	for _, field := range f {
+		if field.Tag == TxExtraTagPublicKey {
+			return field.PublicKey
+		}
+	}
+
+	return nil
+}
+
+// AdditionalPublicKeys gives the per-output public keys, if any.
+//
+func (f TxExtraFields) AdditionalPublicKeys() [][]byte {
+	for _, field := range f {
+		if field.Tag == TxExtraTagAdditionalPublicKeys {
+			return field.AdditionalPublicKeys
+		}
+	}
+
+	return nil
+}
+
+// Nonce gives the extra nonce, if any.
+//
+func (f TxExtraFields) Nonce() *TxExtraNonce {
+	for _, field := range f {
+		if field.Tag == TxExtraTagNonce {
+			return field.Nonce
+		}
+	}
+
+	return nil
+}
+
+// MergeMining gives the merge mining tag, if any.
+//
+func (f TxExtraFields) MergeMining() *TxExtraMergeMining {
+	for _, field := range f {
+		if field.Tag == TxExtraTagMergeMining {
+			return field.MergeMining
+		}
+	}
+
+	return nil
+}
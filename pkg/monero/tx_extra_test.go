@@ -0,0 +1,174 @@
+package monero_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, monero.KeySize)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// nolint:funlen
+func TestTxExtra(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		extra  []byte
+		fields monero.TxExtraFields
+	}{
+		{name: "empty",
+
+			extra:  []byte{},
+			fields: monero.TxExtraFields{},
+		},
+
+		{name: "public key and encrypted payment id",
+
+			extra: concat(
+				[]byte{0x01}, key(1),
+				[]byte{0x02, 9, 0x01, 1, 2, 3, 4, 5, 6, 7, 8},
+			),
+			fields: monero.TxExtraFields{
+				{Tag: monero.TxExtraTagPublicKey, PublicKey: key(1)},
+				{Tag: monero.TxExtraTagNonce, Nonce: &monero.TxExtraNonce{
+					Data: []byte{0x01, 1, 2, 3, 4, 5, 6, 7, 8},
+				}},
+			},
+		},
+
+		{name: "payment id and additional public keys",
+
+			extra: concat(
+				[]byte{0x02, 33, 0x00}, key(9),
+				[]byte{0x01}, key(1),
+				[]byte{0x04, 2}, key(2), key(3),
+			),
+			fields: monero.TxExtraFields{
+				{Tag: monero.TxExtraTagNonce, Nonce: &monero.TxExtraNonce{
+					Data: concat([]byte{0x00}, key(9)),
+				}},
+				{Tag: monero.TxExtraTagPublicKey, PublicKey: key(1)},
+				{Tag: monero.TxExtraTagAdditionalPublicKeys,
+					AdditionalPublicKeys: [][]byte{key(2), key(3)}},
+			},
+		},
+
+		{name: "coinbase with merge mining tag and padding",
+
+			extra: concat(
+				[]byte{0x01}, key(1),
+				[]byte{0x03, 34, 0x80, 0x01}, key(4),
+				[]byte{0x02, 3, 0xaa, 0xbb, 0xcc},
+				[]byte{0x00, 0, 0, 0},
+			),
+			fields: monero.TxExtraFields{
+				{Tag: monero.TxExtraTagPublicKey, PublicKey: key(1)},
+				{Tag: monero.TxExtraTagMergeMining, MergeMining: &monero.TxExtraMergeMining{
+					Depth: 128, MerkleRoot: key(4),
+				}},
+				{Tag: monero.TxExtraTagNonce, Nonce: &monero.TxExtraNonce{
+					Data: []byte{0xaa, 0xbb, 0xcc},
+				}},
+				{Tag: monero.TxExtraTagPadding, Padding: 3},
+			},
+		},
+
+		{name: "minergate and unknown tags",
+
+			extra: concat(
+				[]byte{0xde, 2, 0xaa, 0xbb},
+				[]byte{0x01}, key(1),
+				[]byte{0x7f, 1, 2, 3},
+			),
+			fields: monero.TxExtraFields{
+				{Tag: monero.TxExtraTagMinergate, Data: []byte{0xaa, 0xbb}},
+				{Tag: monero.TxExtraTagPublicKey, PublicKey: key(1)},
+				{Tag: 0x7f, Data: []byte{1, 2, 3}},
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields, err := monero.ParseTxExtra(tc.extra)
+			require.NoError(t, err)
+			assert.Equal(t, tc.fields, fields)
+
+			extra, err := fields.Bytes()
+			require.NoError(t, err)
+			assert.Equal(t, monero.TxExtra(tc.extra), extra)
+		})
+	}
+}
+
+func TestTxExtraMalformed(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+
+		extra  []byte
+		parsed int
+	}{
+		{name: "truncated public key", extra: []byte{0x01, 1, 2}},
+		{name: "truncated nonce", extra: []byte{0x02, 9, 0x01, 1}},
+		{name: "non-zero padding", extra: []byte{0x00, 0, 1}},
+		{name: "padding over max", extra: make([]byte, monero.TxExtraMaxPadding+1)},
+		{name: "merge mining tag with short root", extra: []byte{0x03, 2, 0x00, 0xaa}},
+		{name: "truncated additional public keys",
+			extra: concat([]byte{0x04, 2}, key(2))},
+		{name: "fields before the malformed one are kept",
+			extra: concat([]byte{0x01}, key(1), []byte{0x02, 9}), parsed: 1},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields, err := monero.ParseTxExtra(tc.extra)
+			assert.Error(t, err)
+			assert.Len(t, fields, tc.parsed)
+		})
+	}
+}
+
+func TestTxExtraFields(t *testing.T) {
+	t.Parallel()
+
+	extra := monero.TxExtra(concat(
+		[]byte{0x01}, key(1),
+		[]byte{0x04, 1}, key(2),
+		[]byte{0x02, 9, 0x01, 1, 2, 3, 4, 5, 6, 7, 8},
+	))
+
+	fields, err := extra.Fields()
+	require.NoError(t, err)
+
+	assert.Equal(t, key(1), fields.PublicKey())
+	assert.Equal(t, [][]byte{key(2)}, fields.AdditionalPublicKeys())
+	assert.Nil(t, fields.MergeMining())
+	assert.Nil(t, fields.Nonce().PaymentID())
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, fields.Nonce().EncryptedPaymentID())
+
+	_, err = monero.TxExtraFields{
+		{Tag: monero.TxExtraTagPublicKey, PublicKey: []byte{1}},
+	}.Bytes()
+	assert.Error(t, err)
+
+	_, err = monero.TxExtraFields{
+		{Tag: monero.TxExtraTagNonce, Nonce: &monero.TxExtraNonce{
+			Data: make([]byte, monero.TxExtraMaxNonce+1),
+		}},
+	}.Bytes()
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// BatchCall is a single JSONRPC method invocation to be sent as part of a
+// batch (see `JSONRPCBatch`).
+//
+type BatchCall struct {
+	// Method is the name of the JSONRPC method to invoke.
+	//
+	Method string
+
+	// Params are the parameters to be passed to the method.
+	//
+	Params interface{}
+
+	// Result is where the result of the call gets unmarshalled to.
+	//
+	Result interface{}
+
+	// Error is filled with the error that this particular call resulted
+	// in, if any, once the batch has been submitted.
+	//
+	Error error
+}
+
+// batchResponseEnvelope is the envelope of each of the items in the response
+// to a batch, with the result kept raw so that it can be decoded into the
+// right call once correlated by ID.
+//
+type batchResponseEnvelope struct {
+	ID      string          `json:"id"`
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// JSONRPCBatch issues a set of calls under the JSONRPC endpoint in a single
+// HTTP request, correlating each item of the response back to its call by ID.
+//
+// The error returned refers to the batch as a whole (e.g., the server not
+// being reachable), with the outcome of each call being set in its `Error`
+// field.
+//
+// Servers that don't support batching (like `monerod` and
+// `monero-wallet-rpc`, which reply to an array with a single error envelope)
+// are detected, after which calls are sent sequentially via `JSONRPC` from
+// then on.
+//
+func (c *Client) JSONRPCBatch(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	if atomic.LoadInt32(&c.batchUnsupported) == 0 {
+		methods := make([]string, len(calls))
+		for idx, call := range calls {
+			methods[idx] = call.Method
+		}
+
+		var supported bool
+		err := c.retry(ctx, methods, func() error {
+			call := &Call{
+				Kind:   CallKindBatch,
+				Method: methodBatch,
+				Params: calls,
+			}
+
+			return c.intercept(ctx, call, func(ctx context.Context) (err error) {
+				supported, err = c.jsonrpcBatch(ctx, calls)
+				return err
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("batch: %w", err)
+		}
+
+		if supported {
+			return nil
+		}
+
+		atomic.StoreInt32(&c.batchUnsupported, 1)
+	}
+
+	for _, call := range calls {
+		call.Error = c.JSONRPC(ctx, call.Method, call.Params, call.Result)
+	}
+
+	return nil
+}
+
+// jsonrpcBatch submits the calls as a JSONRPC batch, reporting back whether
+// the server replied with a proper batch response.
+//
+func (c *Client) jsonrpcBatch(
+	ctx context.Context, calls []*BatchCall,
+) (bool, error) {
+	address := *c.address
+	address.Path = endpointJSONRPC
+
+	envelopes := make([]*RequestEnvelope, len(calls))
+	for idx, call := range calls {
+		envelopes[idx] = &RequestEnvelope{
+			ID:      strconv.Itoa(idx),
+			JSONRPC: versionJSONRPC,
+			Method:  call.Method,
+			Params:  call.Params,
+		}
+	}
+
+	b, err := json.Marshal(envelopes)
+	if err != nil {
+		return false, fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", address.String(), bytes.NewReader(b))
+	if err != nil {
+		return false, fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("do: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("read all: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	// a server that doesn't understand batches replies with a single
+	// envelope (typically a parse error) rather than an array.
+	//
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		return false, nil
+	}
+
+	items := []*batchResponseEnvelope{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return false, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	replied := make([]bool, len(calls))
+	for _, item := range items {
+		idx, err := strconv.Atoi(item.ID)
+		if err != nil || idx < 0 || idx >= len(calls) {
+			return false, fmt.Errorf("unexpected id '%s'", item.ID)
+		}
+
+		replied[idx] = true
+		calls[idx].Error = decodeBatchItem(item, calls[idx].Result)
+	}
+
+	for idx, ok := range replied {
+		if !ok {
+			calls[idx].Error = fmt.Errorf("no response for id '%d'", idx)
+		}
+	}
+
+	return true, nil
+}
+
+// decodeBatchItem unwraps the result of a single item of a batch response
+// into `result`, applying the same error semantics as `JSONRPC`.
+//
+func decodeBatchItem(item *batchResponseEnvelope, result interface{}) error {
+	if item.Error != nil {
+		return item.Error
+	}
+
+	if result != nil && len(item.Result) != 0 {
+		if err := json.Unmarshal(item.Result, result); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+	}
+
+	return checkStatus(result)
+}
@@ -0,0 +1,133 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// nolint:funlen
+func TestJSONRPCBatch(t *testing.T) {
+	spec.Run(t, "JSONRPCBatch", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			client *rpc.Client
+			err    error
+		)
+
+		it("correlates responses by id", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				reqs := []*rpc.RequestEnvelope{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs))
+				require.Len(t, reqs, 3)
+
+				// reply in reverse order, failing the 2nd one.
+				//
+				fmt.Fprintf(w, `[
+					{"id":%q, "jsonrpc":"2.0", "result": {"method": %q}},
+					{"id":%q, "jsonrpc":"2.0", "error": {"code": -2, "message": "too big"}},
+					{"id":%q, "jsonrpc":"2.0", "result": {"method": %q}}
+				]`,
+					reqs[2].ID, reqs[2].Method,
+					reqs[1].ID,
+					reqs[0].ID, reqs[0].Method,
+				)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			results := []map[string]string{{}, {}, {}}
+			calls := []*rpc.BatchCall{
+				{Method: "a", Result: &results[0]},
+				{Method: "b", Result: &results[1]},
+				{Method: "c", Result: &results[2]},
+			}
+
+			err = client.JSONRPCBatch(ctx, calls)
+			require.NoError(t, err)
+
+			assert.NoError(t, calls[0].Error)
+			assert.Equal(t, "a", results[0]["method"])
+
+			assert.True(t, errors.Is(calls[1].Error, rpc.ErrTooBigHeight))
+
+			assert.NoError(t, calls[2].Error)
+			assert.Equal(t, "c", results[2]["method"])
+		})
+
+		it("falls back to sequential calls if batches not supported", func() {
+			var batches, singles int32
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				raw := json.RawMessage{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+
+				if raw[0] == '[' {
+					atomic.AddInt32(&batches, 1)
+					fmt.Fprintln(w, `{"id":0, "jsonrpc":"2.0", "error": {"code": -32700, "message": "Parse error"}}`)
+					return
+				}
+
+				atomic.AddInt32(&singles, 1)
+				fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "result": {"foo": "bar"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			for i := 0; i < 2; i++ {
+				results := []map[string]string{{}, {}}
+				calls := []*rpc.BatchCall{
+					{Method: "a", Result: &results[0]},
+					{Method: "b", Result: &results[1]},
+				}
+
+				err = client.JSONRPCBatch(ctx, calls)
+				require.NoError(t, err)
+
+				for idx, call := range calls {
+					assert.NoError(t, call.Error)
+					assert.Equal(t, "bar", results[idx]["foo"])
+				}
+			}
+
+			assert.EqualValues(t, 1, atomic.LoadInt32(&batches))
+			assert.EqualValues(t, 4, atomic.LoadInt32(&singles))
+		})
+
+		it("errors w/ non-200 response", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(500)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPCBatch(ctx, []*rpc.BatchCall{{Method: "a"}})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "non-2xx status")
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
@@ -41,13 +41,35 @@ type Client struct {
 	// endpoints.
 	//
 	address *url.URL
+
+	// batchUnsupported is set (atomically) to 1 once the server has been
+	// detected to not support JSONRPC batches.
+	//
+	batchUnsupported int32
+
+	// retryPolicy dictates how calls that failed due to transient errors
+	// should be retried, if at all.
+	//
+	// To provide one, make use of `WithRetryPolicy` when instantiating
+	// the client via the `NewClient` constructor.
+	//
+	retryPolicy *RetryPolicy
+
+	// interceptors are the middlewares that every request goes through.
+	//
+	// To provide them, make use of `WithInterceptors` when instantiating
+	// the client via the `NewClient` constructor.
+	//
+	interceptors []Interceptor
 }
 
 // clientOptions is a set of options that can be overridden to tweak the
 // client's behavior.
 //
 type clientOptions struct {
-	HTTPClient *http.Client
+	HTTPClient   *http.Client
+	RetryPolicy  *RetryPolicy
+	Interceptors []Interceptor
 }
 
 // ClientOption defines a functional option for overriding optional client
@@ -91,8 +113,10 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	}
 
 	return &Client{
-		address: parsedAddress,
-		http:    options.HTTPClient,
+		address:      parsedAddress,
+		http:         options.HTTPClient,
+		retryPolicy:  options.RetryPolicy,
+		interceptors: options.Interceptors,
 	}, nil
 }
 
@@ -102,10 +126,7 @@ type ResponseEnvelope struct {
 	ID      string      `json:"id"`
 	JSONRPC string      `json:"jsonrpc"`
 	Result  interface{} `json:"result,omitempty"`
-	Error   struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
 }
 
 // RequestEnvelope wraps all requests made to the RPC server.
@@ -117,9 +138,31 @@ type RequestEnvelope struct {
 	Params  interface{} `json:"params,omitempty"`
 }
 
-// Request makes requests to any endpoints, not assuming any particular format.
+// RawRequest makes requests to any endpoints, not assuming any particular
+// format.
+//
+// Failures are reported as `*HTTPError` for non-2xx responses, and
+// `*StatusError` for responses whose `status` field is not "OK".
+//
+// If a retry policy has been configured (see `WithRetryPolicy`), requests to
+// endpoints that are safe to be retried are retried on transient failures.
 //
 func (c *Client) RawRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
+	return c.retry(ctx, []string{endpoint}, func() error {
+		call := &Call{
+			Kind:   CallKindRaw,
+			Method: endpoint,
+			Params: params,
+			Result: response,
+		}
+
+		return c.intercept(ctx, call, func(ctx context.Context) error {
+			return c.rawRequest(ctx, endpoint, params, response)
+		})
+	})
+}
+
+func (c *Client) rawRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
 	address := *c.address
 	address.Path = endpoint
 
@@ -145,14 +188,106 @@ func (c *Client) RawRequest(ctx context.Context, endpoint string, params interfa
 		return fmt.Errorf("submit request: %w", err)
 	}
 
+	if err := checkStatus(response); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// BinaryRequest makes a request to one of the binary (`.bin`) endpoints, whose
+// request and response bodies are encoded in epee's portable storage format
+// (see `levin.PortableStorage`), returning the raw response body.
+//
+// Failures are reported as `*HTTPError` for non-2xx responses. As the body is
+// opaque to this client, checking its `status` is left to the caller.
+//
+// If a retry policy has been configured (see `WithRetryPolicy`), requests to
+// endpoints that are safe to be retried are retried on transient failures.
+//
+func (c *Client) BinaryRequest(ctx context.Context, endpoint string, body []byte) ([]byte, error) {
+	var response []byte
+
+	err := c.retry(ctx, []string{endpoint}, func() error {
+		call := &Call{
+			Kind:   CallKindBinary,
+			Method: endpoint,
+			Params: body,
+			Result: &response,
+		}
+
+		return c.intercept(ctx, call, func(ctx context.Context) (err error) {
+			response, err = c.binaryRequest(ctx, endpoint, body)
+			return err
+		})
+	})
+
+	return response, err
+}
+
+func (c *Client) binaryRequest(ctx context.Context, endpoint string, body []byte) ([]byte, error) {
+	address := *c.address
+	address.Path = endpoint
+
+	req, err := http.NewRequestWithContext(ctx, "GET", address.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", "application/octet-stream")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	return response, nil
+}
+
 // JSONRPC issues a request for a particular method under the JSONRPC endpoint
 // with the proper envolope for its requests and unwrapping of results for
 // responses.
 //
+// Failures are reported as `*Error` when the server fills the `error` field of
+// the envelope, `*HTTPError` for non-2xx responses, and `*StatusError` for
+// results whose `status` field is not "OK".
+//
+// If a retry policy has been configured (see `WithRetryPolicy`), calls to
+// methods that are safe to be retried are retried on transient failures.
+//
+// Every attempt goes through the interceptors configured (see
+// `WithInterceptors`), if any.
+//
 func (c *Client) JSONRPC(ctx context.Context, method string, params interface{}, response interface{}) error {
+	return c.retry(ctx, []string{method}, func() error {
+		call := &Call{
+			Kind:   CallKindJSONRPC,
+			Method: method,
+			Params: params,
+			Result: response,
+		}
+
+		return c.intercept(ctx, call, func(ctx context.Context) error {
+			return c.jsonrpc(ctx, method, params, response)
+		})
+	})
+}
+
+func (c *Client) jsonrpc(ctx context.Context, method string, params interface{}, response interface{}) error {
 	address := *c.address
 	address.Path = endpointJSONRPC
 
@@ -181,11 +316,12 @@ func (c *Client) JSONRPC(ctx context.Context, method string, params interface{},
 		return fmt.Errorf("submit request: %w", err)
 	}
 
-	if rpcResponseBody.Error.Code != 0 || rpcResponseBody.Error.Message != "" {
-		return fmt.Errorf("rpc error: code=%d message=%s",
-			rpcResponseBody.Error.Code,
-			rpcResponseBody.Error.Message,
-		)
+	if rpcResponseBody.Error != nil {
+		return rpcResponseBody.Error
+	}
+
+	if err := checkStatus(response); err != nil {
+		return err
 	}
 
 	return nil
@@ -203,11 +339,14 @@ func (c *Client) submitRequest(req *http.Request, response interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("non-2xx status code: %d", resp.StatusCode)
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-		return fmt.Errorf("decode: %w", err)
+		return &decodeError{err}
 	}
 
 	return nil
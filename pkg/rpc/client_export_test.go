@@ -1,3 +1,8 @@
 package rpc
 
 var EndpointJSONRPC = endpointJSONRPC
+
+var (
+	NewTokenBucket = newTokenBucket
+	Reserve        = (*tokenBucket).reserve
+)
@@ -3,6 +3,7 @@ package rpc_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -155,5 +156,132 @@ func TestClient(t *testing.T) {
 			assert.Contains(t, err.Error(), "foo")
 			assert.Contains(t, err.Error(), "-1")
 		})
+
+		it("exposes rpc error as typed error", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"id", "jsonrpc":"jsonrpc", "error": {"code": -9, "message":"Core is busy"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, nil)
+			require.Error(t, err)
+
+			rpcErr := &rpc.Error{}
+			require.True(t, errors.As(err, &rpcErr))
+			assert.Equal(t, rpc.CodeCoreBusy, rpcErr.Code)
+			assert.Equal(t, "Core is busy", rpcErr.Message)
+
+			assert.True(t, errors.Is(err, rpc.ErrCoreBusy))
+			assert.False(t, errors.Is(err, rpc.ErrRestricted))
+		})
+
+		it("exposes non-2xx status as typed error", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(401)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "method", nil, nil)
+			require.Error(t, err)
+
+			httpErr := &rpc.HTTPError{}
+			require.True(t, errors.As(err, &httpErr))
+			assert.Equal(t, 401, httpErr.StatusCode)
+			assert.True(t, errors.Is(err, rpc.ErrUnauthorized))
+		})
+
+		it("fails if result reports non-OK status", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"id", "jsonrpc":"jsonrpc", "result": {"status": "BUSY"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, &statusResult{})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, rpc.ErrStatusBusy))
+		})
+
+		it("succeeds if result reports OK status", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"id", "jsonrpc":"jsonrpc", "result": {"status": "OK"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, &statusResult{})
+			assert.NoError(t, err)
+		})
 	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "RawRequest", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			client *rpc.Client
+			err    error
+		)
+
+		it("fails if response reports non-OK status", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"status": "PAYMENT REQUIRED"}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/get_height", nil, &statusResult{})
+			require.Error(t, err)
+
+			statusErr := &rpc.StatusError{}
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, rpc.StatusPaymentRequired, statusErr.Status)
+			assert.True(t, errors.Is(err, rpc.ErrStatusPaymentRequired))
+		})
+
+		it("ignores status of responses not reporting it", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"status": "BUSY"}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			result := map[string]string{}
+
+			err = client.RawRequest(ctx, "/get_height", nil, &result)
+			assert.NoError(t, err)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+type statusResult struct {
+	Status string `json:"status"`
+}
+
+func (r statusResult) RPCStatus() string {
+	return r.Status
 }
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// jsonrpcBatch submits `calls` as a single batch if the underlying Requester
+// supports it, or one by one otherwise.
+//
+func (c *Client) jsonrpcBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	if batcher, ok := c.Requester.(BatchRequester); ok {
+		return batcher.JSONRPCBatch(ctx, calls)
+	}
+
+	for _, call := range calls {
+		call.Error = c.JSONRPC(ctx, call.Method, call.Params, call.Result)
+	}
+
+	return nil
+}
+
+// BatchBlockHeaderResult is the outcome of one of the calls of a batch
+// submitted through BatchGetBlockHeaderByHeight.
+//
+type BatchBlockHeaderResult struct {
+	// Height is the height of the block whose header has been requested.
+	//
+	Height uint64
+
+	// Result is the response to the call, set only if it succeeded.
+	//
+	Result *GetBlockHeaderByHeightResult
+
+	// Error is the error that the call resulted in, if any.
+	//
+	Error error
+}
+
+// BatchGetBlockHeaderByHeight retrieves the block headers of the blocks at
+// each of the `heights` supplied, batching all the calls to
+// `get_block_header_by_height` in a single request whenever possible.
+//
+// Results are in the same order as `heights`, each carrying either the
+// response or the error that that particular call resulted in: the error
+// returned is only set if the batch as a whole couldn't be submitted.
+//
+func (c *Client) BatchGetBlockHeaderByHeight(
+	ctx context.Context, heights []uint64,
+) ([]BatchBlockHeaderResult, error) {
+	calls := make([]*rpc.BatchCall, len(heights))
+
+	for idx, height := range heights {
+		calls[idx] = &rpc.BatchCall{
+			Method: methodGetBlockHeaderByHeight,
+			Params: map[string]interface{}{
+				"height": height,
+			},
+			Result: &GetBlockHeaderByHeightResult{},
+		}
+	}
+
+	if err := c.jsonrpcBatch(ctx, calls); err != nil {
+		return nil, fmt.Errorf("jsonrpc batch: %w", err)
+	}
+
+	results := make([]BatchBlockHeaderResult, len(heights))
+	for idx, call := range calls {
+		results[idx] = BatchBlockHeaderResult{
+			Height: heights[idx],
+			Error:  call.Error,
+		}
+
+		if call.Error == nil {
+			results[idx].Result = call.Result.(*GetBlockHeaderByHeightResult)
+		}
+	}
+
+	return results, nil
+}
+
+// BatchBlockResult is the outcome of one of the calls of a batch submitted
+// through BatchGetBlock.
+//
+type BatchBlockResult struct {
+	// Params are the parameters that identify the block requested.
+	//
+	Params GetBlockRequestParameters
+
+	// Result is the response to the call, set only if it succeeded.
+	//
+	Result *GetBlockResult
+
+	// Error is the error that the call resulted in, if any.
+	//
+	Error error
+}
+
+// BatchGetBlock fetches full block information for each of the blocks
+// identified by `params`, batching all the calls to `get_block` in a single
+// request whenever possible.
+//
+// Results are in the same order as `params`, each carrying either the
+// response or the error that that particular call resulted in (including
+// failing verification, see `WithVerification`): the error returned is only
+// set if the batch as a whole couldn't be submitted.
+//
+func (c *Client) BatchGetBlock(
+	ctx context.Context, params []GetBlockRequestParameters,
+) ([]BatchBlockResult, error) {
+	calls := make([]*rpc.BatchCall, len(params))
+
+	for idx, p := range params {
+		calls[idx] = &rpc.BatchCall{
+			Method: methodGetBlock,
+			Params: p,
+			Result: &GetBlockResult{},
+		}
+	}
+
+	if err := c.jsonrpcBatch(ctx, calls); err != nil {
+		return nil, fmt.Errorf("jsonrpc batch: %w", err)
+	}
+
+	results := make([]BatchBlockResult, len(params))
+	for idx, call := range calls {
+		results[idx] = BatchBlockResult{
+			Params: params[idx],
+			Error:  call.Error,
+		}
+
+		if call.Error != nil {
+			continue
+		}
+
+		result := call.Result.(*GetBlockResult)
+		if c.verify {
+			if err := result.Verify(); err != nil {
+				results[idx].Error = fmt.Errorf("verify: %w", err)
+				continue
+			}
+		}
+
+		results[idx].Result = result
+	}
+
+	return results, nil
+}
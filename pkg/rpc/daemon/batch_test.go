@@ -0,0 +1,70 @@
+package daemon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestBatch(t *testing.T) {
+	spec.Run(t, "Batch", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(10))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("keeps the results of the calls that succeeded", func() {
+			resps, err := client.BatchGetBlockHeaderByHeight(ctx, []uint64{3, 100, 5})
+			require.NoError(t, err)
+			require.Len(t, resps, 3)
+
+			require.NoError(t, resps[0].Error)
+			assert.EqualValues(t, 3, resps[0].Result.BlockHeader.Height)
+
+			assert.Error(t, resps[1].Error)
+			assert.EqualValues(t, 100, resps[1].Height)
+			assert.Nil(t, resps[1].Result)
+
+			require.NoError(t, resps[2].Error)
+			assert.EqualValues(t, 5, resps[2].Result.BlockHeader.Height)
+		})
+
+		it("does the same for full blocks", func() {
+			resps, err := client.BatchGetBlock(ctx, []daemon.GetBlockRequestParameters{
+				{Height: 100},
+				{Height: 4},
+			})
+			require.NoError(t, err)
+			require.Len(t, resps, 2)
+
+			assert.Error(t, resps[0].Error)
+			assert.EqualValues(t, 100, resps[0].Params.Height)
+			assert.Nil(t, resps[0].Result)
+
+			require.NoError(t, resps[1].Error)
+			assert.EqualValues(t, 4, resps[1].Result.BlockHeader.Height)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
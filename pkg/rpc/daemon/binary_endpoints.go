@@ -0,0 +1,485 @@
+package daemon
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/levin"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+const (
+	endpointGetBlocksBin             = "/get_blocks.bin"
+	endpointGetBlocksByHeightBin     = "/get_blocks_by_height.bin"
+	endpointGetHashesBin             = "/get_hashes.bin"
+	endpointGetOIndexesBin           = "/get_o_indexes.bin"
+	endpointGetOutsBin               = "/get_outs.bin"
+	endpointGetOutputDistributionBin = "/get_output_distribution.bin"
+)
+
+// GetBlocksBin retrieves, in bulk, the blocks (and their transactions) that
+// follow the most recent of `BlockIDs` known to the daemon, starting at least
+// from `StartHeight`.
+//
+// `BlockIDs` is a "short chain history": hashes of blocks from the most recent
+// to the oldest, which must always end with the genesis block.
+//
+func (c *Client) GetBlocksBin(
+	ctx context.Context, params GetBlocksBinRequestParameters,
+) (*GetBlocksBinResult, error) {
+	blockIDs, err := hashesBlob(params.BlockIDs)
+	if err != nil {
+		return nil, fmt.Errorf("block ids: %w", err)
+	}
+
+	entries, err := c.binaryRequest(ctx, endpointGetBlocksBin, levin.Entries{
+		{Name: "block_ids", Serializable: blockIDs},
+		{Name: "start_height", Serializable: levin.Uint64(params.StartHeight)},
+		{Name: "prune", Serializable: levin.Bool(params.Prune)},
+		{Name: "no_miner_tx", Serializable: levin.Bool(params.NoMinerTx)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	resp := &GetBlocksBinResult{
+		Blocks:          blockEntries(entries),
+		StartHeight:     entryUint64(entries, "start_height"),
+		CurrentHeight:   entryUint64(entries, "current_height"),
+		RPCResultFooter: footerFromEntries(entries),
+	}
+
+	for _, block := range entryObjects(entries, "output_indices") {
+		txs := [][]uint64{}
+		for _, tx := range entryObjects(block, "indices") {
+			txs = append(txs, entryUint64s(tx, "indices"))
+		}
+
+		resp.OutputIndices = append(resp.OutputIndices, txs)
+	}
+
+	return resp, nil
+}
+
+// GetBlocksByHeightBin retrieves, in bulk, the blocks (and their
+// transactions) at each of the `heights` supplied.
+//
+func (c *Client) GetBlocksByHeightBin(
+	ctx context.Context, heights []uint64,
+) (*GetBlocksByHeightBinResult, error) {
+	entries, err := c.binaryRequest(ctx, endpointGetBlocksByHeightBin, levin.Entries{
+		{Name: "heights", Serializable: uint64Array(heights)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return &GetBlocksByHeightBinResult{
+		Blocks:          blockEntries(entries),
+		RPCResultFooter: footerFromEntries(entries),
+	}, nil
+}
+
+// GetHashesBin retrieves the hashes of the blocks that follow the most recent
+// of `blockIDs` known to the daemon, starting at least from `startHeight`.
+//
+func (c *Client) GetHashesBin(
+	ctx context.Context, blockIDs []string, startHeight uint64,
+) (*GetHashesBinResult, error) {
+	ids, err := hashesBlob(blockIDs)
+	if err != nil {
+		return nil, fmt.Errorf("block ids: %w", err)
+	}
+
+	entries, err := c.binaryRequest(ctx, endpointGetHashesBin, levin.Entries{
+		{Name: "block_ids", Serializable: ids},
+		{Name: "start_height", Serializable: levin.Uint64(startHeight)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return &GetHashesBinResult{
+		BlockIDs:        entryHashes(entries, "m_block_ids"),
+		StartHeight:     entryUint64(entries, "start_height"),
+		CurrentHeight:   entryUint64(entries, "current_height"),
+		RPCResultFooter: footerFromEntries(entries),
+	}, nil
+}
+
+// GetOIndexesBin retrieves the global output indexes of the outputs of the
+// transaction whose hash is `txid`.
+//
+func (c *Client) GetOIndexesBin(
+	ctx context.Context, txid string,
+) (*GetOIndexesBinResult, error) {
+	id, err := hashesBlob([]string{txid})
+	if err != nil {
+		return nil, fmt.Errorf("txid: %w", err)
+	}
+
+	entries, err := c.binaryRequest(ctx, endpointGetOIndexesBin, levin.Entries{
+		{Name: "txid", Serializable: id},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return &GetOIndexesBinResult{
+		OIndexes:        entryUint64s(entries, "o_indexes"),
+		RPCResultFooter: footerFromEntries(entries),
+	}, nil
+}
+
+// GetOutsBin retrieves the public keys, commitments and unlock status of the
+// outputs supplied (identified by amount and index).
+//
+func (c *Client) GetOutsBin(
+	ctx context.Context, outputs []GetOutsBinOutput, getTxID bool,
+) (*GetOutsBinResult, error) {
+	outs := make([]levin.Serializable, len(outputs))
+	for idx, output := range outputs {
+		outs[idx] = levin.Section{
+			Entries: []levin.Entry{
+				{Name: "amount", Serializable: levin.Uint64(output.Amount)},
+				{Name: "index", Serializable: levin.Uint64(output.Index)},
+			},
+		}
+	}
+
+	entries, err := c.binaryRequest(ctx, endpointGetOutsBin, levin.Entries{
+		{Name: "outputs", Serializable: levin.Array{Type: levin.TypeObject, Values: outs}},
+		{Name: "get_txid", Serializable: levin.Bool(getTxID)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	resp := &GetOutsBinResult{
+		RPCResultFooter: footerFromEntries(entries),
+	}
+
+	for _, out := range entryObjects(entries, "outs") {
+		resp.Outs = append(resp.Outs, OutKey{
+			Key:      entryHash(out, "key"),
+			Mask:     entryHash(out, "mask"),
+			Unlocked: entryBool(out, "unlocked"),
+			Height:   entryUint64(out, "height"),
+			TxID:     entryHash(out, "txid"),
+		})
+	}
+
+	return resp, nil
+}
+
+// GetOutputDistributionBin retrieves, for each of the amounts supplied, the
+// number of outputs created at each height.
+//
+// Distributions are always requested in binary form (optionally compressed),
+// and decoded back into plain integers.
+//
+func (c *Client) GetOutputDistributionBin(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionResult, error) {
+	entries, err := c.binaryRequest(ctx, endpointGetOutputDistributionBin, levin.Entries{
+		{Name: "amounts", Serializable: uint64Array(params.Amounts)},
+		{Name: "from_height", Serializable: levin.Uint64(params.FromHeight)},
+		{Name: "to_height", Serializable: levin.Uint64(params.ToHeight)},
+		{Name: "cumulative", Serializable: levin.Bool(params.Cumulative)},
+		{Name: "binary", Serializable: levin.Bool(true)},
+		{Name: "compress", Serializable: levin.Bool(params.Compress)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	resp := &GetOutputDistributionResult{
+		RPCResultFooter: footerFromEntries(entries),
+	}
+
+	for _, entries := range entryObjects(entries, "distributions") {
+		distribution, err := distributionFromEntries(entries)
+		if err != nil {
+			return nil, fmt.Errorf("distribution: %w", err)
+		}
+
+		resp.Distributions = append(resp.Distributions, *distribution)
+	}
+
+	return resp, nil
+}
+
+// binaryRequest encodes `params` in portable storage format, submits them to
+// the binary endpoint `endpoint`, and decodes the response, surfacing non-OK
+// statuses as `*rpc.StatusError`.
+//
+func (c *Client) binaryRequest(
+	ctx context.Context, endpoint string, params levin.Entries,
+) (levin.Entries, error) {
+	requester, ok := c.Requester.(BinaryRequester)
+	if !ok {
+		return nil, fmt.Errorf("requester doesn't support binary requests")
+	}
+
+	body, err := requester.BinaryRequest(ctx, endpoint,
+		(&levin.PortableStorage{Entries: params}).Bytes(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeBinaryResponse(body)
+}
+
+func decodeBinaryResponse(body []byte) (levin.Entries, error) {
+	ps, err := levin.NewPortableStorageFromBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	status := entryString(ps.Entries, "status")
+	if status != "" && status != rpc.StatusOK {
+		return nil, &rpc.StatusError{Status: status}
+	}
+
+	return ps.Entries, nil
+}
+
+func footerFromEntries(entries levin.Entries) RPCResultFooter {
+	return RPCResultFooter{
+		Status:    entryString(entries, "status"),
+		Untrusted: entryBool(entries, "untrusted"),
+		Credits:   entryUint64(entries, "credits"),
+		TopHash:   entryString(entries, "top_hash"),
+	}
+}
+
+func blockEntries(entries levin.Entries) []BlockCompleteEntry {
+	blocks := []BlockCompleteEntry{}
+
+	for _, block := range entryObjects(entries, "blocks") {
+		entry := BlockCompleteEntry{
+			Pruned:      entryBool(block, "pruned"),
+			Block:       []byte(entryString(block, "block")),
+			BlockWeight: entryUint64(block, "block_weight"),
+		}
+
+		// pruned blocks carry their txs as objects (blob + prunable
+		// hash), while full ones carry plain blobs.
+		//
+		txs, _ := block.Get("txs")
+		array, _ := txs.Value.(levin.Entries)
+
+		for _, tx := range array {
+			switch v := tx.Value.(type) {
+			case string:
+				entry.Txs = append(entry.Txs, TxBlobEntry{
+					Blob: []byte(v),
+				})
+			case levin.Entries:
+				entry.Txs = append(entry.Txs, TxBlobEntry{
+					Blob:         []byte(entryString(v, "blob")),
+					PrunableHash: entryHash(v, "prunable_hash"),
+				})
+			}
+		}
+
+		blocks = append(blocks, entry)
+	}
+
+	return blocks
+}
+
+func distributionFromEntries(entries levin.Entries) (*Distribution, error) {
+	d := &Distribution{
+		Amount:      entryUint64(entries, "amount"),
+		StartHeight: entryUint64(entries, "start_height"),
+		Base:        entryUint64(entries, "base"),
+	}
+
+	switch {
+	case !entryBool(entries, "binary"):
+		d.Distribution = entryUint64s(entries, "distribution")
+	case entryBool(entries, "compress"):
+		v, err := decodeCompressedDistribution(
+			[]byte(entryString(entries, "compressed_data")),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+
+		d.Distribution = v
+	default:
+		v, err := decodeDistributionBlob(
+			[]byte(entryString(entries, "distribution")),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		d.Distribution = v
+	}
+
+	return d, nil
+}
+
+// decodeDistributionBlob decodes an output distribution serialized as a
+// sequence of little-endian 64-bit integers.
+//
+func decodeDistributionBlob(blob []byte) ([]uint64, error) {
+	if len(blob)%8 != 0 {
+		return nil, fmt.Errorf("distribution blob size %d not multiple of 8", len(blob))
+	}
+
+	res := make([]uint64, len(blob)/8)
+	for idx := range res {
+		res[idx] = binary.LittleEndian.Uint64(blob[idx*8:])
+	}
+
+	return res, nil
+}
+
+// decodeCompressedDistribution decodes an output distribution compressed by
+// the daemon as a sequence of varints.
+//
+func decodeCompressedDistribution(b []byte) ([]uint64, error) {
+	res := []uint64{}
+
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed varint")
+		}
+
+		res = append(res, v)
+		b = b[n:]
+	}
+
+	return res, nil
+}
+
+// hashesBlob packs hex-encoded hashes one after the other in a single blob
+// (the way epee serializes containers of PODs).
+//
+func hashesBlob(hashes []string) (levin.String, error) {
+	blob := make([]byte, 0, len(hashes)*32)
+
+	for _, hash := range hashes {
+		b, err := hex.DecodeString(hash)
+		if err != nil {
+			return "", fmt.Errorf("hex decode '%s': %w", hash, err)
+		}
+
+		if len(b) != 32 {
+			return "", fmt.Errorf("hash '%s' not 32 bytes long", hash)
+		}
+
+		blob = append(blob, b...)
+	}
+
+	return levin.String(blob), nil
+}
+
+func uint64Array(values []uint64) levin.Array {
+	arr := levin.Array{
+		Type:   levin.TypeUint64,
+		Values: make([]levin.Serializable, len(values)),
+	}
+
+	for idx, v := range values {
+		arr.Values[idx] = levin.Uint64(v)
+	}
+
+	return arr
+}
+
+// the accessors below are lenient with regards to missing entries (epee
+// omits empty containers and fields with default values) and to the width of
+// integers, so that a misbehaving node can't make decoding panic.
+
+func entryString(entries levin.Entries, name string) string {
+	entry, _ := entries.Get(name)
+	v, _ := entry.Value.(string)
+
+	return v
+}
+
+func entryBool(entries levin.Entries, name string) bool {
+	entry, _ := entries.Get(name)
+	v, _ := entry.Value.(bool)
+
+	return v
+}
+
+func entryUint64(entries levin.Entries, name string) uint64 {
+	entry, _ := entries.Get(name)
+
+	return toUint64(entry.Value)
+}
+
+func toUint64(value interface{}) uint64 {
+	switch v := value.(type) {
+	case uint64:
+		return v
+	case uint32:
+		return uint64(v)
+	case uint16:
+		return uint64(v)
+	case uint8:
+		return uint64(v)
+	case int64:
+		return uint64(v)
+	case int32:
+		return uint64(v)
+	case int16:
+		return uint64(v)
+	case int8:
+		return uint64(v)
+	default:
+		return 0
+	}
+}
+
+func entryUint64s(entries levin.Entries, name string) []uint64 {
+	entry, _ := entries.Get(name)
+	array, _ := entry.Value.(levin.Entries)
+
+	res := make([]uint64, len(array))
+	for idx, item := range array {
+		res[idx] = toUint64(item.Value)
+	}
+
+	return res
+}
+
+func entryObjects(entries levin.Entries, name string) []levin.Entries {
+	entry, _ := entries.Get(name)
+	array, _ := entry.Value.(levin.Entries)
+
+	res := []levin.Entries{}
+	for _, item := range array {
+		if obj, ok := item.Value.(levin.Entries); ok {
+			res = append(res, obj)
+		}
+	}
+
+	return res
+}
+
+func entryHash(entries levin.Entries, name string) string {
+	return hex.EncodeToString([]byte(entryString(entries, name)))
+}
+
+func entryHashes(entries levin.Entries, name string) []string {
+	blob := []byte(entryString(entries, name))
+
+	res := make([]string, 0, len(blob)/32)
+	for len(blob) >= 32 {
+		res = append(res, hex.EncodeToString(blob[:32]))
+		blob = blob[32:]
+	}
+
+	return res
+}
@@ -0,0 +1,201 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/levin"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+// binaryServer is a fake monerod that decodes the portable storage request
+// and replies with whatever `reply` gives for it.
+//
+func binaryServer(
+	t *testing.T, endpoint string, reply func(req levin.Entries) levin.Entries,
+) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, endpoint, r.URL.Path)
+
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			ps, err := levin.NewPortableStorageFromBytes(body)
+			require.NoError(t, err)
+
+			_, _ = w.Write((&levin.PortableStorage{
+				Entries: reply(ps.Entries),
+			}).Bytes())
+		},
+	))
+}
+
+func binaryClient(t *testing.T, server *httptest.Server) *daemon.Client {
+	client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	return daemon.NewClient(client)
+}
+
+// nolint:funlen
+func TestBinaryEndpoints(t *testing.T) {
+	spec.Run(t, "BinaryEndpoints", func(t *testing.T, when spec.G, it spec.S) {
+		ctx := context.Background()
+		ok := levin.Entry{Name: "status", Serializable: levin.String("OK")}
+
+		it("GetBlocksByHeightBin", func() {
+			server := binaryServer(t, "/get_blocks_by_height.bin",
+				func(req levin.Entries) levin.Entries {
+					heights, found := req.Get("heights")
+					require.True(t, found)
+					require.Equal(t, levin.Entries{
+						{Value: uint64(10)}, {Value: uint64(11)},
+					}, heights.Entries())
+
+					blocks := make([]levin.Serializable, 2)
+					for idx := range blocks {
+						blocks[idx] = levin.Section{
+							Entries: []levin.Entry{
+								{Name: "block", Serializable: levin.String("block")},
+								{Name: "txs", Serializable: levin.Array{
+									Type: levin.TypeString,
+									Values: []levin.Serializable{
+										levin.String("tx"),
+									},
+								}},
+							},
+						}
+					}
+
+					return levin.Entries{ok, {
+						Name: "blocks",
+						Serializable: levin.Array{
+							Type:   levin.TypeObject,
+							Values: blocks,
+						},
+					}}
+				},
+			)
+			defer server.Close()
+
+			resp, err := binaryClient(t, server).GetBlocksByHeightBin(ctx, []uint64{10, 11})
+			require.NoError(t, err)
+
+			assert.Equal(t, "OK", resp.Status)
+			require.Len(t, resp.Blocks, 2)
+			assert.Equal(t, []byte("block"), resp.Blocks[0].Block)
+			assert.Equal(t, []daemon.TxBlobEntry{{Blob: []byte("tx")}}, resp.Blocks[1].Txs)
+		})
+
+		it("GetOutsBin", func() {
+			key := strings.Repeat("ab", 32)
+
+			server := binaryServer(t, "/get_outs.bin",
+				func(req levin.Entries) levin.Entries {
+					outputs, found := req.Get("outputs")
+					require.True(t, found)
+					require.Len(t, outputs.Entries(), 1)
+
+					return levin.Entries{ok, {
+						Name: "outs",
+						Serializable: levin.Array{
+							Type: levin.TypeObject,
+							Values: []levin.Serializable{
+								levin.Section{
+									Entries: []levin.Entry{
+										{Name: "key", Serializable: levin.String(strings.Repeat("\xab", 32))},
+										{Name: "unlocked", Serializable: levin.Bool(true)},
+										{Name: "height", Serializable: levin.Uint64(42)},
+									},
+								},
+							},
+						},
+					}}
+				},
+			)
+			defer server.Close()
+
+			resp, err := binaryClient(t, server).GetOutsBin(ctx,
+				[]daemon.GetOutsBinOutput{{Index: 1}}, false)
+			require.NoError(t, err)
+
+			require.Len(t, resp.Outs, 1)
+			assert.Equal(t, key, resp.Outs[0].Key)
+			assert.True(t, resp.Outs[0].Unlocked)
+			assert.EqualValues(t, 42, resp.Outs[0].Height)
+		})
+
+		it("GetOutputDistributionBin decodes compressed distributions", func() {
+			var compressed []byte
+			for _, v := range []uint64{1, 300, 70000} {
+				b := make([]byte, binary.MaxVarintLen64)
+				compressed = append(compressed, b[:binary.PutUvarint(b, v)]...)
+			}
+
+			server := binaryServer(t, "/get_output_distribution.bin",
+				func(req levin.Entries) levin.Entries {
+					return levin.Entries{ok, {
+						Name: "distributions",
+						Serializable: levin.Array{
+							Type: levin.TypeObject,
+							Values: []levin.Serializable{
+								levin.Section{
+									Entries: []levin.Entry{
+										{Name: "start_height", Serializable: levin.Uint64(5)},
+										{Name: "binary", Serializable: levin.Bool(true)},
+										{Name: "compress", Serializable: levin.Bool(true)},
+										{Name: "compressed_data", Serializable: levin.String(compressed)},
+									},
+								},
+							},
+						},
+					}}
+				},
+			)
+			defer server.Close()
+
+			resp, err := binaryClient(t, server).GetOutputDistributionBin(ctx,
+				daemon.GetOutputDistributionRequestParameters{
+					Amounts:  []uint64{0},
+					Compress: true,
+				})
+			require.NoError(t, err)
+
+			require.Len(t, resp.Distributions, 1)
+			assert.EqualValues(t, 5, resp.Distributions[0].StartHeight)
+			assert.Equal(t, []uint64{1, 300, 70000}, resp.Distributions[0].Distribution)
+		})
+
+		it("surfaces non-OK statuses", func() {
+			server := binaryServer(t, "/get_o_indexes.bin",
+				func(req levin.Entries) levin.Entries {
+					return levin.Entries{
+						{Name: "status", Serializable: levin.String("BUSY")},
+					}
+				},
+			)
+			defer server.Close()
+
+			_, err := binaryClient(t, server).GetOIndexesBin(ctx, strings.Repeat("00", 32))
+			assert.True(t, errors.Is(err, rpc.ErrStatusBusy))
+		})
+
+		it("fails w/ malformed hashes", func() {
+			_, err := daemon.NewClient(nil).GetHashesBin(ctx, []string{"foo"}, 0)
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel())
+}
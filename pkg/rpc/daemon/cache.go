@@ -0,0 +1,613 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+const (
+	// DefaultCacheConfirmations is the default number of blocks that must
+	// have been mined on top of a block for data from it to be cached.
+	//
+	DefaultCacheConfirmations = 10
+
+	// DefaultCacheCapacity is the default maximum number of entries held
+	// by the in-memory store used when none is supplied.
+	//
+	DefaultCacheCapacity = 4096
+
+	// tipRefreshInterval is how often the chain's tip is refreshed when
+	// deciding whether data is deep enough to be cached.
+	//
+	tipRefreshInterval = time.Minute
+)
+
+// CacheStats are statistics about how a Cache has been doing.
+//
+type CacheStats struct {
+	// Hits is the number of requests served from the cache.
+	//
+	Hits uint64
+
+	// Misses is the number of cacheable requests that were not in the
+	// cache.
+	//
+	Misses uint64
+
+	// Stores is the number of results that have been cached.
+	//
+	Stores uint64
+
+	// Invalidations is the number of entries dropped due to reorgs.
+	//
+	Invalidations uint64
+}
+
+// HitRatio gives the fraction of cacheable requests served from the cache.
+//
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type cacheOptions struct {
+	store         CacheStore
+	confirmations uint64
+}
+
+// CacheOption defines a functional option for overriding optional cache
+// configuration parameters.
+//
+type CacheOption func(o *cacheOptions)
+
+// WithCacheStore sets where cached entries are kept (default: an in-memory
+// LRU store with `DefaultCacheCapacity` entries).
+//
+func WithCacheStore(v CacheStore) CacheOption {
+	return func(o *cacheOptions) {
+		o.store = v
+	}
+}
+
+// WithConfirmations sets how many blocks must have been mined on top of a
+// block for data from it to be cached (default: `DefaultCacheConfirmations`).
+//
+func WithConfirmations(v uint64) CacheOption {
+	return func(o *cacheOptions) {
+		o.confirmations = v
+	}
+}
+
+// cacheEntry is what gets stored for each cached result.
+//
+type cacheEntry struct {
+	// Height is the tallest height of the blocks the result refers to.
+	//
+	Height uint64 `json:"height"`
+
+	// ByHeight indicates that the result is addressed by height (or
+	// depends on the chain's layout, like outputs' global indices), thus
+	// not holding anymore in case of a reorg at or below `Height`.
+	//
+	ByHeight bool `json:"by_height"`
+
+	Result json.RawMessage `json:"result"`
+}
+
+// Cache is a Requester that caches results from the data that don't change
+// once deep enough in the chain: block headers, blocks, transactions and
+// outputs, addressed either by hash or, below a number of confirmations, by
+// height.
+//
+// Entries addressed by height are invalidated when a reorg is detected, i.e.,
+// once the daemon reports a block whose hash differs from the one seen
+// before at the same height (or by explicitly calling `Invalidate`).
+//
+// Everything else is passed through to the underlying Requester untouched.
+//
+type Cache struct {
+	requester Requester
+	opts      cacheOptions
+
+	mu         sync.Mutex
+	top        uint64
+	topUpdated time.Time
+	hashes     map[uint64]string
+	stats      CacheStats
+}
+
+var (
+	_ Requester       = (*Cache)(nil)
+	_ BatchRequester  = (*Cache)(nil)
+	_ BinaryRequester = (*Cache)(nil)
+)
+
+// NewCache instantiates a new Cache in front of the Requester supplied.
+//
+func NewCache(r Requester, opts ...CacheOption) *Cache {
+	options := cacheOptions{
+		confirmations: DefaultCacheConfirmations,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.store == nil {
+		options.store = NewLRUCacheStore(DefaultCacheCapacity)
+	}
+
+	return &Cache{
+		requester: r,
+		opts:      options,
+		hashes:    map[uint64]string{},
+	}
+}
+
+// Stats gives a snapshot of the statistics of the cache.
+//
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// JSONRPC implements Requester, serving cacheable methods from the cache
+// whenever possible.
+//
+func (c *Cache) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return c.do(ctx, method, params, result, func() error {
+		return c.requester.JSONRPC(ctx, method, params, result)
+	})
+}
+
+// RawRequest implements Requester, serving cacheable endpoints from the cache
+// whenever possible.
+//
+func (c *Cache) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return c.do(ctx, endpoint, params, response, func() error {
+		return c.requester.RawRequest(ctx, endpoint, params, response)
+	})
+}
+
+// JSONRPCBatch implements BatchRequester, serving the calls that are cached
+// and submitting only the rest.
+//
+func (c *Cache) JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	missing := []*rpc.BatchCall{}
+
+	for _, call := range calls {
+		if !c.lookup(call.Method, call.Params, call.Result) {
+			missing = append(missing, call)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := NewClient(c.requester).jsonrpcBatch(ctx, missing); err != nil {
+		return err
+	}
+
+	for _, call := range missing {
+		if call.Error == nil {
+			c.store(ctx, call.Method, call.Params, call.Result)
+		}
+	}
+
+	return nil
+}
+
+// BinaryRequest implements BinaryRequester by passing the request through to
+// the underlying Requester.
+//
+func (c *Cache) BinaryRequest(
+	ctx context.Context, endpoint string, body []byte,
+) ([]byte, error) {
+	requester, ok := c.requester.(BinaryRequester)
+	if !ok {
+		return nil, fmt.Errorf("requester doesn't support binary requests")
+	}
+
+	return requester.BinaryRequest(ctx, endpoint, body)
+}
+
+// Invalidate drops all entries addressed by height that refer to blocks at
+// `height` or above, e.g., due to a reorg.
+//
+func (c *Cache) Invalidate(height uint64) error {
+	keys := []string{}
+
+	err := c.opts.store.Range(func(key string, value []byte) bool {
+		entry := &cacheEntry{}
+		if err := json.Unmarshal(value, entry); err != nil || (entry.ByHeight && entry.Height >= height) {
+			keys = append(keys, key)
+		}
+
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("range: %w", err)
+	}
+
+	for _, key := range keys {
+		if err := c.opts.store.Delete(key); err != nil {
+			return fmt.Errorf("delete: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for h := range c.hashes {
+		if h >= height {
+			delete(c.hashes, h)
+		}
+	}
+
+	c.stats.Invalidations += uint64(len(keys))
+
+	return nil
+}
+
+// do serves the request from the cache if possible, falling back to `fetch`
+// and caching what it got back otherwise.
+//
+func (c *Cache) do(
+	ctx context.Context, method string, params, result interface{},
+	fetch func() error,
+) error {
+	if c.lookup(method, params, result) {
+		return nil
+	}
+
+	if err := fetch(); err != nil {
+		return err
+	}
+
+	c.store(ctx, method, params, result)
+
+	return nil
+}
+
+// lookup fills `result` with the cached result of the call, if any, telling
+// whether it did so.
+//
+func (c *Cache) lookup(method string, params, result interface{}) bool {
+	if !cacheable(method, result) {
+		return false
+	}
+
+	key, err := cacheKey(method, params)
+	if err != nil {
+		return false
+	}
+
+	value, found, err := c.opts.store.Get(key)
+	if err == nil && found {
+		entry := &cacheEntry{}
+		if json.Unmarshal(value, entry) == nil && json.Unmarshal(entry.Result, result) == nil {
+			c.mu.Lock()
+			c.stats.Hits++
+			top := c.top
+			c.mu.Unlock()
+
+			refreshDepth(result, top)
+
+			return true
+		}
+	}
+
+	c.mu.Lock()
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	return false
+}
+
+// store caches the result of a call if it's deep enough in the chain,
+// looking for reorgs in what's been fetched along the way.
+//
+// Failing to cache is not an error: the call has been served anyway.
+//
+func (c *Cache) store(ctx context.Context, method string, params, result interface{}) {
+	headers := blockHeaders(result)
+	c.observe(headers)
+
+	if !cacheable(method, result) {
+		return
+	}
+
+	height, byHeight, complete := resultHeight(params, result)
+	if !complete || !c.deep(ctx, height) {
+		return
+	}
+
+	key, err := cacheKey(method, params)
+	if err != nil {
+		return
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	value, err := json.Marshal(&cacheEntry{
+		Height:   height,
+		ByHeight: byHeight,
+		Result:   b,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := c.opts.store.Put(key, value); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Stores++
+
+	for _, header := range headers {
+		c.hashes[header.Height] = header.Hash
+	}
+}
+
+// observe keeps track of the tip of the chain and detects reorgs from the
+// block headers seen.
+//
+func (c *Cache) observe(headers []*BlockHeader) {
+	var reorgHeight *uint64
+
+	c.mu.Lock()
+	for _, header := range headers {
+		if top := header.Height + header.Depth; top > c.top {
+			c.top = top
+			c.topUpdated = time.Now()
+		}
+
+		hash, found := c.hashes[header.Height]
+		if found && hash != header.Hash && (reorgHeight == nil || header.Height < *reorgHeight) {
+			height := header.Height
+			reorgHeight = &height
+		}
+	}
+	c.mu.Unlock()
+
+	if reorgHeight != nil {
+		_ = c.Invalidate(*reorgHeight)
+	}
+}
+
+// deep tells whether a block at `height` has enough confirmations for data
+// from it to be cached, refreshing what's known about the tip of the chain
+// if needed.
+//
+func (c *Cache) deep(ctx context.Context, height uint64) bool {
+	c.mu.Lock()
+	top, updated := c.top, c.topUpdated
+	c.mu.Unlock()
+
+	if height+c.opts.confirmations > top && time.Since(updated) > tipRefreshInterval {
+		resp := &GetBlockCountResult{}
+		if err := c.requester.JSONRPC(ctx, methodGetBlockCount, nil, resp); err == nil && resp.Count > 0 {
+			c.mu.Lock()
+			if resp.Count-1 > c.top {
+				c.top = resp.Count - 1
+			}
+			c.topUpdated = time.Now()
+			top = c.top
+			c.mu.Unlock()
+		}
+	}
+
+	return height+c.opts.confirmations <= top
+}
+
+// cacheable tells whether results of calls to `method` can be cached at all,
+// which depends on them being unmarshalled to the types this package uses
+// for them.
+//
+func cacheable(method string, result interface{}) bool {
+	switch result.(type) {
+	case *GetBlockHeaderByHeightResult:
+		return method == methodGetBlockHeaderByHeight
+	case *GetBlockHeaderByHashResult:
+		return method == methodGetBlockHeaderByHash
+	case *GetBlockResult:
+		return method == methodGetBlock
+	case *GetTransactionsResult:
+		return method == endpointGetTransactions
+	case *GetOutsResult:
+		return method == endpointGetOuts
+	default:
+		return false
+	}
+}
+
+// resultHeight gives the tallest height of the blocks that a (cacheable)
+// result refers to, whether the result is addressed by height, and whether
+// it's complete enough to be cached at all (e.g., not including transactions
+// still in the pool or outputs still locked).
+//
+func resultHeight(params, result interface{}) (uint64, bool, bool) {
+	var height uint64
+
+	switch r := result.(type) {
+	case *GetBlockHeaderByHeightResult:
+		return r.BlockHeader.Height, true, true
+	case *GetBlockHeaderByHashResult:
+		for _, header := range blockHeaders(r) {
+			if header.Height > height {
+				height = header.Height
+			}
+		}
+
+		return height, false, true
+	case *GetBlockResult:
+		return r.BlockHeader.Height, blockRequestedByHeight(params), true
+	case *GetTransactionsResult:
+		// a partial reply (some transactions not known yet) would keep
+		// being served even once those get mined.
+		//
+		if len(r.Txs) == 0 || len(r.MissedTx) > 0 ||
+			len(r.Txs) != len(requestedTxs(params)) {
+			return 0, false, false
+		}
+
+		for _, tx := range r.Txs {
+			if tx.InPool {
+				return 0, false, false
+			}
+
+			if tx.BlockHeight > height {
+				height = tx.BlockHeight
+			}
+		}
+
+		// the block a transaction has been mined in (`block_height`)
+		// doesn't hold anymore after a reorg.
+		//
+		return height, true, true
+	case *GetOutsResult:
+		if len(r.Outs) == 0 {
+			return 0, false, false
+		}
+
+		for _, out := range r.Outs {
+			if !out.Unlocked {
+				return 0, false, false
+			}
+
+			if out.Height > height {
+				height = out.Height
+			}
+		}
+
+		// global output indices depend on the chain's layout.
+		//
+		return height, true, true
+	default:
+		return 0, false, false
+	}
+}
+
+// blockRequestedByHeight tells whether `get_block` has been called with a
+// height rather than a hash.
+//
+func blockRequestedByHeight(params interface{}) bool {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return true
+	}
+
+	p := &GetBlockRequestParameters{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return true
+	}
+
+	return p.Hash == ""
+}
+
+// blockHeaders gives the block headers included in a result, if any.
+//
+func blockHeaders(result interface{}) []*BlockHeader {
+	headers := []*BlockHeader{}
+
+	for _, header := range allBlockHeaders(result) {
+		if header.Hash != "" {
+			headers = append(headers, header)
+		}
+	}
+
+	return headers
+}
+
+// allBlockHeaders gives all the block headers fields of a result, filled or
+// not.
+//
+func allBlockHeaders(result interface{}) []*BlockHeader {
+	switch r := result.(type) {
+	case *GetBlockHeaderByHeightResult:
+		return []*BlockHeader{&r.BlockHeader}
+	case *GetBlockHeaderByHashResult:
+		headers := []*BlockHeader{&r.BlockHeader}
+		for idx := range r.BlockHeaders {
+			headers = append(headers, &r.BlockHeaders[idx])
+		}
+
+		return headers
+	case *GetBlockResult:
+		return []*BlockHeader{&r.BlockHeader}
+	case *GetLastBlockHeaderResult:
+		return []*BlockHeader{&r.BlockHeader}
+	case *GetBlockHeadersRangeResult:
+		headers := []*BlockHeader{}
+		for idx := range r.Headers {
+			headers = append(headers, &r.Headers[idx])
+		}
+
+		return headers
+	default:
+		return nil
+	}
+}
+
+// refreshDepth updates the depth of the block headers of a cached result
+// according to what's known about the tip of the chain now.
+//
+func refreshDepth(result interface{}, top uint64) {
+	for _, header := range blockHeaders(result) {
+		if top > header.Height {
+			header.Depth = top - header.Height
+		}
+	}
+}
+
+// cacheKey gives the key under which results of calls to `method` with
+// `params` are cached.
+//
+func cacheKey(method string, params interface{}) (string, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	return method + " " + string(b), nil
+}
+
+// requestedTxs gives the hashes of the transactions that `/get_transactions`
+// has been called with.
+//
+func requestedTxs(params interface{}) []string {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+
+	p := &struct {
+		TxsHashes []string `json:"txs_hashes"`
+	}{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil
+	}
+
+	return p.TxsHashes
+}
@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CacheStore is where a Cache keeps its entries.
+//
+type CacheStore interface {
+	// Get retrieves the value stored under `key`, if any.
+	//
+	Get(key string) ([]byte, bool, error)
+
+	// Put stores `value` under `key`, replacing any previous value.
+	//
+	Put(key string, value []byte) error
+
+	// Delete removes the value stored under `key`, if any.
+	//
+	Delete(key string) error
+
+	// Range calls `fn` for every entry in the store until it returns
+	// false.
+	//
+	Range(fn func(key string, value []byte) bool) error
+}
+
+// LRUCacheStore is an in-memory CacheStore holding up to a maximum number of
+// entries, evicting the least recently used ones to make room for new ones.
+//
+type LRUCacheStore struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+var _ CacheStore = (*LRUCacheStore)(nil)
+
+// NewLRUCacheStore instantiates a new LRUCacheStore holding at most
+// `capacity` entries.
+//
+func NewLRUCacheStore(capacity int) *LRUCacheStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &LRUCacheStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+// Get implements CacheStore, marking the entry as the most recently used.
+//
+func (s *LRUCacheStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, found := s.entries[key]
+	if !found {
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(elem)
+
+	// nolint:forcetypeassert
+	return elem.Value.(*lruEntry).value, true, nil
+}
+
+// Put implements CacheStore, evicting the least recently used entry if the
+// store is full.
+//
+func (s *LRUCacheStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, found := s.entries[key]; found {
+		// nolint:forcetypeassert
+		elem.Value.(*lruEntry).value = value
+		s.order.MoveToFront(elem)
+
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&lruEntry{key: key, value: value})
+
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+
+		// nolint:forcetypeassert
+		delete(s.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return nil
+}
+
+// Delete implements CacheStore.
+//
+func (s *LRUCacheStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, found := s.entries[key]; found {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+	}
+
+	return nil
+}
+
+// Range implements CacheStore, going from the most to the least recently used
+// entry.
+//
+func (s *LRUCacheStore) Range(fn func(key string, value []byte) bool) error {
+	s.mu.Lock()
+	entries := make([]lruEntry, 0, s.order.Len())
+	for elem := s.order.Front(); elem != nil; elem = elem.Next() {
+		// nolint:forcetypeassert
+		entries = append(entries, *elem.Value.(*lruEntry))
+	}
+	s.mu.Unlock()
+
+	for _, entry := range entries {
+		if !fn(entry.key, entry.value) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// DiskCacheStore is a CacheStore that keeps each entry in a file under a
+// directory, so that entries survive across processes.
+//
+type DiskCacheStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ CacheStore = (*DiskCacheStore)(nil)
+
+// NewDiskCacheStore instantiates a new DiskCacheStore keeping its entries
+// under `dir`, creating it if needed.
+//
+func NewDiskCacheStore(dir string) (*DiskCacheStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("mkdir all '%s': %w", dir, err)
+	}
+
+	return &DiskCacheStore{dir: dir}, nil
+}
+
+// path gives the path to the file holding the entry for `key`.
+//
+// ps.: keys are hashed as they might be too long (or have characters not
+// fit) for a file name.
+//
+func (s *DiskCacheStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements CacheStore.
+//
+func (s *DiskCacheStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("read file: %w", err)
+	}
+
+	storedKey, value, err := splitDiskEntry(b)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if storedKey != key {
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
+
+// Put implements CacheStore.
+//
+func (s *DiskCacheStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := make([]byte, 0, len(key)+1+len(value))
+	b = append(b, key...)
+	b = append(b, '\n')
+	b = append(b, value...)
+
+	// write to a temporary file first so that readers never see partially
+	// written entries.
+	//
+	tmp := s.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path(key)); err != nil {
+		return fmt.Errorf("rename: %w", err)
+	}
+
+	return nil
+}
+
+// Delete implements CacheStore.
+//
+func (s *DiskCacheStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove: %w", err)
+	}
+
+	return nil
+}
+
+// Range implements CacheStore.
+//
+func (s *DiskCacheStore) Range(fn func(key string, value []byte) bool) error {
+	s.mu.Lock()
+	files, err := os.ReadDir(s.dir)
+	s.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != "" {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		key, value, err := splitDiskEntry(b)
+		if err != nil {
+			return err
+		}
+
+		if !fn(key, value) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// splitDiskEntry splits the contents of an entry's file into its key (first
+// line) and value (the rest).
+//
+func splitDiskEntry(b []byte) (string, []byte, error) {
+	idx := bytes.IndexByte(b, '\n')
+	if idx < 0 {
+		return "", nil, fmt.Errorf("malformed cache entry")
+	}
+
+	return string(b[:idx]), b[idx+1:], nil
+}
@@ -0,0 +1,250 @@
+package daemon_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestCache(t *testing.T) {
+	spec.Run(t, "Cache", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			cache  *daemon.Cache
+			client *daemon.Client
+		)
+
+		newCachedClient := func(opts ...daemon.CacheOption) (*daemon.Cache, *daemon.Client) {
+			c, err := server.NewClient()
+			require.NoError(t, err)
+
+			cache := daemon.NewCache(c.Requester,
+				append([]daemon.CacheOption{daemon.WithConfirmations(5)}, opts...)...,
+			)
+
+			return cache, daemon.NewClient(cache)
+		}
+
+		it.Before(func() {
+			server = daemontest.NewServer(daemontest.WithBlocks(20))
+			cache, client = newCachedClient()
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		when("block headers", func() {
+			it("serves deep ones from the cache", func() {
+				for i := 0; i < 3; i++ {
+					resp, err := client.GetBlockHeaderByHeight(ctx, 10)
+					require.NoError(t, err)
+					assert.EqualValues(t, 10, resp.BlockHeader.Height)
+				}
+
+				assert.EqualValues(t, 1, server.Calls("get_block_header_by_height"))
+				assert.Equal(t, daemon.CacheStats{Hits: 2, Misses: 1, Stores: 1}, cache.Stats())
+			})
+
+			it("doesn't cache those too close to the tip", func() {
+				for i := 0; i < 2; i++ {
+					_, err := client.GetBlockHeaderByHeight(ctx, 17)
+					require.NoError(t, err)
+				}
+
+				assert.EqualValues(t, 2, server.Calls("get_block_header_by_height"))
+				assert.Zero(t, cache.Stats().Stores)
+			})
+
+			it("keeps the depth up to date", func() {
+				_, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+
+				server.GenerateBlocks(3)
+
+				_, err = client.GetLastBlockHeader(ctx)
+				require.NoError(t, err)
+
+				resp, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+				assert.EqualValues(t, 12, resp.BlockHeader.Depth)
+				assert.EqualValues(t, 1, server.Calls("get_block_header_by_height"))
+			})
+
+			it("serves batches partially from the cache", func() {
+				_, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+
+				resps, err := client.BatchGetBlockHeaderByHeight(ctx, []uint64{10, 11})
+				require.NoError(t, err)
+				require.Len(t, resps, 2)
+				require.NoError(t, resps[0].Error)
+				require.NoError(t, resps[1].Error)
+				assert.EqualValues(t, 10, resps[0].Result.BlockHeader.Height)
+				assert.EqualValues(t, 11, resps[1].Result.BlockHeader.Height)
+
+				assert.EqualValues(t, 2, server.Calls("get_block_header_by_height"))
+				assert.EqualValues(t, 1, cache.Stats().Hits)
+			})
+		})
+
+		when("transactions", func() {
+			it("only caches those mined deep enough", func() {
+				server.AddTx(daemontest.Tx{Hash: "aa", Blob: "00"})
+
+				_, err := client.GetTransactions(ctx, []string{"aa"})
+				require.NoError(t, err)
+
+				server.GenerateBlocks(6)
+
+				for i := 0; i < 2; i++ {
+					resp, err := client.GetTransactions(ctx, []string{"aa"})
+					require.NoError(t, err)
+					require.Len(t, resp.Txs, 1)
+					assert.EqualValues(t, 20, resp.Txs[0].BlockHeight)
+				}
+
+				assert.EqualValues(t, 2, server.Calls("/get_transactions"))
+			})
+
+			it("doesn't cache replies missing some", func() {
+				server.AddTx(daemontest.Tx{Hash: "aa", Blob: "00"})
+				server.GenerateBlocks(6)
+
+				resp, err := client.GetTransactions(ctx, []string{"aa", "bb"})
+				require.NoError(t, err)
+				require.Len(t, resp.Txs, 1)
+				assert.Equal(t, []string{"bb"}, resp.MissedTx)
+
+				server.AddTx(daemontest.Tx{Hash: "bb", Blob: "01"})
+				server.GenerateBlocks(1)
+
+				resp, err = client.GetTransactions(ctx, []string{"aa", "bb"})
+				require.NoError(t, err)
+				assert.Len(t, resp.Txs, 2)
+				assert.Zero(t, cache.Stats().Hits)
+			})
+
+			it("drops them on reorgs below where they were mined", func() {
+				server.AddTx(daemontest.Tx{Hash: "aa", Blob: "00"})
+				server.GenerateBlocks(6)
+
+				_, err := client.GetTransactions(ctx, []string{"aa"})
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, cache.Stats().Stores)
+
+				require.NoError(t, cache.Invalidate(20))
+
+				_, err = client.GetTransactions(ctx, []string{"aa"})
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, server.Calls("/get_transactions"))
+			})
+		})
+
+		when("reorgs", func() {
+			it("drops entries addressed by height", func() {
+				_, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+
+				blockByHeight, err := client.GetBlock(ctx, daemon.GetBlockRequestParameters{Height: 12})
+				require.NoError(t, err)
+
+				_, err = client.GetBlock(ctx, daemon.GetBlockRequestParameters{
+					Hash: blockByHeight.BlockHeader.Hash,
+				})
+				require.NoError(t, err)
+
+				server.Reorg(9, 10)
+
+				// seeing the new block at height 12 gives the reorg
+				// away.
+				//
+				resp, err := client.GetBlockHeadersRange(ctx, 12, 12)
+				require.NoError(t, err)
+				require.Len(t, resp.Headers, 1)
+
+				assert.EqualValues(t, 1, cache.Stats().Invalidations)
+
+				header, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, server.Calls("get_block_header_by_height"))
+
+				newBlock, err := client.GetBlock(ctx, daemon.GetBlockRequestParameters{Height: 12})
+				require.NoError(t, err)
+				assert.Equal(t, resp.Headers[0].Hash, newBlock.BlockHeader.Hash)
+				assert.NotEqual(t, blockByHeight.BlockHeader.Hash, newBlock.BlockHeader.Hash)
+				assert.NotEmpty(t, header.BlockHeader.Hash)
+			})
+
+			it("drops entries when explicitly invalidated", func() {
+				_, err := client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+
+				require.NoError(t, cache.Invalidate(10))
+
+				_, err = client.GetBlockHeaderByHeight(ctx, 10)
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, server.Calls("get_block_header_by_height"))
+			})
+		})
+
+		when("using the disk store", func() {
+			it("reuses entries across caches", func() {
+				dir := t.TempDir()
+
+				store, err := daemon.NewDiskCacheStore(dir)
+				require.NoError(t, err)
+
+				_, client := newCachedClient(daemon.WithCacheStore(store))
+				_, err = client.GetBlockHeaderByHeight(ctx, 3)
+				require.NoError(t, err)
+
+				store, err = daemon.NewDiskCacheStore(dir)
+				require.NoError(t, err)
+
+				cache, client := newCachedClient(daemon.WithCacheStore(store))
+				resp, err := client.GetBlockHeaderByHeight(ctx, 3)
+				require.NoError(t, err)
+				assert.EqualValues(t, 3, resp.BlockHeader.Height)
+
+				assert.EqualValues(t, 1, cache.Stats().Hits)
+				assert.EqualValues(t, 1, server.Calls("get_block_header_by_height"))
+			})
+		})
+
+		when("using the lru store", func() {
+			it("evicts the least recently used entries", func() {
+				store := daemon.NewLRUCacheStore(2)
+
+				require.NoError(t, store.Put("a", []byte("1")))
+				require.NoError(t, store.Put("b", []byte("2")))
+
+				_, found, err := store.Get("a")
+				require.NoError(t, err)
+				assert.True(t, found)
+
+				require.NoError(t, store.Put("c", []byte("3")))
+
+				_, found, err = store.Get("b")
+				require.NoError(t, err)
+				assert.False(t, found)
+
+				keys := []string{}
+				require.NoError(t, store.Range(func(key string, _ []byte) bool {
+					keys = append(keys, key)
+					return true
+				}))
+				assert.Equal(t, []string{"c", "a"}, keys)
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -1,6 +1,10 @@
 package daemon
 
-import "context"
+import (
+	"context"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
 
 // Requester is responsible for making concrete request to Monero's endpoints,
 // i.e., either `jsonrpc` methods or those "raw" endpoints.
@@ -25,18 +29,64 @@ type Requester interface {
 	) error
 }
 
+// BatchRequester is implemented by Requesters that are able to submit several
+// `/json_rpc` calls in a single request (e.g., `rpc.Client`).
+//
+type BatchRequester interface {
+	// JSONRPCBatch submits all `calls` at once, filling each call's
+	// result and error.
+	//
+	JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error
+}
+
+// BinaryRequester is implemented by Requesters that are able to make requests
+// to the binary (`.bin`) endpoints (e.g., `rpc.Client`).
+//
+type BinaryRequester interface {
+	// BinaryRequest submits the portable storage-encoded `body` to
+	// `endpoint`, returning the raw response body.
+	//
+	BinaryRequest(ctx context.Context, endpoint string, body []byte) ([]byte, error)
+}
+
 // Client provides access to the daemon's JSONRPC methods and regular
 // endpoints.
 //
 type Client struct {
 	Requester
+
+	// verify tells whether transactions and blocks should have their
+	// hashes checked.
+	//
+	verify bool
+}
+
+// ClientOption configures a Client.
+//
+type ClientOption func(c *Client)
+
+// WithVerification makes the client recompute the hashes of the
+// transactions and blocks it retrieves (through GetTransactions and
+// GetBlock) from their blobs, failing with `ErrHashMismatch` if they don't
+// match what the daemon claims they are.
+//
+func WithVerification() ClientOption {
+	return func(c *Client) {
+		c.verify = true
+	}
 }
 
 // NewClient instantiates a new client for interacting with monero's daemon
 // api.
 //
-func NewClient(c Requester) *Client {
-	return &Client{
+func NewClient(c Requester, opts ...ClientOption) *Client {
+	client := &Client{
 		Requester: c,
 	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
 }
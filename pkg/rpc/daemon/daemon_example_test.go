@@ -9,7 +9,7 @@ import (
 )
 
 // nolint
-func ExampleGetHeight() {
+func ExampleClient_GetHeight() {
 	ctx := context.Background()
 	addr := "http://localhost:18081"
 
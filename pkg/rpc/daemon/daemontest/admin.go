@@ -0,0 +1,227 @@
+package daemontest
+
+import (
+	"encoding/binary"
+	"path/filepath"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+const (
+	// pruningSeed is the seed that the fake node prunes its chain with.
+	//
+	pruningSeed = 0x181
+
+	// updateVersion is the version that the fake node reports as
+	// available for updating to.
+	//
+	updateVersion = "0.18.3.1"
+)
+
+// jsonrpc
+
+func flushTxpool(s *state, params []byte) (interface{}, error) {
+	req := &daemon.FlushTxpoolRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	flush := map[string]bool{}
+	for _, txid := range req.TxIDs {
+		flush[txid] = true
+	}
+
+	pool := []string{}
+	for _, hash := range s.pool {
+		if len(flush) != 0 && !flush[hash] {
+			pool = append(pool, hash)
+			continue
+		}
+
+		delete(s.txs, hash)
+	}
+
+	s.pool = pool
+
+	return &daemon.FlushTxpoolResult{RPCResultFooter: ok}, nil
+}
+
+func flushCache(s *state, params []byte) (interface{}, error) {
+	return &daemon.FlushCacheResult{RPCResultFooter: ok}, nil
+}
+
+func pruneBlockchain(s *state, params []byte) (interface{}, error) {
+	req := &daemon.PruneBlockchainRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if !req.Check {
+		s.pruningSeed = pruningSeed
+	}
+
+	return &daemon.PruneBlockchainResult{
+		Pruned:          s.pruningSeed != 0,
+		PruningSeed:     s.pruningSeed,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getTxpoolBacklog(s *state, params []byte) (interface{}, error) {
+	blob := make([]byte, 0, 24*len(s.pool))
+
+	for _, hash := range s.pool {
+		tx := s.txs[hash]
+
+		var inPool uint64
+		if tx.ReceiveTime != 0 {
+			inPool = uint64(time.Since(time.Unix(tx.ReceiveTime, 0)).Seconds())
+		}
+
+		entry := make([]byte, 24)
+		binary.LittleEndian.PutUint64(entry, tx.Weight)
+		binary.LittleEndian.PutUint64(entry[8:], tx.Fee)
+		binary.LittleEndian.PutUint64(entry[16:], inPool)
+
+		blob = append(blob, entry...)
+	}
+
+	return map[string]interface{}{
+		"backlog": epeeBlob(blob),
+		"status":  rpc.StatusOK,
+	}, nil
+}
+
+func banned(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		Address string `json:"address"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	resp := &daemon.BannedResult{RPCResultFooter: ok}
+
+	if until, found := s.bans[req.Address]; found {
+		if left := time.Until(until); left > 0 {
+			resp.Banned = true
+			resp.Seconds = uint32(left.Seconds())
+		}
+	}
+
+	return resp, nil
+}
+
+func setBootstrapDaemon(s *state, params []byte) (interface{}, error) {
+	req := &daemon.SetBootstrapDaemonRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	s.bootstrapDaemon = req.Address
+
+	return &daemon.SetBootstrapDaemonResult{RPCResultFooter: ok}, nil
+}
+
+// raw
+
+func popBlocks(s *state, body []byte) (interface{}, error) {
+	req := &daemon.PopBlocksRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	s.popBlocks(req.NBlocks)
+
+	return &daemon.PopBlocksResult{
+		Height:          s.height(),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func saveBc(s *state, body []byte) (interface{}, error) {
+	return &daemon.SaveBcResult{RPCResultFooter: ok}, nil
+}
+
+func inPeers(s *state, body []byte) (interface{}, error) {
+	req := &daemon.InPeersRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	if req.Set {
+		s.inPeers = req.InPeers
+	}
+
+	return &daemon.InPeersResult{
+		InPeers:         s.inPeers,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func outPeers(s *state, body []byte) (interface{}, error) {
+	req := &daemon.OutPeersRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	if req.Set {
+		s.outPeers = req.OutPeers
+	}
+
+	return &daemon.OutPeersResult{
+		OutPeers:        s.outPeers,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func update(s *state, body []byte) (interface{}, error) {
+	req := &daemon.UpdateRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	filename := "monero-linux-x64-v" + updateVersion + ".tar.bz2"
+	resp := &daemon.UpdateResult{
+		Update:          true,
+		Version:         updateVersion,
+		UserURI:         "https://downloads.getmonero.org/cli/" + filename,
+		AutoURI:         "https://updates.getmonero.org/cli/" + filename,
+		Hash:            fakeHash("update", 0, updateVersion, 0),
+		RPCResultFooter: ok,
+	}
+
+	switch req.Command {
+	case daemon.UpdateCheck:
+	case daemon.UpdateDownload:
+		resp.Path = req.Path
+		if resp.Path == "" {
+			resp.Path = filepath.Join("/var/lib/monero", filename)
+		}
+	default:
+		return nil, failed("unknown command")
+	}
+
+	return resp, nil
+}
+
+func getAltBlocksHashes(s *state, body []byte) (interface{}, error) {
+	hashes := []string{}
+	for _, block := range s.alt {
+		hashes = append(hashes, block.Hash)
+	}
+
+	return &daemon.GetAltBlocksHashesResult{
+		BlksHashes:      hashes,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getTransactionPoolHashes(s *state, body []byte) (interface{}, error) {
+	return &daemon.GetTransactionPoolHashesResult{
+		TxHashes:        append([]string{}, s.pool...),
+		RPCResultFooter: ok,
+	}, nil
+}
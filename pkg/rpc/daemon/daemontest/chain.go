@@ -0,0 +1,417 @@
+package daemontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+const (
+	// BlockReward is the reward given to the miner of every block in the
+	// fake chain.
+	//
+	BlockReward uint64 = 600000000000
+
+	// Difficulty is the difficulty of every block in the fake chain.
+	//
+	Difficulty uint64 = 1000
+
+	blockTime    = 120
+	majorVersion = 16
+
+	// maxTxSize is the size over which transactions are refused for being
+	// too big.
+	//
+	maxTxSize = 149400
+)
+
+// Block is a block in the fake chain.
+//
+type Block struct {
+	Hash        string
+	PrevHash    string
+	Height      uint64
+	Timestamp   int64
+	Nonce       uint64
+	MinerTxHash string
+	TxHashes    []string
+}
+
+// Tx is a transaction either in the fake chain or in its transaction pool.
+//
+type Tx struct {
+	// Hash is the hash of the transaction.
+	//
+	Hash string
+
+	// Blob is the hex-encoded binary form of the transaction.
+	//
+	Blob string
+
+	// JSON is the JSON representation of the transaction.
+	//
+	JSON string
+
+	Fee         uint64
+	Weight      uint64
+	ReceiveTime int64
+	Relayed     bool
+
+	// InPool indicates whether the transaction is still in the pool, or
+	// has been mined at `BlockHeight`.
+	//
+	InPool      bool
+	BlockHeight uint64
+
+	// KeyImages are the (hex-encoded) key images of the inputs of the
+	// transaction, reported as spent by `/is_key_image_spent`.
+	//
+	KeyImages []string
+}
+
+// state is the in-memory state of the fake node. It's not safe for
+// concurrent use: the server serializes access to it.
+//
+type state struct {
+	nettype string
+
+	blocks []*Block
+	txs    map[string]*Tx
+	pool   []string
+
+	whitePeers []daemon.Peer
+	grayPeers  []daemon.Peer
+	bans       map[string]time.Time
+
+	limitUp, limitDown uint64
+	logLevel           int8
+	logCategories      string
+
+	mining        bool
+	minerAddress  string
+	miningThreads uint
+
+	startTime time.Time
+	bytesIn   uint64
+
+	// txValidator decides whether transactions submitted through
+	// `/send_raw_transaction` are accepted.
+	//
+	txValidator TxValidator
+
+	// payments is the state of rpc payments, nil unless enabled.
+	//
+	payments *payments
+
+	// forks counts the reorgs so far, making sure that blocks mined
+	// after one differ from those they replace.
+	//
+	forks uint64
+
+	// alt are the blocks dropped from the main chain by reorgs.
+	//
+	alt []*Block
+
+	inPeers, outPeers uint32
+	pruningSeed       uint32
+	bootstrapDaemon   string
+}
+
+func newState(nettype string) *state {
+	s := &state{
+		nettype:   nettype,
+		txs:       map[string]*Tx{},
+		bans:      map[string]time.Time{},
+		limitUp:   2048,
+		limitDown: 8192,
+		inPeers:   math.MaxUint32,
+		outPeers:  12,
+		startTime: time.Now(),
+	}
+
+	s.blocks = []*Block{newBlock(0, "", 0, 0)}
+
+	return s
+}
+
+func newBlock(height uint64, prevHash string, timestamp int64, nonce uint64) *Block {
+	return &Block{
+		Hash:        fakeHash("block", height, prevHash, nonce),
+		PrevHash:    prevHash,
+		Height:      height,
+		Timestamp:   timestamp,
+		Nonce:       nonce,
+		MinerTxHash: fakeHash("miner_tx", height, prevHash, nonce),
+		TxHashes:    []string{},
+	}
+}
+
+func (s *state) height() uint64 {
+	return uint64(len(s.blocks))
+}
+
+func (s *state) top() *Block {
+	return s.blocks[len(s.blocks)-1]
+}
+
+func (s *state) blockByHash(hash string) (*Block, bool) {
+	for _, block := range s.blocks {
+		if block.Hash == hash {
+			return block, true
+		}
+	}
+
+	return nil, false
+}
+
+// generateBlocks mines `n` blocks on top of the chain, the first of which
+// includes all of the transactions in the pool.
+//
+func (s *state) generateBlocks(n uint64) []string {
+	hashes := make([]string, 0, n)
+
+	for i := uint64(0); i < n; i++ {
+		top := s.top()
+
+		// (32-bit) nonces differ from those of the blocks replaced by
+		// reorgs, making their hashes differ too.
+		//
+		nonce := uint32(top.Nonce) + 1 + uint32(s.forks)<<20
+
+		block := newBlock(top.Height+1, top.Hash,
+			top.Timestamp+blockTime, uint64(nonce))
+
+		block.TxHashes = append(block.TxHashes, s.pool...)
+		for _, hash := range s.pool {
+			s.txs[hash].InPool = false
+			s.txs[hash].BlockHeight = block.Height
+		}
+		s.pool = nil
+
+		s.blocks = append(s.blocks, block)
+		hashes = append(hashes, block.Hash)
+	}
+
+	return hashes
+}
+
+// reorg replaces the top `depth` blocks (never the genesis one) with `n` new
+// ones, sending the transactions of those dropped back to the pool.
+//
+func (s *state) reorg(depth, n uint64) []string {
+	s.alt = append(s.alt, s.popBlocks(depth)...)
+	s.forks++
+
+	return s.generateBlocks(n)
+}
+
+// popBlocks removes the top `n` blocks (never the genesis one), sending
+// their transactions back to the pool.
+//
+func (s *state) popBlocks(n uint64) []*Block {
+	if n >= s.height() {
+		n = s.height() - 1
+	}
+
+	dropped := append([]*Block{}, s.blocks[s.height()-n:]...)
+	s.blocks = s.blocks[:s.height()-n]
+
+	for _, block := range dropped {
+		for _, hash := range block.TxHashes {
+			s.txs[hash].InPool = true
+			s.txs[hash].BlockHeight = 0
+			s.pool = append(s.pool, hash)
+		}
+	}
+
+	return dropped
+}
+
+// alternateChains groups the blocks dropped by reorgs into the chains they
+// formed, each one going from the top block down to the first diverging one.
+//
+func (s *state) alternateChains() []daemon.AlternateChain {
+	parents := map[string]bool{}
+	byHash := map[string]*Block{}
+
+	for _, block := range s.alt {
+		parents[block.PrevHash] = true
+		byHash[block.Hash] = block
+	}
+
+	chains := []daemon.AlternateChain{}
+
+	for _, top := range s.alt {
+		if parents[top.Hash] {
+			continue
+		}
+
+		chain := daemon.AlternateChain{
+			BlockHash:      top.Hash,
+			Difficulty:     int64(Difficulty * (top.Height + 1)),
+			Height:         top.Height,
+			WideDifficulty: fmt.Sprintf("0x%x", Difficulty*(top.Height+1)),
+		}
+
+		for block := top; block != nil; block = byHash[block.PrevHash] {
+			chain.BlockHashes = append(chain.BlockHashes, block.Hash)
+			chain.MainChainParentBlock = block.PrevHash
+		}
+
+		chain.Length = uint64(len(chain.BlockHashes))
+		chains = append(chains, chain)
+	}
+
+	return chains
+}
+
+// template gives the (hex-encoded) block template for mining on top of
+// `prev`, up to the area reserved for extra nonces.
+//
+func (s *state) template(prev *Block) string {
+	return fakeHash("template", prev.Height+1, prev.Hash, 0)
+}
+
+// keyImageStatus gives whether `keyImage` has been spent by a transaction in
+// the chain or in the pool.
+//
+func (s *state) keyImageStatus(keyImage string) daemon.KeyImageSpentStatus {
+	for _, tx := range s.txs {
+		for _, ki := range tx.KeyImages {
+			if ki != keyImage {
+				continue
+			}
+
+			if tx.InPool {
+				return daemon.KeyImageSpentInPool
+			}
+
+			return daemon.KeyImageSpentInChain
+		}
+	}
+
+	return daemon.KeyImageUnspent
+}
+
+func (s *state) header(block *Block) daemon.BlockHeader {
+	var fees uint64
+	for _, hash := range block.TxHashes {
+		fees += s.txs[hash].Fee
+	}
+
+	return daemon.BlockHeader{
+		BlockSize:            uint64(100 + 1000*len(block.TxHashes)),
+		BlockWeight:          uint64(100 + 1000*len(block.TxHashes)),
+		CumulativeDifficulty: Difficulty * (block.Height + 1),
+		Depth:                s.height() - block.Height - 1,
+		Difficulty:           Difficulty,
+		Hash:                 block.Hash,
+		Height:               block.Height,
+		LongTermWeight:       uint64(100 + 1000*len(block.TxHashes)),
+		MajorVersion:         majorVersion,
+		MinerTxHash:          block.MinerTxHash,
+		MinorVersion:         majorVersion,
+		Nonce:                block.Nonce,
+		NumTxes:              uint(len(block.TxHashes)),
+		PowHash:              "",
+		PrevHash:             block.PrevHash,
+		Reward:               BlockReward + fees,
+		Timestamp:            block.Timestamp,
+		WideCumulativeDifficulty: fmt.Sprintf("0x%x",
+			Difficulty*(block.Height+1)),
+		WideDifficulty: fmt.Sprintf("0x%x", Difficulty),
+	}
+}
+
+// Height gives the number of blocks in the chain.
+//
+func (s *Server) Height() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.height()
+}
+
+// Block retrieves a copy of the block at `height`.
+//
+func (s *Server) Block(height uint64) (Block, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if height >= s.state.height() {
+		return Block{}, false
+	}
+
+	return *s.state.blocks[height], true
+}
+
+// GenerateBlocks advances the chain by `n` blocks (just like the
+// `generateblocks` method), returning their hashes.
+//
+func (s *Server) GenerateBlocks(n uint64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.generateBlocks(n)
+}
+
+// Reorg simulates a reorganization of the chain, replacing its top `depth`
+// blocks with `n` new ones (with transactions from the blocks replaced being
+// mined again in the first of them), returning the hashes of the new blocks.
+//
+func (s *Server) Reorg(depth, n uint64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state.reorg(depth, n)
+}
+
+// AddTx adds a transaction to the pool, to be mined in the next block
+// generated.
+//
+func (s *Server) AddTx(tx Tx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tx.ReceiveTime == 0 {
+		tx.ReceiveTime = time.Now().Unix()
+	}
+
+	tx.InPool = true
+	s.state.txs[tx.Hash] = &tx
+	s.state.pool = append(s.state.pool, tx.Hash)
+}
+
+// AddPeer adds a peer to either the white or the gray peer list.
+//
+func (s *Server) AddPeer(peer daemon.Peer, white bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if white {
+		s.state.whitePeers = append(s.state.whitePeers, peer)
+		return
+	}
+
+	s.state.grayPeers = append(s.state.grayPeers, peer)
+}
+
+// Ban bans `host` for the given duration (just like the `set_bans` method).
+//
+func (s *Server) Ban(host string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.bans[host] = time.Now().Add(d)
+}
+
+func fakeHash(kind string, height uint64, prevHash string, nonce uint64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%s:%d",
+		kind, height, prevHash, nonce)))
+
+	return hex.EncodeToString(sum[:])
+}
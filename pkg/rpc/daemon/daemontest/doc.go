@@ -0,0 +1,5 @@
+// Package daemontest provides an in-memory fake of monerod (see `Server`) for
+// testing code that makes use of `daemon.Client`, including the command line
+// interface (by pointing `--address` at the server's URL).
+//
+package daemontest
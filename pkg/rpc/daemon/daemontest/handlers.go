@@ -0,0 +1,1125 @@
+package daemontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+type (
+	jsonrpcHandler func(s *state, params []byte) (interface{}, error)
+	rawHandler     func(s *state, body []byte) (interface{}, error)
+)
+
+var ok = daemon.RPCResultFooter{Status: rpc.StatusOK}
+
+var jsonrpcHandlers = map[string]jsonrpcHandler{
+	"add_aux_pow":                addAuxPow,
+	"banned":                     banned,
+	"calc_pow":                   calcPow,
+	"flush_cache":                flushCache,
+	"flush_txpool":               flushTxpool,
+	"generateblocks":             generateBlocks,
+	"get_alternate_chains":       getAlternateChains,
+	"get_bans":                   getBans,
+	"get_block":                  getBlock,
+	"get_block_count":            getBlockCount,
+	"get_block_header_by_hash":   getBlockHeaderByHash,
+	"get_block_header_by_height": getBlockHeaderByHeight,
+	"get_block_headers_range":    getBlockHeadersRange,
+	"get_block_template":         getBlockTemplate,
+	"get_coinbase_tx_sum":        getCoinbaseTxSum,
+	"get_connections":            getConnections,
+	"get_fee_estimate":           getFeeEstimate,
+	"get_info":                   getInfo,
+	"get_last_block_header":      getLastBlockHeader,
+	"get_miner_data":             getMinerData,
+	"get_output_distribution":    getOutputDistribution,
+	"get_output_histogram":       getOutputHistogram,
+	"get_txpool_backlog":         getTxpoolBacklog,
+	"get_version":                getVersion,
+	"hard_fork_info":             hardForkInfo,
+	"on_get_block_hash":          onGetBlockHash,
+	"prune_blockchain":           pruneBlockchain,
+	"relay_tx":                   relayTx,
+	"rpc_access_account":         rpcAccessAccount,
+	"rpc_access_data":            rpcAccessData,
+	"rpc_access_info":            rpcAccessInfo,
+	"rpc_access_pay":             rpcAccessPay,
+	"rpc_access_submit_nonce":    rpcAccessSubmitNonce,
+	"set_bans":                   setBans,
+	"set_bootstrap_daemon":       setBootstrapDaemon,
+	"submit_block":               submitBlock,
+	"sync_info":                  syncInfo,
+}
+
+var rawHandlers = map[string]rawHandler{
+	"/get_alt_blocks_hashes":       getAltBlocksHashes,
+	"/get_height":                  getHeight,
+	"/get_limit":                   getLimit,
+	"/get_net_stats":               getNetStats,
+	"/get_outs":                    getOuts,
+	"/get_peer_list":               getPeerList,
+	"/get_public_nodes":            getPublicNodes,
+	"/get_transaction_pool":        getTransactionPool,
+	"/get_transaction_pool_hashes": getTransactionPoolHashes,
+	"/get_transaction_pool_stats":  getTransactionPoolStats,
+	"/get_transactions":            getTransactions,
+	"/in_peers":                    inPeers,
+	"/is_key_image_spent":          isKeyImageSpent,
+	"/mining_status":               miningStatus,
+	"/out_peers":                   outPeers,
+	"/pop_blocks":                  popBlocks,
+	"/save_bc":                     saveBc,
+	"/send_raw_transaction":        sendRawTransaction,
+	"/set_limit":                   setLimit,
+	"/set_log_categories":          setLogCategories,
+	"/set_log_level":               setLogLevel,
+	"/start_mining":                startMining,
+	"/stop_mining":                 stopMining,
+	"/update":                      update,
+}
+
+// failed reports a failure the way raw endpoints do: through their status.
+//
+func failed(status string) error {
+	return &rpc.StatusError{Status: status}
+}
+
+func decode(b []byte, v interface{}) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return &rpc.Error{Code: rpc.CodeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+// jsonrpc
+
+func generateBlocks(s *state, params []byte) (interface{}, error) {
+	req := &daemon.GenerateBlocksRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if req.PreviousBlock != "" && req.PreviousBlock != s.top().Hash {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Block not found",
+		}
+	}
+
+	return &daemon.GenerateBlocksResult{
+		Blocks:          s.generateBlocks(req.AmountOfBlocks),
+		Height:          int(s.height()),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getAlternateChains(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetAlternateChainsResult{
+		Chains:          s.alternateChains(),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getBans(s *state, params []byte) (interface{}, error) {
+	type ban struct {
+		Host    string `json:"host"`
+		IP      int    `json:"ip"`
+		Seconds uint   `json:"seconds"`
+	}
+
+	bans := []ban{}
+	for host, until := range s.bans {
+		left := time.Until(until)
+		if left <= 0 {
+			delete(s.bans, host)
+			continue
+		}
+
+		bans = append(bans, ban{Host: host, Seconds: uint(left.Seconds())})
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Host < bans[j].Host
+	})
+
+	return map[string]interface{}{
+		"bans":   bans,
+		"status": rpc.StatusOK,
+	}, nil
+}
+
+func setBans(s *state, params []byte) (interface{}, error) {
+	req := &daemon.SetBansRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	for _, ban := range req.Bans {
+		if !ban.Ban {
+			delete(s.bans, ban.Host)
+			continue
+		}
+
+		s.bans[ban.Host] = time.Now().Add(time.Duration(ban.Seconds) * time.Second)
+	}
+
+	return &daemon.SetBansResult{RPCResultFooter: ok}, nil
+}
+
+func getBlock(s *state, params []byte) (interface{}, error) {
+	req := &daemon.GetBlockRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	block, err := s.lookup(req.Height, req.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	blockJSON, err := json.Marshal(map[string]interface{}{
+		"major_version": majorVersion,
+		"minor_version": majorVersion,
+		"timestamp":     block.Timestamp,
+		"prev_id":       block.PrevHash,
+		"nonce":         block.Nonce,
+		"miner_tx": map[string]interface{}{
+			"version":     2,
+			"unlock_time": block.Height + 60,
+			"vin": []interface{}{
+				map[string]interface{}{
+					"gen": map[string]interface{}{"height": block.Height},
+				},
+			},
+			"vout": []interface{}{
+				map[string]interface{}{
+					"amount": s.header(block).Reward,
+					"target": map[string]interface{}{
+						"key": fakeHash("output", block.Height, block.Hash, 0),
+					},
+				},
+			},
+			"extra":          []int{},
+			"rct_signatures": map[string]interface{}{"type": 0},
+		},
+		"tx_hashes": block.TxHashes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	return &daemon.GetBlockResult{
+		Blob:            hex.EncodeToString([]byte(block.Hash)),
+		BlockHeader:     s.header(block),
+		JSON:            string(blockJSON),
+		MinerTxHash:     block.MinerTxHash,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getBlockCount(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetBlockCountResult{
+		Count:           s.height(),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getBlockHeaderByHash(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		Hash   string   `json:"hash"`
+		Hashes []string `json:"hashes"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	hashes := req.Hashes
+	if req.Hash != "" {
+		hashes = append([]string{req.Hash}, hashes...)
+	}
+
+	resp := &daemon.GetBlockHeaderByHashResult{
+		BlockHeaders:    []daemon.BlockHeader{},
+		RPCResultFooter: ok,
+	}
+
+	for _, hash := range hashes {
+		block, found := s.blockByHash(hash)
+		if !found {
+			return nil, &rpc.Error{
+				Code:    rpc.CodeInternalError,
+				Message: "Internal error: can't get block by hash. Hash = " + hash + ".",
+			}
+		}
+
+		resp.BlockHeaders = append(resp.BlockHeaders, s.header(block))
+	}
+
+	if len(resp.BlockHeaders) > 0 {
+		resp.BlockHeader = resp.BlockHeaders[0]
+	}
+
+	return resp, nil
+}
+
+func getBlockHeaderByHeight(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		Height uint64 `json:"height"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	block, err := s.lookup(req.Height, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &daemon.GetBlockHeaderByHeightResult{
+		BlockHeader:     s.header(block),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getBlockHeadersRange(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		StartHeight uint64 `json:"start_height"`
+		EndHeight   uint64 `json:"end_height"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if req.StartHeight > req.EndHeight || req.EndHeight >= s.height() {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeTooBigHeight,
+			Message: "Invalid start/end heights.",
+		}
+	}
+
+	resp := &daemon.GetBlockHeadersRangeResult{RPCResultFooter: ok}
+	for height := req.StartHeight; height <= req.EndHeight; height++ {
+		resp.Headers = append(resp.Headers, s.header(s.blocks[height]))
+	}
+
+	return resp, nil
+}
+
+func getBlockTemplate(s *state, params []byte) (interface{}, error) {
+	req := &daemon.GetBlockTemplateRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if req.WalletAddress == "" {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongWalletAddress,
+			Message: "Failed to parse wallet address",
+		}
+	}
+
+	if req.ReserveSize > 255 {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeTooBigReserveSize,
+			Message: "Too big reserved size, maximum 255",
+		}
+	}
+
+	if req.ReserveSize != 0 && req.ExtraNonce != "" {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Cannot specify both a reserve_size and an extra_nonce",
+		}
+	}
+
+	extraNonce, err := hex.DecodeString(req.ExtraNonce)
+	if err != nil {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Parameter extra_nonce should be a hex string",
+		}
+	}
+
+	prev := s.top()
+	if req.PreviousBlock != "" {
+		block, found := s.blockByHash(req.PreviousBlock)
+		if !found {
+			return nil, &rpc.Error{
+				Code:    rpc.CodeInternalError,
+				Message: "Internal error: failed to create block template",
+			}
+		}
+
+		prev = block
+	}
+
+	template := s.template(prev)
+	reserved := make([]byte, req.ReserveSize)
+
+	if len(extraNonce) > 0 {
+		reserved = extraNonce
+	}
+
+	return &daemon.GetBlockTemplateResult{
+		BlockhashingBlob:  template,
+		BlocktemplateBlob: template + hex.EncodeToString(reserved),
+		Difficulty:        int64(Difficulty),
+		WideDifficulty:    fmt.Sprintf("0x%x", Difficulty),
+		ExpectedReward:    int64(BlockReward),
+		Height:            int(prev.Height + 1),
+		PrevHash:          prev.Hash,
+		ReservedOffset:    len(template) / 2,
+		SeedHash:          s.blocks[0].Hash,
+		RPCResultFooter:   ok,
+	}, nil
+}
+
+// submitBlock accepts blocks whose blobs are built from the template for the
+// top of the chain, mining them on top of it.
+//
+func submitBlock(s *state, params []byte) (interface{}, error) {
+	blobs := []string{}
+	if err := decode(params, &blobs); err != nil {
+		return nil, err
+	}
+
+	if len(blobs) != 1 {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Wrong param",
+		}
+	}
+
+	if _, err := hex.DecodeString(blobs[0]); err != nil {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongBlockblob,
+			Message: "Wrong block blob",
+		}
+	}
+
+	if !strings.HasPrefix(blobs[0], s.template(s.top())) {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeBlockNotAccepted,
+			Message: "Block not accepted",
+		}
+	}
+
+	hashes := s.generateBlocks(1)
+
+	return &daemon.SubmitBlockResult{
+		BlockID:         hashes[0],
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getMinerData(s *state, params []byte) (interface{}, error) {
+	top := s.top()
+
+	backlog := []daemon.MinerDataTx{}
+	for _, hash := range s.pool {
+		tx := s.txs[hash]
+		backlog = append(backlog, daemon.MinerDataTx{
+			ID:     tx.Hash,
+			Weight: tx.Weight,
+			Fee:    tx.Fee,
+		})
+	}
+
+	return &daemon.GetMinerDataResult{
+		MajorVersion:          majorVersion,
+		Height:                s.height(),
+		PrevID:                top.Hash,
+		SeedHash:              s.blocks[0].Hash,
+		Difficulty:            fmt.Sprintf("0x%x", Difficulty),
+		MedianWeight:          300000,
+		AlreadyGeneratedCoins: BlockReward * s.height(),
+		TxBacklog:             backlog,
+		RPCResultFooter:       ok,
+	}, nil
+}
+
+// calcPow gives a fake proof-of-work hash: the sha256 of the blob.
+//
+func calcPow(s *state, params []byte) (interface{}, error) {
+	req := &daemon.CalcPowRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	blob, err := hex.DecodeString(req.BlockBlob)
+	if err != nil || len(blob) == 0 {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongBlockblob,
+			Message: "Wrong block blob",
+		}
+	}
+
+	sum := sha256.Sum256(blob)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// addAuxPow commits to the merge-mined blocks by appending a fake merkle root
+// (the sha256 of their ids and hashes) to the template.
+//
+func addAuxPow(s *state, params []byte) (interface{}, error) {
+	req := &daemon.AddAuxPowRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if _, err := hex.DecodeString(req.BlocktemplateBlob); err != nil {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongBlockblob,
+			Message: "Invalid blocktemplate_blob",
+		}
+	}
+
+	if len(req.AuxPow) == 0 {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Empty aux pow hash vector",
+		}
+	}
+
+	h := sha256.New()
+	for _, aux := range req.AuxPow {
+		h.Write([]byte(aux.ID + aux.Hash))
+	}
+
+	root := hex.EncodeToString(h.Sum(nil))
+	depth := uint32(0)
+
+	for n := 1; n < len(req.AuxPow); n <<= 1 {
+		depth++
+	}
+
+	return &daemon.AddAuxPowResult{
+		BlocktemplateBlob: req.BlocktemplateBlob + root,
+		BlockhashingBlob:  root,
+		MerkleRoot:        root,
+		MerkleTreeDepth:   depth,
+		AuxPow:            req.AuxPow,
+		RPCResultFooter:   ok,
+	}, nil
+}
+
+func getCoinbaseTxSum(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		Height uint64 `json:"height"`
+		Count  uint64 `json:"count"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	var emission, fees uint64
+	for height := req.Height; height < req.Height+req.Count && height < s.height(); height++ {
+		emission += BlockReward
+		for _, hash := range s.blocks[height].TxHashes {
+			fees += s.txs[hash].Fee
+		}
+	}
+
+	return &daemon.GetCoinbaseTxSumResult{
+		EmissionAmount:     int64(emission),
+		FeeAmount:          int(fees),
+		WideEmissionAmount: fmt.Sprintf("0x%x", emission),
+		WideFeeAmount:      fmt.Sprintf("0x%x", fees),
+		RPCResultFooter:    ok,
+	}, nil
+}
+
+func getConnections(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetConnectionsResult{RPCResultFooter: ok}, nil
+}
+
+func getFeeEstimate(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetFeeEstimateResult{
+		Fee:              20000,
+		QuantizationMask: 10000,
+		RPCResultFooter:  ok,
+	}, nil
+}
+
+func getInfo(s *state, params []byte) (interface{}, error) {
+	top := s.top()
+
+	return &daemon.GetInfoResult{
+		AdjustedTime:             uint64(time.Now().Unix()),
+		BlockSizeLimit:           600000,
+		BlockSizeMedian:          300000,
+		BlockWeightLimit:         600000,
+		BlockWeightMedian:        300000,
+		BootstrapDaemonAddress:   s.bootstrapDaemon,
+		CumulativeDifficulty:     int64(Difficulty * s.height()),
+		Difficulty:               Difficulty,
+		GreyPeerlistSize:         uint(len(s.grayPeers)),
+		Height:                   s.height(),
+		HeightWithoutBootstrap:   s.height(),
+		Mainnet:                  s.nettype == "mainnet",
+		Nettype:                  s.nettype,
+		Stagenet:                 s.nettype == "stagenet",
+		StartTime:                uint64(s.startTime.Unix()),
+		Synchronized:             true,
+		Target:                   blockTime,
+		TargetHeight:             s.height(),
+		Testnet:                  s.nettype == "testnet",
+		TopBlockHash:             top.Hash,
+		TxCount:                  uint64(len(s.txs) - len(s.pool)),
+		TxPoolSize:               uint64(len(s.pool)),
+		Version:                  "0.17.3.0-daemontest",
+		WhitePeerlistSize:        uint(len(s.whitePeers)),
+		WideCumulativeDifficulty: fmt.Sprintf("0x%x", Difficulty*s.height()),
+		WideDifficulty:           fmt.Sprintf("0x%x", Difficulty),
+		RPCResultFooter:          ok,
+	}, nil
+}
+
+func getLastBlockHeader(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetLastBlockHeaderResult{
+		BlockHeader:     s.header(s.top()),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getVersion(s *state, params []byte) (interface{}, error) {
+	return &daemon.GetVersionResult{
+		Release:         true,
+		Version:         3<<16 | 10,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func hardForkInfo(s *state, params []byte) (interface{}, error) {
+	return &daemon.HardForkInfoResult{
+		Enabled:         true,
+		Threshold:       0,
+		Version:         majorVersion,
+		Voting:          majorVersion,
+		Window:          10080,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func onGetBlockHash(s *state, params []byte) (interface{}, error) {
+	req := []uint64{}
+	if err := decode(params, &req); err != nil {
+		return nil, err
+	}
+
+	if len(req) != 1 {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeWrongParam,
+			Message: "Wrong parameters, expected height",
+		}
+	}
+
+	block, err := s.lookup(req[0], "")
+	if err != nil {
+		return nil, err
+	}
+
+	return block.Hash, nil
+}
+
+func relayTx(s *state, params []byte) (interface{}, error) {
+	req := &struct {
+		TxIDs []string `json:"txids"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	for _, txid := range req.TxIDs {
+		tx, found := s.txs[txid]
+		if !found || !tx.InPool {
+			return nil, &rpc.Error{
+				Code:    rpc.CodeWrongParam,
+				Message: "Transaction not found in pool: " + txid,
+			}
+		}
+
+		tx.Relayed = true
+	}
+
+	return &daemon.RelayTxResult{RPCResultFooter: ok}, nil
+}
+
+func syncInfo(s *state, params []byte) (interface{}, error) {
+	return &daemon.SyncInfoResult{
+		Height:          s.height(),
+		TargetHeight:    s.height(),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+// raw
+
+func getHeight(s *state, body []byte) (interface{}, error) {
+	return &daemon.GetHeightResult{
+		Hash:            s.top().Hash,
+		Height:          s.height(),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getLimit(s *state, body []byte) (interface{}, error) {
+	return &daemon.GetLimitResult{
+		LimitUp:         s.limitUp,
+		LimitDown:       s.limitDown,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func setLimit(s *state, body []byte) (interface{}, error) {
+	req := &daemon.SetLimitRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	if req.LimitUp != 0 {
+		s.limitUp = req.LimitUp
+	}
+
+	if req.LimitDown != 0 {
+		s.limitDown = req.LimitDown
+	}
+
+	return &daemon.SetLimitResult{
+		LimitUp:         s.limitUp,
+		LimitDown:       s.limitDown,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getNetStats(s *state, body []byte) (interface{}, error) {
+	return &daemon.GetNetStatsResult{
+		StartTime:       s.startTime.Unix(),
+		TotalBytesIn:    s.bytesIn,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getOuts(s *state, body []byte) (interface{}, error) {
+	req := &struct {
+		Outputs []struct {
+			Amount uint64 `json:"amount"`
+			Index  uint64 `json:"index"`
+		} `json:"outputs"`
+		GetTxID bool `json:"get_txid"`
+	}{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	type out struct {
+		Height   uint64 `json:"height"`
+		Key      string `json:"key"`
+		Mask     string `json:"mask"`
+		Txid     string `json:"txid"`
+		Unlocked bool   `json:"unlocked"`
+	}
+
+	outs := []out{}
+	for _, output := range req.Outputs {
+		o := out{
+			Height:   output.Index % s.height(),
+			Key:      fakeHash("output_key", output.Amount, "", output.Index),
+			Mask:     fakeHash("output_mask", output.Amount, "", output.Index),
+			Unlocked: true,
+		}
+
+		if req.GetTxID {
+			o.Txid = fakeHash("output_txid", output.Amount, "", output.Index)
+		}
+
+		outs = append(outs, o)
+	}
+
+	return map[string]interface{}{
+		"outs":   outs,
+		"status": rpc.StatusOK,
+	}, nil
+}
+
+func getPeerList(s *state, body []byte) (interface{}, error) {
+	return &daemon.GetPeerListResult{
+		GrayList:        s.grayPeers,
+		WhiteList:       s.whitePeers,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func getPublicNodes(s *state, body []byte) (interface{}, error) {
+	req := &daemon.GetPublicNodesRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	public := func(peers []daemon.Peer) []daemon.Peer {
+		res := []daemon.Peer{}
+		for _, peer := range peers {
+			if _, banned := s.bans[peer.Host]; banned && !req.IncludeBlocked {
+				continue
+			}
+
+			if peer.RPCPort != 0 {
+				res = append(res, peer)
+			}
+		}
+
+		return res
+	}
+
+	resp := &daemon.GetPublicNodesResult{RPCResultFooter: ok}
+	if req.White {
+		resp.WhiteList = public(s.whitePeers)
+	}
+
+	if req.Gray {
+		resp.GrayList = public(s.grayPeers)
+	}
+
+	return resp, nil
+}
+
+func getTransactionPool(s *state, body []byte) (interface{}, error) {
+	type transaction struct {
+		BlobSize    uint64 `json:"blob_size"`
+		Fee         uint64 `json:"fee"`
+		IDHash      string `json:"id_hash"`
+		ReceiveTime int64  `json:"receive_time"`
+		Relayed     bool   `json:"relayed"`
+		TxBlob      string `json:"tx_blob"`
+		TxJSON      string `json:"tx_json"`
+		Weight      uint64 `json:"weight"`
+	}
+
+	txs := []transaction{}
+	for _, hash := range s.pool {
+		tx := s.txs[hash]
+
+		txs = append(txs, transaction{
+			BlobSize:    uint64(len(tx.Blob) / 2),
+			Fee:         tx.Fee,
+			IDHash:      tx.Hash,
+			ReceiveTime: tx.ReceiveTime,
+			Relayed:     tx.Relayed,
+			TxBlob:      tx.Blob,
+			TxJSON:      tx.JSON,
+			Weight:      tx.Weight,
+		})
+	}
+
+	return map[string]interface{}{
+		"transactions": txs,
+		"status":       rpc.StatusOK,
+	}, nil
+}
+
+func getTransactionPoolStats(s *state, body []byte) (interface{}, error) {
+	stats := map[string]interface{}{}
+
+	var total, fees, min, max uint64
+	oldest := int64(0)
+
+	for idx, hash := range s.pool {
+		tx := s.txs[hash]
+		size := uint64(len(tx.Blob) / 2)
+
+		total += size
+		fees += tx.Fee
+
+		if idx == 0 || size < min {
+			min = size
+		}
+
+		if size > max {
+			max = size
+		}
+
+		if oldest == 0 || tx.ReceiveTime < oldest {
+			oldest = tx.ReceiveTime
+		}
+	}
+
+	stats["bytes_total"] = total
+	stats["bytes_min"] = min
+	stats["bytes_max"] = max
+	stats["fee_total"] = fees
+	stats["oldest"] = oldest
+	stats["txs_total"] = len(s.pool)
+
+	return map[string]interface{}{
+		"pool_stats": stats,
+		"status":     rpc.StatusOK,
+	}, nil
+}
+
+func getTransactions(s *state, body []byte) (interface{}, error) {
+	req := &struct {
+		TxsHashes    []string `json:"txs_hashes"`
+		DecodeAsJSON bool     `json:"decode_as_json"`
+	}{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	resp := &daemon.GetTransactionsResult{
+		Txs:             []daemon.GetTransactionsResultTransaction{},
+		RPCResultFooter: ok,
+	}
+	var missed []string
+
+	for _, hash := range req.TxsHashes {
+		tx, found := s.txs[hash]
+		if !found {
+			missed = append(missed, hash)
+			continue
+		}
+
+		entry := daemon.GetTransactionsResultTransaction{
+			AsHex:  tx.Blob,
+			InPool: tx.InPool,
+			TxHash: tx.Hash,
+		}
+
+		if req.DecodeAsJSON {
+			entry.AsJSON = tx.JSON
+		}
+
+		if !tx.InPool {
+			entry.BlockHeight = tx.BlockHeight
+			entry.BlockTimestamp = s.blocks[tx.BlockHeight].Timestamp
+		}
+
+		resp.Txs = append(resp.Txs, entry)
+		resp.TxsAsHex = append(resp.TxsAsHex, tx.Blob)
+	}
+
+	if len(missed) > 0 {
+		resp.MissedTx = missed
+	}
+
+	return resp, nil
+}
+
+func isKeyImageSpent(s *state, body []byte) (interface{}, error) {
+	req := &struct {
+		KeyImages []string `json:"key_images"`
+	}{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	resp := &daemon.IsKeyImageSpentResult{
+		SpentStatus:     make([]daemon.KeyImageSpentStatus, len(req.KeyImages)),
+		RPCResultFooter: ok,
+	}
+
+	for idx, keyImage := range req.KeyImages {
+		if _, err := hex.DecodeString(keyImage); err != nil || len(keyImage) != 64 {
+			return nil, failed("Failed to parse key image")
+		}
+
+		resp.SpentStatus[idx] = s.keyImageStatus(keyImage)
+	}
+
+	return resp, nil
+}
+
+func sendRawTransaction(s *state, body []byte) (interface{}, error) {
+	req := &daemon.SendRawTransactionRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	resp := &daemon.SendRawTransactionResult{
+		RPCResultFooter: daemon.RPCResultFooter{Status: rpc.StatusFailed},
+	}
+
+	blob, err := hex.DecodeString(req.TxAsHex)
+	if err != nil || len(blob) == 0 {
+		resp.Reason = "Failed to parse hex representation of transaction data"
+		return resp, nil
+	}
+
+	sum := sha256.Sum256(blob)
+	hash := hex.EncodeToString(sum[:])
+
+	if _, found := s.txs[hash]; found {
+		resp.DoubleSpend = true
+	}
+
+	if len(blob) > maxTxSize {
+		resp.TooBig = true
+	}
+
+	if s.txValidator != nil {
+		for _, reason := range s.txValidator(blob, req.DoSanityChecks) {
+			setRejectionReason(resp, reason)
+		}
+	}
+
+	if reasons := resp.RejectionReasons(); len(reasons) > 0 {
+		resp.Reason = string(reasons[0])
+		return resp, nil
+	}
+
+	s.txs[hash] = &Tx{
+		Hash:        hash,
+		Blob:        req.TxAsHex,
+		Weight:      uint64(len(blob)),
+		ReceiveTime: time.Now().Unix(),
+		Relayed:     !req.DoNotRelay,
+		InPool:      true,
+	}
+	s.pool = append(s.pool, hash)
+
+	resp.Status = rpc.StatusOK
+	resp.NotRelayed = req.DoNotRelay
+
+	return resp, nil
+}
+
+// setRejectionReason sets the flag corresponding to `reason` in `resp`.
+//
+func setRejectionReason(resp *daemon.SendRawTransactionResult, reason daemon.RejectionReason) {
+	switch reason {
+	case daemon.RejectionDoubleSpend:
+		resp.DoubleSpend = true
+	case daemon.RejectionFeeTooLow:
+		resp.FeeTooLow = true
+	case daemon.RejectionInvalidInput:
+		resp.InvalidInput = true
+	case daemon.RejectionInvalidOutput:
+		resp.InvalidOutput = true
+	case daemon.RejectionLowMixin:
+		resp.LowMixin = true
+	case daemon.RejectionNonzeroUnlockTime:
+		resp.NonzeroUnlockTime = true
+	case daemon.RejectionOverspend:
+		resp.Overspend = true
+	case daemon.RejectionSanityCheckFailed:
+		resp.SanityCheckFailed = true
+	case daemon.RejectionTooBig:
+		resp.TooBig = true
+	case daemon.RejectionTooFewOutputs:
+		resp.TooFewOutputs = true
+	case daemon.RejectionTxExtraTooBig:
+		resp.TxExtraTooBig = true
+	}
+}
+
+func miningStatus(s *state, body []byte) (interface{}, error) {
+	return &daemon.MiningStatusResult{
+		Active:          s.mining,
+		Address:         s.minerAddress,
+		BlockReward:     BlockReward,
+		BlockTarget:     blockTime,
+		Difficulty:      Difficulty,
+		PowAlgorithm:    "RandomX",
+		ThreadsCount:    uint64(s.miningThreads),
+		WideDifficulty:  fmt.Sprintf("0x%x", Difficulty),
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func startMining(s *state, body []byte) (interface{}, error) {
+	req := &daemon.StartMiningRequestParameters{}
+	if err := decode(body, req); err != nil || req.MinerAddress == "" {
+		return nil, failed("Failed, wrong address")
+	}
+
+	if s.mining {
+		return nil, failed("Already mining")
+	}
+
+	s.mining = true
+	s.minerAddress = req.MinerAddress
+	s.miningThreads = req.ThreadsCount
+
+	return &daemon.StartMiningResult{RPCResultFooter: ok}, nil
+}
+
+func stopMining(s *state, body []byte) (interface{}, error) {
+	if !s.mining {
+		return nil, failed("Mining never started")
+	}
+
+	s.mining = false
+	s.minerAddress = ""
+	s.miningThreads = 0
+
+	return &daemon.StopMiningResult{RPCResultFooter: ok}, nil
+}
+
+func setLogCategories(s *state, body []byte) (interface{}, error) {
+	req := &daemon.SetLogCategoriesRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	s.logCategories = req.Categories
+
+	return &daemon.SetLogCategoriesResult{
+		Categories:      s.logCategories,
+		RPCResultFooter: ok,
+	}, nil
+}
+
+func setLogLevel(s *state, body []byte) (interface{}, error) {
+	req := &daemon.SetLogLevelRequestParameters{}
+	if err := decode(body, req); err != nil {
+		return nil, failed("Failed")
+	}
+
+	if req.Level < 0 || req.Level > 4 {
+		return nil, failed("Error: log level not valid")
+	}
+
+	s.logLevel = req.Level
+
+	return &daemon.SetLogLevelResult{RPCResultFooter: ok}, nil
+}
+
+// lookup retrieves a block either by hash (if not empty) or by height.
+//
+func (s *state) lookup(height uint64, hash string) (*Block, error) {
+	if hash != "" {
+		block, found := s.blockByHash(hash)
+		if !found {
+			return nil, &rpc.Error{
+				Code:    rpc.CodeInternalError,
+				Message: "Internal error: can't get block by hash. Hash = " + hash + ".",
+			}
+		}
+
+		return block, nil
+	}
+
+	if height >= s.height() {
+		return nil, &rpc.Error{
+			Code: rpc.CodeTooBigHeight,
+			Message: fmt.Sprintf("Requested block height: %d greater than current top block height: %d",
+				height, s.height()-1),
+		}
+	}
+
+	return s.blocks[height], nil
+}
@@ -0,0 +1,187 @@
+package daemontest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+// spendableAge is the number of blocks after which outputs are unlocked.
+//
+const spendableAge = 10
+
+// outputsAt gives the number of outputs of `amount` created by `block`: its
+// coinbase plus two per transaction for RingCT outputs (amount 0), and one
+// every other block for any other amount.
+//
+func outputsAt(amount uint64, block *Block) uint64 {
+	if amount == 0 {
+		return 1 + 2*uint64(len(block.TxHashes))
+	}
+
+	if block.Height%2 == 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// epeeBlob is a binary blob serialized in JSON the way monerod does: as a
+// string with only quotes, backslashes and control characters escaped.
+//
+type epeeBlob []byte
+
+func (b epeeBlob) MarshalJSON() ([]byte, error) {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+	for _, c := range b {
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&sb, "\\u%04x", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+
+	return []byte(sb.String()), nil
+}
+
+func getOutputDistribution(s *state, params []byte) (interface{}, error) {
+	req := &daemon.GetOutputDistributionRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	top := s.height() - 1
+	to := req.ToHeight
+	if to == 0 {
+		to = top
+	}
+
+	if req.FromHeight > to || to > top {
+		return nil, &rpc.Error{
+			Code:    rpc.CodeInternalError,
+			Message: "Failed to get output distribution",
+		}
+	}
+
+	distributions := []map[string]interface{}{}
+
+	for _, amount := range req.Amounts {
+		var (
+			base   uint64
+			counts []uint64
+		)
+
+		for _, block := range s.blocks[:to+1] {
+			n := outputsAt(amount, block)
+			if block.Height < req.FromHeight {
+				base += n
+				continue
+			}
+
+			counts = append(counts, n)
+		}
+
+		if req.Cumulative {
+			sum := base
+			for idx := range counts {
+				sum += counts[idx]
+				counts[idx] = sum
+			}
+		}
+
+		distribution := map[string]interface{}{
+			"amount":       amount,
+			"start_height": req.FromHeight,
+			"base":         base,
+			"binary":       req.Binary,
+			"compress":     req.Compress,
+		}
+
+		switch {
+		case !req.Binary:
+			distribution["distribution"] = counts
+		case req.Compress:
+			var blob []byte
+			for _, v := range counts {
+				b := make([]byte, binary.MaxVarintLen64)
+				blob = append(blob, b[:binary.PutUvarint(b, v)]...)
+			}
+
+			distribution["compressed_data"] = epeeBlob(blob)
+		default:
+			blob := make([]byte, 8*len(counts))
+			for idx, v := range counts {
+				binary.LittleEndian.PutUint64(blob[idx*8:], v)
+			}
+
+			distribution["distribution"] = epeeBlob(blob)
+		}
+
+		distributions = append(distributions, distribution)
+	}
+
+	return map[string]interface{}{
+		"distributions": distributions,
+		"status":        rpc.StatusOK,
+	}, nil
+}
+
+func getOutputHistogram(s *state, params []byte) (interface{}, error) {
+	req := &daemon.GetOutputHistogramRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	amounts := req.Amounts
+	if len(amounts) == 0 {
+		amounts = []uint64{0}
+	}
+
+	top := s.height() - 1
+	histogram := []daemon.HistogramEntry{}
+
+	for _, amount := range amounts {
+		entry := daemon.HistogramEntry{Amount: amount}
+
+		for _, block := range s.blocks {
+			n := outputsAt(amount, block)
+			unlocked := block.Height+spendableAge <= top
+
+			if unlocked {
+				entry.UnlockedInstances += n
+			}
+
+			if req.Unlocked && !unlocked {
+				continue
+			}
+
+			entry.TotalInstances += n
+
+			if req.RecentCutoff != 0 && block.Timestamp >= int64(req.RecentCutoff) {
+				entry.RecentInstances += n
+			}
+		}
+
+		if entry.TotalInstances < req.MinCount ||
+			(req.MaxCount != 0 && entry.TotalInstances > req.MaxCount) {
+			continue
+		}
+
+		histogram = append(histogram, entry)
+	}
+
+	return &daemon.GetOutputHistogramResult{
+		Histogram:       histogram,
+		RPCResultFooter: ok,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package daemontest
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+// account is what the server keeps track of for each client paying for rpc.
+//
+type account struct {
+	balance      uint64
+	creditsTotal uint64
+	creditsUsed  uint64
+	noncesGood   uint64
+	noncesDupe   uint64
+	noncesStale  uint64
+	lastSign     time.Time
+	lastUpdate   time.Time
+	nonces       map[uint32]bool
+}
+
+// payments is the state of rpc payments, when enabled.
+//
+type payments struct {
+	cost           uint64
+	creditsPerHash uint64
+	accounts       map[string]*account
+}
+
+func newPayments(cost, creditsPerHash uint64) *payments {
+	return &payments{
+		cost:           cost,
+		creditsPerHash: creditsPerHash,
+		accounts:       map[string]*account{},
+	}
+}
+
+// paid tells whether calls to `method` are charged for.
+//
+func paid(method string) bool {
+	return !strings.HasPrefix(method, "rpc_access_")
+}
+
+// identify verifies the client signature `client`, giving back the account
+// of the client.
+//
+func (p *payments) identify(client string) (string, *account, error) {
+	public, ts, err := monero.VerifyRPCPaymentSignature(client)
+	if err != nil {
+		return "", nil, &rpc.Error{Code: rpc.CodeInvalidClient, Message: "Invalid client"}
+	}
+
+	acc, found := p.accounts[public]
+	if !found {
+		acc = &account{nonces: map[uint32]bool{}}
+		p.accounts[public] = acc
+	}
+
+	if !ts.After(acc.lastSign) {
+		return "", nil, &rpc.Error{Code: rpc.CodeInvalidClient, Message: "Invalid client"}
+	}
+
+	acc.lastSign = ts
+
+	return public, acc, nil
+}
+
+// charge deducts the cost of a call from the balance of the client
+// identified in `params`, giving back the footer to reply with and whether
+// the call has been paid for.
+//
+func (s *state) charge(params []byte) (daemon.RPCResultFooter, bool) {
+	footer := daemon.RPCResultFooter{
+		Status:  rpc.StatusPaymentRequired,
+		TopHash: s.top().Hash,
+	}
+
+	req := &struct {
+		Client string `json:"client"`
+	}{}
+
+	if err := json.Unmarshal(params, req); err != nil || req.Client == "" {
+		return footer, false
+	}
+
+	_, acc, err := s.payments.identify(req.Client)
+	if err != nil {
+		return footer, false
+	}
+
+	footer.Credits = acc.balance
+	if acc.balance < s.payments.cost {
+		return footer, false
+	}
+
+	acc.balance -= s.payments.cost
+	acc.creditsUsed += s.payments.cost
+	acc.lastUpdate = time.Now()
+
+	footer.Status = rpc.StatusOK
+	footer.Credits = acc.balance
+
+	return footer, true
+}
+
+// withCredits fills the balance of credits and top hash from `footer` in the
+// (JSON object) result `v`.
+//
+func withCredits(v interface{}, footer daemon.RPCResultFooter) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return v
+	}
+
+	fields["credits"] = footer.Credits
+	fields["top_hash"] = footer.TopHash
+
+	return fields
+}
+
+func errPaymentNotEnabled() error {
+	return &rpc.Error{
+		Code:    rpc.CodePaymentNotRequired,
+		Message: "Payments not enabled",
+	}
+}
+
+func rpcAccessInfo(s *state, params []byte) (interface{}, error) {
+	if s.payments == nil {
+		return nil, errPaymentNotEnabled()
+	}
+
+	req := &daemon.RPCAccessInfoRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	_, acc, err := s.payments.identify(req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	top := s.top()
+
+	return &daemon.RPCAccessInfoResult{
+		HashingBlob:         top.Hash + "00000000",
+		SeedHeight:          0,
+		SeedHash:            s.blocks[0].Hash,
+		Cookie:              uint32(top.Height),
+		Diff:                Difficulty,
+		CreditsPerHashFound: s.payments.creditsPerHash,
+		Height:              s.height(),
+		RPCResultFooter: daemon.RPCResultFooter{
+			Status:  rpc.StatusOK,
+			Credits: acc.balance,
+			TopHash: top.Hash,
+		},
+	}, nil
+}
+
+// rpcAccessSubmitNonce credits the client for any nonce not submitted before:
+// unable to compute RandomX hashes, the server takes any of them as meeting
+// the difficulty.
+//
+func rpcAccessSubmitNonce(s *state, params []byte) (interface{}, error) {
+	if s.payments == nil {
+		return nil, errPaymentNotEnabled()
+	}
+
+	req := &daemon.RPCAccessSubmitNonceRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	_, acc, err := s.payments.identify(req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Cookie != uint32(s.top().Height) {
+		acc.noncesStale++
+		return nil, &rpc.Error{Code: rpc.CodeStalePayment, Message: "Stale payment"}
+	}
+
+	if acc.nonces[req.Nonce] {
+		acc.noncesDupe++
+		return nil, &rpc.Error{Code: rpc.CodeDuplicatePayment, Message: "Duplicate payment"}
+	}
+
+	acc.nonces[req.Nonce] = true
+	acc.noncesGood++
+	acc.balance += s.payments.creditsPerHash
+	acc.creditsTotal += s.payments.creditsPerHash
+	acc.lastUpdate = time.Now()
+
+	return &daemon.RPCAccessSubmitNonceResult{
+		RPCResultFooter: daemon.RPCResultFooter{
+			Status:  rpc.StatusOK,
+			Credits: acc.balance,
+			TopHash: s.top().Hash,
+		},
+	}, nil
+}
+
+func rpcAccessPay(s *state, params []byte) (interface{}, error) {
+	if s.payments == nil {
+		return nil, errPaymentNotEnabled()
+	}
+
+	req := &daemon.RPCAccessPayRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	_, acc, err := s.payments.identify(req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	footer := daemon.RPCResultFooter{
+		Status:  rpc.StatusPaymentRequired,
+		Credits: acc.balance,
+		TopHash: s.top().Hash,
+	}
+
+	if acc.balance >= req.Payment {
+		acc.balance -= req.Payment
+		acc.creditsUsed += req.Payment
+		acc.lastUpdate = time.Now()
+
+		footer.Status = rpc.StatusOK
+		footer.Credits = acc.balance
+	}
+
+	return &daemon.RPCAccessPayResult{RPCResultFooter: footer}, nil
+}
+
+func rpcAccessAccount(s *state, params []byte) (interface{}, error) {
+	if s.payments == nil {
+		return nil, errPaymentNotEnabled()
+	}
+
+	req := &daemon.RPCAccessAccountRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	_, acc, err := s.payments.identify(req.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.DeltaBalance > 0:
+		acc.balance += uint64(req.DeltaBalance)
+		acc.creditsTotal += uint64(req.DeltaBalance)
+	case uint64(-req.DeltaBalance) > acc.balance:
+		acc.balance = 0
+	default:
+		acc.balance -= uint64(-req.DeltaBalance)
+	}
+
+	acc.lastUpdate = time.Now()
+
+	return &daemon.RPCAccessAccountResult{
+		RPCResultFooter: daemon.RPCResultFooter{
+			Status:  rpc.StatusOK,
+			Credits: acc.balance,
+			TopHash: s.top().Hash,
+		},
+	}, nil
+}
+
+func rpcAccessData(s *state, params []byte) (interface{}, error) {
+	if s.payments == nil {
+		return nil, errPaymentNotEnabled()
+	}
+
+	entries := []daemon.RPCAccessDataEntry{}
+	for client, acc := range s.payments.accounts {
+		entries = append(entries, daemon.RPCAccessDataEntry{
+			Client:         client,
+			Balance:        acc.balance,
+			LastUpdateTime: uint64(acc.lastUpdate.Unix()),
+			CreditsTotal:   acc.creditsTotal,
+			CreditsUsed:    acc.creditsUsed,
+			NoncesGood:     acc.noncesGood,
+			NoncesStale:    acc.noncesStale,
+			NoncesDupe:     acc.noncesDupe,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Client < entries[j].Client
+	})
+
+	return &daemon.RPCAccessDataResult{
+		Entries:         entries,
+		RPCResultFooter: ok,
+	}, nil
+}
@@ -0,0 +1,519 @@
+package daemontest
+
+import (
+	"crypto/md5" // nolint:gosec
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	mhttp "github.com/jjsteel/go-monero/pkg/http"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+const (
+	endpointJSONRPC = "/json_rpc"
+	digestRealm     = "monero-rpc"
+)
+
+// Fault describes a misbehavior that the server should exhibit when serving
+// requests.
+//
+type Fault struct {
+	// Methods restricts the fault to the JSONRPC methods or raw endpoints
+	// listed (e.g., "get_info" or "/get_height"). When empty, the fault
+	// applies to every request.
+	//
+	Methods []string
+
+	// Times is the number of requests the fault applies to before being
+	// lifted. When 0, it applies until the faults are cleared.
+	//
+	Times int
+
+	// Latency is how long to wait before serving the request.
+	//
+	Latency time.Duration
+
+	// Busy makes the server reply with a `BUSY` status, just like monerod
+	// does while it's still syncing.
+	//
+	Busy bool
+
+	// MalformedJSON makes the server reply with a body that can't be
+	// decoded.
+	//
+	MalformedJSON bool
+
+	// StatusCode, if set, is the HTTP status code to reply with (without
+	// a body).
+	//
+	StatusCode int
+}
+
+func (f *Fault) appliesTo(method string) bool {
+	if len(f.Methods) == 0 {
+		return true
+	}
+
+	for _, m := range f.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+type serverOptions struct {
+	blocks   uint64
+	nettype  string
+	username string
+	password string
+
+	payment        bool
+	paymentCost    uint64
+	creditsPerHash uint64
+
+	txValidator TxValidator
+}
+
+// Option is a functional option for configuring the server.
+//
+type Option func(o *serverOptions)
+
+// WithBlocks seeds the chain with `v` blocks (including the genesis one).
+//
+func WithBlocks(v uint64) Option {
+	return func(o *serverOptions) {
+		o.blocks = v
+	}
+}
+
+// WithNettype sets the network type reported by the server (`mainnet`,
+// `testnet`, `stagenet` or `fakechain`).
+//
+func WithNettype(v string) Option {
+	return func(o *serverOptions) {
+		o.nettype = v
+	}
+}
+
+// WithDigestAuth requires clients to authenticate using HTTP digest
+// authentication, challenging them just like monerod does when started with
+// `--rpc-login`.
+//
+func WithDigestAuth(username, password string) Option {
+	return func(o *serverOptions) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// TxValidator decides whether a transaction submitted through
+// `/send_raw_transaction` is accepted, giving back the reasons for rejecting
+// it, if any.
+//
+type TxValidator func(blob []byte, sanityChecks bool) []daemon.RejectionReason
+
+// WithTxValidator sets how to validate transactions submitted through
+// `/send_raw_transaction`, on top of the checks that the server always
+// performs (the transaction not being already known, i.e., a double spend,
+// and not being too big).
+//
+func WithTxValidator(v TxValidator) Option {
+	return func(o *serverOptions) {
+		o.txValidator = v
+	}
+}
+
+// WithRPCPayment makes the server offer rpc for credits, just like monerod does
+// when started with `--rpc-payment-address`: every call (other than the
+// `rpc_access_*` ones) costs `cost` credits, and every nonce submitted gives
+// `creditsPerHash` credits.
+//
+// As the server can't compute RandomX hashes, any nonce not submitted before
+// is taken as meeting the difficulty.
+//
+func WithRPCPayment(cost, creditsPerHash uint64) Option {
+	return func(o *serverOptions) {
+		o.payment = true
+		o.paymentCost = cost
+		o.creditsPerHash = creditsPerHash
+	}
+}
+
+// Server is an in-memory fake of monerod serving both `/json_rpc` methods and
+// the raw endpoints, backed by a programmable chain.
+//
+type Server struct {
+	*httptest.Server
+
+	opts  serverOptions
+	nonce string
+
+	mu     sync.Mutex
+	state  *state
+	faults []*Fault
+	calls  map[string]uint64
+}
+
+// NewServer instantiates and starts a new fake monerod.
+//
+func NewServer(opts ...Option) *Server {
+	options := serverOptions{
+		blocks:  1,
+		nettype: "mainnet",
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	s := &Server{
+		opts:  options,
+		nonce: randomHex(16),
+		state: newState(options.nettype),
+		calls: map[string]uint64{},
+	}
+
+	s.state.txValidator = options.txValidator
+
+	if options.payment {
+		s.state.payments = newPayments(options.paymentCost, options.creditsPerHash)
+	}
+
+	if options.blocks > 1 {
+		s.state.generateBlocks(options.blocks - 1)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// NewClient instantiates a daemon client targeting this server, already
+// configured with the credentials for digest authentication if required.
+//
+func (s *Server) NewClient(opts ...rpc.ClientOption) (*daemon.Client, error) {
+	httpClient := s.Server.Client()
+
+	if s.opts.username != "" {
+		httpClient.Transport = mhttp.NewDigestAuthTransport(
+			s.opts.username, s.opts.password, httpClient.Transport,
+		)
+	}
+
+	client, err := rpc.NewClient(s.URL,
+		append([]rpc.ClientOption{rpc.WithHTTPClient(httpClient)}, opts...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	return daemon.NewClient(client), nil
+}
+
+// InjectFault makes the server misbehave according to `f` from now on.
+//
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults lifts all of the faults previously injected.
+//
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Calls gives how many times the JSONRPC method or raw endpoint `method` has
+// been served.
+//
+func (s *Server) Calls(method string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Digest qop="auth",algorithm=MD5,realm="%s",nonce="%s",stale=false`,
+			digestRealm, s.nonce,
+		))
+		w.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if r.URL.Path == endpointJSONRPC {
+		s.serveJSONRPC(w, body)
+		return
+	}
+
+	s.serveRaw(w, r.URL.Path, body)
+}
+
+func (s *Server) serveJSONRPC(w http.ResponseWriter, body []byte) {
+	req := &rpc.RequestEnvelope{}
+	if err := json.Unmarshal(body, req); err != nil {
+		writeJSON(w, &rpc.ResponseEnvelope{
+			ID:      "0",
+			JSONRPC: "2.0",
+			Error: &rpc.Error{
+				Code:    rpc.CodeParseError,
+				Message: "Parse error",
+			},
+		})
+
+		return
+	}
+
+	if s.fault(w, req.Method, func() interface{} {
+		return &rpc.ResponseEnvelope{
+			ID:      req.ID,
+			JSONRPC: "2.0",
+			Result:  daemon.RPCResultFooter{Status: rpc.StatusBusy},
+		}
+	}) {
+		return
+	}
+
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	resp := &rpc.ResponseEnvelope{
+		ID:      req.ID,
+		JSONRPC: "2.0",
+	}
+
+	handler, found := jsonrpcHandlers[req.Method]
+	if !found {
+		resp.Error = &rpc.Error{
+			Code:    rpc.CodeMethodNotFound,
+			Message: "Method not found",
+		}
+
+		writeJSON(w, resp)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	result, err := s.handle(req.Method, params, func() (interface{}, error) {
+		return handler(s.state, params)
+	})
+	s.mu.Unlock()
+
+	if err != nil {
+		resp.Error = toRPCError(err)
+	} else {
+		resp.Result = result
+	}
+
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveRaw(w http.ResponseWriter, endpoint string, body []byte) {
+	handler, found := rawHandlers[endpoint]
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if s.fault(w, endpoint, func() interface{} {
+		return daemon.RPCResultFooter{Status: rpc.StatusBusy}
+	}) {
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[endpoint]++
+	s.state.bytesIn += uint64(len(body))
+	result, err := s.handle(endpoint, body, func() (interface{}, error) {
+		return handler(s.state, body)
+	})
+	s.mu.Unlock()
+
+	var statusErr *rpc.StatusError
+	if errors.As(err, &statusErr) {
+		result = daemon.RPCResultFooter{Status: statusErr.Status}
+	}
+
+	writeJSON(w, result)
+}
+
+// handle serves a call through `handler`, charging the client for it first
+// if rpc payments are enabled.
+//
+// ps.: must be called with the lock held.
+//
+func (s *Server) handle(
+	method string, params []byte, handler func() (interface{}, error),
+) (interface{}, error) {
+	if s.state.payments == nil || !paid(method) {
+		return handler()
+	}
+
+	footer, charged := s.state.charge(params)
+	if !charged {
+		return footer, nil
+	}
+
+	result, err := handler()
+	if err != nil {
+		return nil, err
+	}
+
+	return withCredits(result, footer), nil
+}
+
+// fault applies the faults injected for `method`, returning whether the
+// request has already been replied to.
+//
+func (s *Server) fault(
+	w http.ResponseWriter, method string, busy func() interface{},
+) bool {
+	var applied []Fault
+
+	s.mu.Lock()
+	remaining := s.faults[:0]
+	for _, f := range s.faults {
+		if !f.appliesTo(method) {
+			remaining = append(remaining, f)
+			continue
+		}
+
+		applied = append(applied, *f)
+
+		f.Times--
+		if f.Times != 0 {
+			remaining = append(remaining, f)
+		}
+	}
+	s.faults = remaining
+	s.mu.Unlock()
+
+	for _, f := range applied {
+		if f.Latency > 0 {
+			time.Sleep(f.Latency)
+		}
+
+		switch {
+		case f.StatusCode != 0:
+			w.WriteHeader(f.StatusCode)
+			return true
+		case f.MalformedJSON:
+			fmt.Fprint(w, `{"id": "0", "jsonrpc": "2.0", "result": {"sta`)
+			return true
+		case f.Busy:
+			writeJSON(w, busy())
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorized verifies the digest authentication credentials of the request,
+// if the server requires authentication at all.
+//
+func (s *Server) authorized(r *http.Request) bool {
+	if s.opts.username == "" {
+		return true
+	}
+
+	fields := parseDigestFields(r.Header.Get("Authorization"))
+	if fields == nil ||
+		fields["username"] != s.opts.username ||
+		fields["nonce"] != s.nonce {
+		return false
+	}
+
+	ha1 := md5Hex(s.opts.username + ":" + digestRealm + ":" + s.opts.password)
+	ha2 := md5Hex(r.Method + ":" + fields["uri"])
+	expected := md5Hex(strings.Join([]string{
+		ha1, fields["nonce"], fields["nc"], fields["cnonce"], fields["qop"], ha2,
+	}, ":"))
+
+	return fields["response"] == expected
+}
+
+func parseDigestFields(header string) map[string]string {
+	const prefix = "Digest "
+
+	if !strings.HasPrefix(header, prefix) {
+		return nil
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(header[len(prefix):], ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			return nil
+		}
+
+		fields[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+
+	return fields
+}
+
+func toRPCError(err error) *rpc.Error {
+	var rpcErr *rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	return &rpc.Error{
+		Code:    rpc.CodeWrongParam,
+		Message: err.Error(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func md5Hex(s string) string {
+	// nolint:gosec
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Errorf("rand read: %w", err))
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,186 @@
+package daemontest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/jjsteel/go-monero/pkg/http"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestServer(t *testing.T) {
+	spec.Run(t, "Server", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(10))
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		when("chain", func() {
+			it("reports the seeded chain", func() {
+				info, err := client.GetInfo(ctx)
+				require.NoError(t, err)
+				assert.EqualValues(t, 10, info.Height)
+
+				header, err := client.GetLastBlockHeader(ctx)
+				require.NoError(t, err)
+				assert.EqualValues(t, 9, header.BlockHeader.Height)
+				assert.Equal(t, info.TopBlockHash, header.BlockHeader.Hash)
+			})
+
+			it("links blocks together", func() {
+				resp, err := client.GetBlockHeadersRange(ctx, 1, 3)
+				require.NoError(t, err)
+				require.Len(t, resp.Headers, 3)
+
+				for idx := 1; idx < len(resp.Headers); idx++ {
+					assert.Equal(t, resp.Headers[idx-1].Hash, resp.Headers[idx].PrevHash)
+				}
+
+				byHash, err := client.GetBlockHeaderByHash(ctx, []string{resp.Headers[1].Hash})
+				require.NoError(t, err)
+				assert.EqualValues(t, 2, byHash.BlockHeader.Height)
+			})
+
+			it("errors w/ heights past the top", func() {
+				_, err := client.GetBlockHeaderByHeight(ctx, 10)
+				assert.True(t, errors.Is(err, rpc.ErrTooBigHeight))
+			})
+
+			it("mines pool txs w/ generateblocks", func() {
+				server.AddTx(daemontest.Tx{Hash: "aa", Blob: "0102", Fee: 10})
+
+				pool, err := client.GetTransactionPool(ctx)
+				require.NoError(t, err)
+				require.Len(t, pool.Transactions, 1)
+
+				resp, err := client.GenerateBlocks(ctx, daemon.GenerateBlocksRequestParameters{
+					AmountOfBlocks: 2,
+				})
+				require.NoError(t, err)
+				assert.Len(t, resp.Blocks, 2)
+				assert.EqualValues(t, 12, server.Height())
+
+				block, err := client.GetBlock(ctx, daemon.GetBlockRequestParameters{Height: 10})
+				require.NoError(t, err)
+
+				blockJSON, err := block.InnerJSON()
+				require.NoError(t, err)
+				assert.Equal(t, []string{"aa"}, blockJSON.TxHashes)
+
+				txs, err := client.GetTransactions(ctx, []string{"aa"})
+				require.NoError(t, err)
+				require.Len(t, txs.Txs, 1)
+				assert.False(t, txs.Txs[0].InPool)
+				assert.EqualValues(t, 10, txs.Txs[0].BlockHeight)
+			})
+		})
+
+		when("faults", func() {
+			it("replies w/ busy status", func() {
+				server.InjectFault(daemontest.Fault{Busy: true})
+
+				_, err := client.GetInfo(ctx)
+				assert.True(t, errors.Is(err, rpc.ErrStatusBusy))
+
+				_, err = client.GetHeight(ctx)
+				assert.True(t, errors.Is(err, rpc.ErrStatusBusy))
+			})
+
+			it("lifts faults after the number of times set", func() {
+				server.InjectFault(daemontest.Fault{
+					Methods: []string{"get_info"},
+					Times:   1,
+					Busy:    true,
+				})
+
+				_, err := client.GetHeight(ctx)
+				assert.NoError(t, err)
+
+				_, err = client.GetInfo(ctx)
+				assert.Error(t, err)
+
+				_, err = client.GetInfo(ctx)
+				assert.NoError(t, err)
+			})
+
+			it("replies w/ malformed json", func() {
+				server.InjectFault(daemontest.Fault{MalformedJSON: true})
+
+				_, err := client.GetInfo(ctx)
+				assert.Error(t, err)
+			})
+
+			it("replies w/ http status codes", func() {
+				server.InjectFault(daemontest.Fault{StatusCode: 503})
+
+				_, err := client.GetInfo(ctx)
+
+				var httpErr *rpc.HTTPError
+				require.True(t, errors.As(err, &httpErr))
+				assert.Equal(t, 503, httpErr.StatusCode)
+			})
+
+			it("delays responses", func() {
+				server.InjectFault(daemontest.Fault{Latency: 200 * time.Millisecond})
+
+				ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+				defer cancel()
+
+				_, err := client.GetInfo(ctx)
+				assert.True(t, errors.Is(err, context.DeadlineExceeded))
+			})
+		})
+
+		when("digest auth", func() {
+			it("authenticates the client", func() {
+				authServer := daemontest.NewServer(daemontest.WithDigestAuth("user", "pass"))
+				defer authServer.Close()
+
+				client, err := authServer.NewClient()
+				require.NoError(t, err)
+
+				_, err = client.GetInfo(ctx)
+				assert.NoError(t, err)
+			})
+
+			it("rejects wrong credentials", func() {
+				authServer := daemontest.NewServer(daemontest.WithDigestAuth("user", "pass"))
+				defer authServer.Close()
+
+				httpClient := authServer.Client()
+				httpClient.Transport = mhttp.NewDigestAuthTransport(
+					"user", "wrong", httpClient.Transport,
+				)
+
+				c, err := rpc.NewClient(authServer.URL, rpc.WithHTTPClient(httpClient))
+				require.NoError(t, err)
+
+				_, err = daemon.NewClient(c).GetInfo(ctx)
+				assert.True(t, errors.Is(err, rpc.ErrUnauthorized))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
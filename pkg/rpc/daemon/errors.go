@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RejectionReason is a reason for the daemon to refuse a transaction
+// submitted through SendRawTransaction.
+//
+type RejectionReason string
+
+const (
+	RejectionDoubleSpend       RejectionReason = "double_spend"
+	RejectionFeeTooLow         RejectionReason = "fee_too_low"
+	RejectionInvalidInput      RejectionReason = "invalid_input"
+	RejectionInvalidOutput     RejectionReason = "invalid_output"
+	RejectionLowMixin          RejectionReason = "low_mixin"
+	RejectionNonzeroUnlockTime RejectionReason = "nonzero_unlock_time"
+	RejectionOverspend         RejectionReason = "overspend"
+	RejectionSanityCheckFailed RejectionReason = "sanity_check_failed"
+	RejectionTooBig            RejectionReason = "too_big"
+	RejectionTooFewOutputs     RejectionReason = "too_few_outputs"
+	RejectionTxExtraTooBig     RejectionReason = "tx_extra_too_big"
+)
+
+// Sentinel values for the reasons a transaction might be rejected for, meant
+// to be used with `errors.Is`, for instance:
+//
+// 	if errors.Is(err, daemon.ErrDoubleSpend) {
+// 		// inputs already spent
+// 	}
+//
+var (
+	ErrDoubleSpend       = &TxRejectedError{Reasons: []RejectionReason{RejectionDoubleSpend}}
+	ErrFeeTooLow         = &TxRejectedError{Reasons: []RejectionReason{RejectionFeeTooLow}}
+	ErrInvalidInput      = &TxRejectedError{Reasons: []RejectionReason{RejectionInvalidInput}}
+	ErrInvalidOutput     = &TxRejectedError{Reasons: []RejectionReason{RejectionInvalidOutput}}
+	ErrLowMixin          = &TxRejectedError{Reasons: []RejectionReason{RejectionLowMixin}}
+	ErrNonzeroUnlockTime = &TxRejectedError{Reasons: []RejectionReason{RejectionNonzeroUnlockTime}}
+	ErrOverspend         = &TxRejectedError{Reasons: []RejectionReason{RejectionOverspend}}
+	ErrSanityCheckFailed = &TxRejectedError{Reasons: []RejectionReason{RejectionSanityCheckFailed}}
+	ErrTooBig            = &TxRejectedError{Reasons: []RejectionReason{RejectionTooBig}}
+	ErrTooFewOutputs     = &TxRejectedError{Reasons: []RejectionReason{RejectionTooFewOutputs}}
+	ErrTxExtraTooBig     = &TxRejectedError{Reasons: []RejectionReason{RejectionTxExtraTooBig}}
+)
+
+// ErrReorgTooDeep is the error that a ChainWalker fails with when the chain
+// got reorganized past the oldest of the blocks it remembers, leaving it
+// unable to find the fork point to roll back to.
+//
+var ErrReorgTooDeep = errors.New("reorg deeper than the blocks remembered")
+
+// ErrHashMismatch is the error that a client with verification enabled (see
+// `WithVerification`) fails with when the transactions or blocks the daemon
+// responds with don't hash to what it claims they do.
+//
+var ErrHashMismatch = errors.New("hash mismatch")
+
+// TxRejectedError is the error returned when the daemon refuses a transaction
+// submitted through SendRawTransaction.
+//
+type TxRejectedError struct {
+	// Reasons are the reasons for the rejection, derived from the flags
+	// set in the response (might be empty if the daemon didn't set any).
+	//
+	Reasons []RejectionReason
+
+	// Reason is the human-readable reason given by the daemon, if any.
+	//
+	Reason string
+
+	// Result is the full response from the daemon.
+	//
+	Result *SendRawTransactionResult
+
+	// err is the status error that the rejection has been surfaced as.
+	//
+	err error
+}
+
+func (e *TxRejectedError) Error() string {
+	reasons := make([]string, len(e.Reasons))
+	for idx, reason := range e.Reasons {
+		reasons[idx] = string(reason)
+	}
+
+	return fmt.Sprintf("tx rejected: reasons=[%s] reason=%q",
+		strings.Join(reasons, ","), e.Reason)
+}
+
+// Is reports whether `target` is a `*TxRejectedError` whose reasons are all
+// among the ones of this error, allowing the sentinel values (e.g.,
+// `ErrDoubleSpend`) to be matched regardless of any other reasons.
+//
+func (e *TxRejectedError) Is(target error) bool {
+	t, ok := target.(*TxRejectedError)
+	if !ok {
+		return false
+	}
+
+	for _, want := range t.Reasons {
+		if !e.HasReason(want) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Unwrap gives the status error that the rejection has been surfaced as by
+// the rpc client (e.g., `rpc.ErrStatusFailed`).
+//
+func (e *TxRejectedError) Unwrap() error {
+	return e.err
+}
+
+// HasReason tells whether `reason` is one of the reasons for the rejection.
+//
+func (e *TxRejectedError) HasReason(reason RejectionReason) bool {
+	for _, r := range e.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+
+	return false
+}
@@ -4,9 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"unicode/utf8"
 )
 
 const (
+	methodAddAuxPow              = "add_aux_pow"
+	methodBanned                 = "banned"
+	methodCalcPow                = "calc_pow"
+	methodFlushCache             = "flush_cache"
+	methodFlushTxpool            = "flush_txpool"
 	methodGenerateBlocks         = "generateblocks"
 	methodGetAlternateChains     = "get_alternate_chains"
 	methodGetBans                = "get_bans"
@@ -21,12 +28,24 @@ const (
 	methodGetFeeEstimate         = "get_fee_estimate"
 	methodGetInfo                = "get_info"
 	methodGetLastBlockHeader     = "get_last_block_header"
+	methodGetMinerData           = "get_miner_data"
+	methodGetOutputDistribution  = "get_output_distribution"
+	methodGetOutputHistogram     = "get_output_histogram"
+	methodGetTxpoolBacklog       = "get_txpool_backlog"
 	methodGetVersion             = "get_version"
 	methodHardForkInfo           = "hard_fork_info"
 	methodOnGetBlockHash         = "on_get_block_hash"
+	methodPruneBlockchain        = "prune_blockchain"
+	methodRPCAccessAccount       = "rpc_access_account"
+	methodRPCAccessData          = "rpc_access_data"
+	methodRPCAccessInfo          = "rpc_access_info"
+	methodRPCAccessPay           = "rpc_access_pay"
+	methodRPCAccessSubmitNonce   = "rpc_access_submit_nonce"
 	methodRPCAccessTracking      = "rpc_access_tracking"
 	methodRelayTx                = "relay_tx"
 	methodSetBans                = "set_bans"
+	methodSetBootstrapDaemon     = "set_bootstrap_daemon"
+	methodSubmitBlock            = "submit_block"
 	methodSyncInfo               = "sync_info"
 )
 
@@ -222,16 +241,42 @@ func (c *Client) RelayTx(
 	return resp, nil
 }
 
+// GetBlockTemplateRequestParameters is the set of parameters to be passed to
+// the GetBlockTemplate RPC method.
+//
+type GetBlockTemplateRequestParameters struct {
+	// WalletAddress is the address of the wallet to receive the coinbase
+	// transaction's outputs if the block is successfully mined.
+	//
+	WalletAddress string `json:"wallet_address"`
+
+	// ReserveSize is the number of bytes to reserve in the coinbase
+	// transaction's extra field (at `ReservedOffset` in the template) for
+	// miners to fill with an extra nonce (max 255).
+	//
+	// Can't be set together with `ExtraNonce`.
+	//
+	ReserveSize uint `json:"reserve_size,omitempty"`
+
+	// ExtraNonce is a hex-encoded extra nonce to be placed in the
+	// coinbase transaction's extra field right away.
+	//
+	// Can't be set together with `ReserveSize`.
+	//
+	ExtraNonce string `json:"extra_nonce,omitempty"`
+
+	// PreviousBlock is the hash of the block to mine on top of (default:
+	// the top of the chain).
+	//
+	PreviousBlock string `json:"prev_block,omitempty"`
+}
+
 // GetBlockTemplate gets a block template on which mining a new block.
 //
 func (c *Client) GetBlockTemplate(
-	ctx context.Context, walletAddress string, reserveSize uint,
+	ctx context.Context, params GetBlockTemplateRequestParameters,
 ) (*GetBlockTemplateResult, error) {
 	resp := &GetBlockTemplateResult{}
-	params := map[string]interface{}{
-		"wallet_address": walletAddress,
-		"reserve_size":   reserveSize,
-	}
 
 	err := c.JSONRPC(ctx, methodGetBlockTemplate, params, resp)
 	if err != nil {
@@ -241,6 +286,123 @@ func (c *Client) GetBlockTemplate(
 	return resp, nil
 }
 
+// SubmitBlock submits mined blocks (hex-encoded blobs) to the network.
+//
+// Blocks that the daemon refuses are reported as `*rpc.Error`s, matching
+// `rpc.ErrWrongBlockblob` when not even parsed, and `rpc.ErrBlockNotAccepted`
+// when deemed invalid (e.g., not meeting the difficulty, or mined on top of
+// a stale block).
+//
+func (c *Client) SubmitBlock(
+	ctx context.Context, blobs ...string,
+) (*SubmitBlockResult, error) {
+	resp := &SubmitBlockResult{}
+
+	err := c.JSONRPC(ctx, methodSubmitBlock, blobs, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetMinerData retrieves the data needed for building a block template
+// locally (e.g., by a pool using its own template builder), being a lighter
+// way than GetBlockTemplate of knowing when to refresh templates.
+//
+func (c *Client) GetMinerData(ctx context.Context) (*GetMinerDataResult, error) {
+	resp := &GetMinerDataResult{}
+
+	err := c.JSONRPC(ctx, methodGetMinerData, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CalcPowRequestParameters is the set of parameters to be passed to the
+// CalcPow RPC method.
+//
+type CalcPowRequestParameters struct {
+	// MajorVersion is the major version of the block.
+	//
+	MajorVersion uint8 `json:"major_version"`
+
+	// Height is the height of the block.
+	//
+	Height uint64 `json:"height"`
+
+	// BlockBlob is the hex-encoded hashing blob of the block.
+	//
+	BlockBlob string `json:"block_blob"`
+
+	// SeedHash is the RandomX seed hash to use, if any.
+	//
+	SeedHash string `json:"seed_hash,omitempty"`
+}
+
+// CalcPow calculates the proof-of-work hash of a block, giving it back
+// hex-encoded.
+//
+// (restricted).
+//
+func (c *Client) CalcPow(
+	ctx context.Context, params CalcPowRequestParameters,
+) (string, error) {
+	var hash string
+
+	err := c.JSONRPC(ctx, methodCalcPow, params, &hash)
+	if err != nil {
+		return "", fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return hash, nil
+}
+
+// AuxPow is a merge-mined chain's block to be committed to in a block
+// template.
+//
+type AuxPow struct {
+	// ID is the unique identifier of the merge-mined chain.
+	//
+	ID string `json:"id"`
+
+	// Hash is the hash of the merge-mined chain's block.
+	//
+	Hash string `json:"hash"`
+}
+
+// AddAuxPowRequestParameters is the set of parameters to be passed to the
+// AddAuxPow RPC method.
+//
+type AddAuxPowRequestParameters struct {
+	// BlocktemplateBlob is the block template to add the merge mining
+	// commitment to.
+	//
+	BlocktemplateBlob string `json:"blocktemplate_blob"`
+
+	// AuxPow are the merge-mined chains' blocks to commit to.
+	//
+	AuxPow []AuxPow `json:"aux_pow"`
+}
+
+// AddAuxPow adds a merge mining commitment (the merkle root of the blocks of
+// the merge-mined chains) to a block template.
+//
+func (c *Client) AddAuxPow(
+	ctx context.Context, params AddAuxPowRequestParameters,
+) (*AddAuxPowResult, error) {
+	resp := &AddAuxPowResult{}
+
+	err := c.JSONRPC(ctx, methodAddAuxPow, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
 func (c *Client) GetConnections(
 	ctx context.Context,
 ) (*GetConnectionsResult, error) {
@@ -386,6 +548,12 @@ func (c *Client) GetBlock(
 		return nil, fmt.Errorf("jsonrpc: %w", err)
 	}
 
+	if c.verify {
+		if err := resp.Verify(); err != nil {
+			return nil, fmt.Errorf("verify: %w", err)
+		}
+	}
+
 	return resp, nil
 }
 
@@ -415,3 +583,365 @@ func (c *Client) SyncInfo(ctx context.Context) (*SyncInfoResult, error) {
 
 	return resp, nil
 }
+
+// RPCAccessInfoRequestParameters is the set of parameters to be passed to the
+// RPCAccessInfo RPC method.
+//
+type RPCAccessInfoRequestParameters struct {
+	// Client is the signature identifying the client (see
+	// `monero.RPCPaymentSignature`). When making requests through a
+	// Payer, it's filled automatically if left empty.
+	//
+	Client string `json:"client,omitempty"`
+}
+
+// RPCAccessInfo retrieves the information needed for earning credits from a
+// node offering rpc for credits (i.e., mining to it), as well as the current
+// balance of the client.
+//
+func (c *Client) RPCAccessInfo(
+	ctx context.Context, params RPCAccessInfoRequestParameters,
+) (*RPCAccessInfoResult, error) {
+	resp := &RPCAccessInfoResult{}
+
+	err := c.JSONRPC(ctx, methodRPCAccessInfo, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessSubmitNonceRequestParameters is the set of parameters to be passed
+// to the RPCAccessSubmitNonce RPC method.
+//
+type RPCAccessSubmitNonceRequestParameters struct {
+	// Client is the signature identifying the client (see
+	// `RPCAccessInfoRequestParameters`).
+	//
+	Client string `json:"client,omitempty"`
+
+	// Nonce is the nonce that, once set in the hashing blob, gives a hash
+	// meeting the difficulty.
+	//
+	Nonce uint32 `json:"nonce"`
+
+	// Cookie is the cookie from the `RPCAccessInfo` call that the hashing
+	// blob came from.
+	//
+	Cookie uint32 `json:"cookie"`
+}
+
+// RPCAccessSubmitNonce submits a nonce found for the hashing blob given by
+// `RPCAccessInfo`, crediting the client if valid.
+//
+func (c *Client) RPCAccessSubmitNonce(
+	ctx context.Context, params RPCAccessSubmitNonceRequestParameters,
+) (*RPCAccessSubmitNonceResult, error) {
+	resp := &RPCAccessSubmitNonceResult{}
+
+	err := c.JSONRPC(ctx, methodRPCAccessSubmitNonce, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessPayRequestParameters is the set of parameters to be passed to the
+// RPCAccessPay RPC method.
+//
+type RPCAccessPayRequestParameters struct {
+	// Client is the signature identifying the client (see
+	// `RPCAccessInfoRequestParameters`).
+	//
+	Client string `json:"client,omitempty"`
+
+	// PayingFor is a description of what's being paid for.
+	//
+	PayingFor string `json:"paying_for"`
+
+	// Payment is the number of credits to pay.
+	//
+	Payment uint64 `json:"payment"`
+}
+
+// RPCAccessPay pays the node with credits from the client's balance.
+//
+func (c *Client) RPCAccessPay(
+	ctx context.Context, params RPCAccessPayRequestParameters,
+) (*RPCAccessPayResult, error) {
+	resp := &RPCAccessPayResult{}
+
+	err := c.JSONRPC(ctx, methodRPCAccessPay, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessAccountRequestParameters is the set of parameters to be passed to
+// the RPCAccessAccount RPC method.
+//
+type RPCAccessAccountRequestParameters struct {
+	// Client is the signature identifying the client whose account is
+	// being looked up (see `RPCAccessInfoRequestParameters`).
+	//
+	Client string `json:"client,omitempty"`
+
+	// DeltaBalance is the number of credits to add to (or remove from,
+	// if negative) the client's balance.
+	//
+	DeltaBalance int64 `json:"delta_balance,omitempty"`
+}
+
+// RPCAccessAccount retrieves the balance of a client, optionally adjusting it.
+//
+// (restricted).
+//
+func (c *Client) RPCAccessAccount(
+	ctx context.Context, params RPCAccessAccountRequestParameters,
+) (*RPCAccessAccountResult, error) {
+	resp := &RPCAccessAccountResult{}
+
+	err := c.JSONRPC(ctx, methodRPCAccessAccount, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessData retrieves the accounts of all of the clients that the node
+// knows about.
+//
+// (restricted).
+//
+func (c *Client) RPCAccessData(ctx context.Context) (*RPCAccessDataResult, error) {
+	resp := &RPCAccessDataResult{}
+
+	err := c.JSONRPC(ctx, methodRPCAccessData, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FlushTxpool drops transactions from the pool (all of them if no
+// transaction id is given).
+//
+// (restricted).
+//
+func (c *Client) FlushTxpool(
+	ctx context.Context, params FlushTxpoolRequestParameters,
+) (*FlushTxpoolResult, error) {
+	resp := &FlushTxpoolResult{}
+
+	err := c.JSONRPC(ctx, methodFlushTxpool, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FlushCache flushes the caches of transactions and blocks that the daemon
+// deemed invalid, having it reconsider them.
+//
+// (restricted).
+//
+func (c *Client) FlushCache(
+	ctx context.Context, params FlushCacheRequestParameters,
+) (*FlushCacheResult, error) {
+	resp := &FlushCacheResult{}
+
+	err := c.JSONRPC(ctx, methodFlushCache, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PruneBlockchain prunes the chain, or only reports whether it is when
+// `Check` is set.
+//
+// (restricted).
+//
+func (c *Client) PruneBlockchain(
+	ctx context.Context, params PruneBlockchainRequestParameters,
+) (*PruneBlockchainResult, error) {
+	resp := &PruneBlockchainResult{}
+
+	err := c.JSONRPC(ctx, methodPruneBlockchain, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTxpoolBacklog retrieves the weight, fee and time spent in the pool of
+// each of the transactions in the pool.
+//
+func (c *Client) GetTxpoolBacklog(ctx context.Context) (*GetTxpoolBacklogResult, error) {
+	resp := &GetTxpoolBacklogResult{}
+
+	err := c.JSONRPC(ctx, methodGetTxpoolBacklog, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Banned checks whether an address is banned, and for how long.
+//
+// (restricted).
+//
+func (c *Client) Banned(ctx context.Context, address string) (*BannedResult, error) {
+	resp := &BannedResult{}
+	params := map[string]string{
+		"address": address,
+	}
+
+	err := c.JSONRPC(ctx, methodBanned, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SetBootstrapDaemon configures the daemon that the node relies on for
+// serving requests while it's syncing.
+//
+// (restricted).
+//
+func (c *Client) SetBootstrapDaemon(
+	ctx context.Context, params SetBootstrapDaemonRequestParameters,
+) (*SetBootstrapDaemonResult, error) {
+	resp := &SetBootstrapDaemonResult{}
+
+	err := c.JSONRPC(ctx, methodSetBootstrapDaemon, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputHistogram retrieves, for each amount, how many outputs of that
+// amount exist in the chain (useful for picking decoys amongst pre-RingCT
+// outputs, or assessing how many of them are spendable).
+//
+func (c *Client) GetOutputHistogram(
+	ctx context.Context, params GetOutputHistogramRequestParameters,
+) (*GetOutputHistogramResult, error) {
+	resp := &GetOutputHistogramResult{}
+
+	err := c.JSONRPC(ctx, methodGetOutputHistogram, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputDistribution retrieves, for each of the amounts supplied, the
+// number of outputs created at each height between `FromHeight` and
+// `ToHeight` (0 for the top of the chain).
+//
+// Distributions requested in binary form (optionally compressed) are
+// decoded back into plain integers.
+//
+func (c *Client) GetOutputDistribution(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionResult, error) {
+	resp := &GetOutputDistributionResult{}
+
+	err := c.JSONRPC(ctx, methodGetOutputDistribution, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// unquoteBlob decodes a binary blob that the daemon embedded in a JSON
+// string.
+//
+// The daemon doesn't encode blobs in any way: it only escapes quotes,
+// backslashes and control characters, leaving every other byte as is (thus
+// not necessarily valid UTF-8), which rules out `json.Unmarshal` as it
+// replaces invalid sequences.
+//
+func unquoteBlob(raw json.RawMessage) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return nil, fmt.Errorf("not a string")
+	}
+
+	raw = raw[1 : len(raw)-1]
+	res := make([]byte, 0, len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			res = append(res, raw[i])
+			continue
+		}
+
+		i++
+		if i == len(raw) {
+			return nil, fmt.Errorf("truncated escape sequence")
+		}
+
+		switch raw[i] {
+		case '"', '\\', '/':
+			res = append(res, raw[i])
+		case 'b':
+			res = append(res, '\b')
+		case 'f':
+			res = append(res, '\f')
+		case 'n':
+			res = append(res, '\n')
+		case 'r':
+			res = append(res, '\r')
+		case 't':
+			res = append(res, '\t')
+		case 'v':
+			res = append(res, '\v')
+		case 'u':
+			if i+4 >= len(raw) {
+				return nil, fmt.Errorf("truncated escape sequence")
+			}
+
+			v, err := strconv.ParseUint(string(raw[i+1:i+5]), 16, 16)
+			if err != nil {
+				return nil, fmt.Errorf("escape sequence: %w", err)
+			}
+
+			// escaped bytes come as \u00XX, anything wider being a
+			// character that was re-escaped on the way (e.g., U+2028
+			// by Go's encoder) to be put back as UTF-8.
+			//
+			if v <= 0xff {
+				res = append(res, byte(v))
+			} else {
+				var buf [utf8.UTFMax]byte
+				res = append(res, buf[:utf8.EncodeRune(buf[:], rune(v))]...)
+			}
+
+			i += 4
+		default:
+			return nil, fmt.Errorf("invalid escape sequence '\\%c'", raw[i])
+		}
+	}
+
+	return res, nil
+}
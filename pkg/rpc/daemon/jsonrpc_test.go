@@ -0,0 +1,452 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestMining(t *testing.T) {
+	spec.Run(t, "Mining", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(10))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("mines a block from a template with an extra nonce", func() {
+			template, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+				WalletAddress: "address",
+				ReserveSize:   8,
+			})
+			require.NoError(t, err)
+			assert.EqualValues(t, 10, template.Height)
+
+			blob, err := template.WithExtraNonce([]byte{0xca, 0xfe})
+			require.NoError(t, err)
+			assert.Len(t, blob, len(template.BlocktemplateBlob))
+			assert.Equal(t, "cafe", blob[template.ReservedOffset*2:template.ReservedOffset*2+4])
+
+			resp, err := client.SubmitBlock(ctx, blob)
+			require.NoError(t, err)
+
+			header, err := client.GetLastBlockHeader(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, resp.BlockID, header.BlockHeader.Hash)
+			assert.EqualValues(t, 10, header.BlockHeader.Height)
+		})
+
+		it("refuses an extra nonce not fitting in the blob", func() {
+			template, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+				WalletAddress: "address",
+				ReserveSize:   2,
+			})
+			require.NoError(t, err)
+
+			_, err = template.WithExtraNonce([]byte{1, 2, 3})
+			assert.Error(t, err)
+		})
+
+		it("rejects stale blocks", func() {
+			template, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+				WalletAddress: "address",
+			})
+			require.NoError(t, err)
+
+			server.GenerateBlocks(1)
+
+			_, err = client.SubmitBlock(ctx, template.BlocktemplateBlob)
+			assert.ErrorIs(t, err, rpc.ErrBlockNotAccepted)
+		})
+
+		it("rejects malformed blocks", func() {
+			_, err := client.SubmitBlock(ctx, "not hex")
+			assert.ErrorIs(t, err, rpc.ErrWrongBlockblob)
+		})
+
+		it("builds templates with an extra nonce on top of a previous block", func() {
+			prev, err := client.GetBlockHeaderByHeight(ctx, 5)
+			require.NoError(t, err)
+
+			template, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+				WalletAddress: "address",
+				ExtraNonce:    "beef",
+				PreviousBlock: prev.BlockHeader.Hash,
+			})
+			require.NoError(t, err)
+
+			assert.EqualValues(t, 6, template.Height)
+			assert.Equal(t, prev.BlockHeader.Hash, template.PrevHash)
+			assert.Equal(t, "beef", template.BlocktemplateBlob[template.ReservedOffset*2:])
+		})
+
+		it("gives miner data", func() {
+			server.AddTx(daemontest.Tx{Hash: "aa", Fee: 10, Weight: 1500})
+
+			resp, err := client.GetMinerData(ctx)
+			require.NoError(t, err)
+
+			header, err := client.GetLastBlockHeader(ctx)
+			require.NoError(t, err)
+
+			assert.EqualValues(t, 10, resp.Height)
+			assert.Equal(t, header.BlockHeader.Hash, resp.PrevID)
+			assert.Equal(t, []daemon.MinerDataTx{
+				{ID: "aa", Weight: 1500, Fee: 10},
+			}, resp.TxBacklog)
+		})
+
+		it("calculates pow hashes", func() {
+			hash, err := client.CalcPow(ctx, daemon.CalcPowRequestParameters{
+				MajorVersion: 16,
+				Height:       10,
+				BlockBlob:    "00",
+			})
+			require.NoError(t, err)
+			assert.Len(t, hash, 64)
+		})
+
+		it("adds aux pow", func() {
+			template, err := client.GetBlockTemplate(ctx, daemon.GetBlockTemplateRequestParameters{
+				WalletAddress: "address",
+			})
+			require.NoError(t, err)
+
+			resp, err := client.AddAuxPow(ctx, daemon.AddAuxPowRequestParameters{
+				BlocktemplateBlob: template.BlocktemplateBlob,
+				AuxPow: []daemon.AuxPow{
+					{ID: "01", Hash: "02"},
+					{ID: "03", Hash: "04"},
+					{ID: "05", Hash: "06"},
+				},
+			})
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, resp.MerkleTreeDepth)
+			assert.Len(t, resp.AuxPow, 3)
+			assert.NotEmpty(t, resp.MerkleRoot)
+		})
+	}, spec.Report(report.Terminal{}))
+}
+
+// nolint:funlen
+func TestOutputDistribution(t *testing.T) {
+	spec.Run(t, "OutputDistribution", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(4))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+
+			server.AddTx(daemontest.Tx{Hash: "aa"})
+			server.AddTx(daemontest.Tx{Hash: "bb"})
+			server.GenerateBlocks(1)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		for _, tc := range []struct {
+			name     string
+			binary   bool
+			compress bool
+		}{
+			{name: "plain"},
+			{name: "binary", binary: true},
+			{name: "compressed", binary: true, compress: true},
+		} {
+			tc := tc
+
+			it("decodes "+tc.name+" distributions", func() {
+				resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+					Amounts:    []uint64{0, 1000},
+					FromHeight: 1,
+					Binary:     tc.binary,
+					Compress:   tc.compress,
+				})
+				require.NoError(t, err)
+
+				assert.Equal(t, []daemon.Distribution{
+					{
+						Amount:       0,
+						StartHeight:  1,
+						Base:         1,
+						Distribution: []uint64{1, 1, 1, 5},
+					},
+					{
+						Amount:       1000,
+						StartHeight:  1,
+						Base:         1,
+						Distribution: []uint64{0, 1, 0, 1},
+					},
+				}, resp.Distributions)
+			})
+		}
+
+		it("gives cumulative distributions", func() {
+			resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+				Amounts:    []uint64{0},
+				FromHeight: 2,
+				ToHeight:   3,
+				Cumulative: true,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, resp.Distributions, 1)
+			assert.EqualValues(t, 2, resp.Distributions[0].Base)
+			assert.Equal(t, []uint64{3, 4}, resp.Distributions[0].Distribution)
+		})
+
+		it("fails for heights past the top", func() {
+			_, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+				Amounts:  []uint64{0},
+				ToHeight: 10,
+			})
+			assert.ErrorIs(t, err, rpc.ErrInternalError)
+		})
+
+		it("decodes blobs not being valid utf-8", func() {
+			// 0x80 (not valid utf-8 on its own), a quote and a backslash
+			// sent as is, then control characters escaped.
+			//
+			body := []byte("{\"binary\":true,\"distribution\":\"" +
+				"\x80\\\"\\\\\\u0001" + strings.Repeat("\\u0000", 4) + "\"}")
+
+			d := &daemon.Distribution{}
+			require.NoError(t, json.Unmarshal(body, d))
+			assert.Equal(t, []uint64{0x015c2280}, d.Distribution)
+		})
+
+		it("gives the histogram of outputs", func() {
+			server.GenerateBlocks(10)
+
+			// blocks are 2 minutes apart, from the epoch on.
+			//
+			resp, err := client.GetOutputHistogram(ctx, daemon.GetOutputHistogramRequestParameters{
+				Amounts:      []uint64{0, 1000},
+				RecentCutoff: 10 * 120,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, []daemon.HistogramEntry{
+				{Amount: 0, TotalInstances: 19, UnlockedInstances: 9, RecentInstances: 5},
+				{Amount: 1000, TotalInstances: 8, UnlockedInstances: 3, RecentInstances: 3},
+			}, resp.Histogram)
+
+			resp, err = client.GetOutputHistogram(ctx, daemon.GetOutputHistogramRequestParameters{
+				Amounts:  []uint64{0},
+				Unlocked: true,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, resp.Histogram, 1)
+			assert.EqualValues(t, 9, resp.Histogram[0].TotalInstances)
+		})
+
+		it("filters histogram entries", func() {
+			resp, err := client.GetOutputHistogram(ctx, daemon.GetOutputHistogramRequestParameters{
+				Amounts:  []uint64{0, 1000},
+				MinCount: 4,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, resp.Histogram, 1)
+			assert.EqualValues(t, 0, resp.Histogram[0].Amount)
+		})
+	}, spec.Report(report.Terminal{}))
+}
+
+// nolint:funlen
+func TestNodeAdministration(t *testing.T) {
+	spec.Run(t, "NodeAdministration", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(10))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("pops blocks, sending their txs back to the pool", func() {
+			server.AddTx(daemontest.Tx{Hash: "aa"})
+			server.GenerateBlocks(2)
+
+			resp, err := client.PopBlocks(ctx, daemon.PopBlocksRequestParameters{NBlocks: 3})
+			require.NoError(t, err)
+			assert.EqualValues(t, 9, resp.Height)
+
+			pool, err := client.GetTransactionPoolHashes(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"aa"}, pool.TxHashes)
+		})
+
+		it("flushes the pool", func() {
+			for _, hash := range []string{"aa", "bb", "cc"} {
+				server.AddTx(daemontest.Tx{Hash: hash})
+			}
+
+			_, err := client.FlushTxpool(ctx, daemon.FlushTxpoolRequestParameters{
+				TxIDs: []string{"bb"},
+			})
+			require.NoError(t, err)
+
+			pool, err := client.GetTransactionPoolHashes(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"aa", "cc"}, pool.TxHashes)
+
+			_, err = client.FlushTxpool(ctx, daemon.FlushTxpoolRequestParameters{})
+			require.NoError(t, err)
+
+			pool, err = client.GetTransactionPoolHashes(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, pool.TxHashes)
+		})
+
+		it("decodes the packed txpool backlog", func() {
+			server.AddTx(daemontest.Tx{Hash: "aa", Weight: 1500, Fee: 30})
+			server.AddTx(daemontest.Tx{Hash: "bb", Weight: 0x2280, Fee: 0x0a})
+
+			resp, err := client.GetTxpoolBacklog(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, []daemon.TxpoolBacklogEntry{
+				{Weight: 1500, Fee: 30},
+				{Weight: 0x2280, Fee: 0x0a},
+			}, resp.Backlog)
+
+			b, err := json.Marshal(resp)
+			require.NoError(t, err)
+
+			decoded := &daemon.GetTxpoolBacklogResult{}
+			require.NoError(t, json.Unmarshal(b, decoded))
+			assert.Equal(t, resp, decoded)
+		})
+
+		it("lists alternative blocks", func() {
+			dropped := server.GenerateBlocks(2)
+			server.Reorg(2, 3)
+
+			resp, err := client.GetAltBlocksHashes(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, dropped, resp.BlksHashes)
+		})
+
+		it("prunes the chain", func() {
+			resp, err := client.PruneBlockchain(ctx, daemon.PruneBlockchainRequestParameters{Check: true})
+			require.NoError(t, err)
+			assert.False(t, resp.Pruned)
+
+			resp, err = client.PruneBlockchain(ctx, daemon.PruneBlockchainRequestParameters{})
+			require.NoError(t, err)
+			assert.True(t, resp.Pruned)
+			assert.NotZero(t, resp.PruningSeed)
+		})
+
+		it("limits peers", func() {
+			in, err := client.InPeers(ctx, daemon.InPeersRequestParameters{Set: true, InPeers: 5})
+			require.NoError(t, err)
+			assert.EqualValues(t, 5, in.InPeers)
+
+			out, err := client.OutPeers(ctx, daemon.OutPeersRequestParameters{})
+			require.NoError(t, err)
+			assert.EqualValues(t, 12, out.OutPeers)
+
+			in, err = client.InPeers(ctx, daemon.InPeersRequestParameters{})
+			require.NoError(t, err)
+			assert.EqualValues(t, 5, in.InPeers)
+		})
+
+		it("tells whether an address is banned", func() {
+			server.Ban("1.2.3.4", time.Hour)
+
+			resp, err := client.Banned(ctx, "1.2.3.4")
+			require.NoError(t, err)
+			assert.True(t, resp.Banned)
+			assert.NotZero(t, resp.Seconds)
+
+			resp, err = client.Banned(ctx, "4.3.2.1")
+			require.NoError(t, err)
+			assert.False(t, resp.Banned)
+		})
+
+		it("sets the bootstrap daemon", func() {
+			_, err := client.SetBootstrapDaemon(ctx, daemon.SetBootstrapDaemonRequestParameters{
+				Address: "node.example.com:18081",
+			})
+			require.NoError(t, err)
+
+			info, err := client.GetInfo(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "node.example.com:18081", info.BootstrapDaemonAddress)
+		})
+
+		it("checks for updates", func() {
+			resp, err := client.Update(ctx, daemon.UpdateRequestParameters{
+				Command: daemon.UpdateCheck,
+			})
+			require.NoError(t, err)
+			assert.True(t, resp.Update)
+			assert.NotEmpty(t, resp.Version)
+			assert.Empty(t, resp.Path)
+
+			resp, err = client.Update(ctx, daemon.UpdateRequestParameters{
+				Command: daemon.UpdateDownload,
+				Path:    "/tmp/update",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "/tmp/update", resp.Path)
+		})
+
+		it("saves the chain and flushes caches", func() {
+			_, err := client.SaveBc(ctx)
+			require.NoError(t, err)
+
+			_, err = client.FlushCache(ctx, daemon.FlushCacheRequestParameters{BadTxs: true})
+			require.NoError(t, err)
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,641 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// DefaultMonitorWindow is the default number of recent blocks that a
+// ReorgMonitor remembers.
+//
+const DefaultMonitorWindow = 100
+
+// ChainMain is a notification of blocks added to the main chain, as
+// published by monerod over zmq (`json-minimal-chain_main`), with the first
+// of those blocks building on top of `FirstPrevID`.
+//
+type ChainMain struct {
+	FirstHeight uint64   `json:"first_height"`
+	FirstPrevID string   `json:"first_prev_id"`
+	IDs         []string `json:"ids"`
+}
+
+// NewTip tells that the main chain has a new top block.
+//
+type NewTip struct {
+	// Block is the new top block.
+	//
+	Block BlockID `json:"block"`
+}
+
+// Reorg tells that blocks of the main chain got replaced by those of a
+// competing one.
+//
+type Reorg struct {
+	// ForkHeight is the height of the last block that both the old and
+	// the new chain have in common.
+	//
+	ForkHeight uint64 `json:"fork_height"`
+
+	// Depth is the number of blocks that got orphaned.
+	//
+	Depth uint64 `json:"depth"`
+
+	// Orphaned are the blocks no longer part of the main chain, oldest
+	// first.
+	//
+	Orphaned []BlockID `json:"orphaned"`
+}
+
+// ChainEvent is what a ReorgMonitor delivers, with a single one of its fields
+// set.
+//
+type ChainEvent struct {
+	// NewTip is set when the main chain has a new top block.
+	//
+	NewTip *NewTip `json:"new_tip,omitempty"`
+
+	// Reorg is set when blocks of the main chain got orphaned (it's then
+	// followed by a NewTip event for the top of the new chain).
+	//
+	Reorg *Reorg `json:"reorg,omitempty"`
+
+	// AltChain is set when an alternative chain not seen before (or one
+	// that grew) is reported by the node.
+	//
+	AltChain *AlternateChain `json:"alt_chain,omitempty"`
+}
+
+// ChainEventFunc is called by a ReorgMonitor for every event, one at a time,
+// in order. Returning an error stops the monitor.
+//
+type ChainEventFunc func(ctx context.Context, event ChainEvent) error
+
+type monitorOptions struct {
+	window       int
+	pollInterval time.Duration
+	chainMain    <-chan ChainMain
+}
+
+// MonitorOption defines a functional option for overriding optional reorg
+// monitor configuration parameters.
+//
+type MonitorOption func(o *monitorOptions)
+
+// WithMonitorWindow sets how many of the most recent blocks are remembered
+// (default: `DefaultMonitorWindow`). Reorgs deeper than that fail the monitor
+// with `ErrReorgTooDeep`, and alternative chains whose top is older than that
+// are forgotten about.
+//
+func WithMonitorWindow(v int) MonitorOption {
+	return func(o *monitorOptions) {
+		o.window = v
+	}
+}
+
+// WithMonitorPollInterval sets how often the node is polled for changes to
+// the main and alternative chains (default: `DefaultPollInterval`).
+//
+func WithMonitorPollInterval(v time.Duration) MonitorOption {
+	return func(o *monitorOptions) {
+		o.pollInterval = v
+	}
+}
+
+// WithChainMain has the monitor consume chain_main notifications (e.g., from
+// `zmq.MinimalChainMain` events) for learning about new blocks as soon as
+// they're added rather than on the next poll.
+//
+func WithChainMain(v <-chan ChainMain) MonitorOption {
+	return func(o *monitorOptions) {
+		o.chainMain = v
+	}
+}
+
+// ReorgMonitor keeps track of the tip of the main chain and of the
+// alternative chains seen by a node, reporting new blocks, reorgs (along with
+// their depth and the blocks orphaned) and alternative chains as they appear.
+//
+type ReorgMonitor struct {
+	client *Client
+	opts   monitorOptions
+
+	mu     sync.Mutex
+	window []BlockID
+	alts   []AlternateChain
+
+	// seen maps the top blocks of the alternative chains already reported
+	// to their heights, so that they can be forgotten about once they fall
+	// out of the window.
+	//
+	seen map[string]uint64
+}
+
+// NewReorgMonitor instantiates a new ReorgMonitor that polls the node
+// through the client supplied.
+//
+func NewReorgMonitor(client *Client, opts ...MonitorOption) *ReorgMonitor {
+	options := monitorOptions{
+		window:       DefaultMonitorWindow,
+		pollInterval: DefaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.window < 1 {
+		options.window = 1
+	}
+
+	return &ReorgMonitor{
+		client: client,
+		opts:   options,
+		seen:   map[string]uint64{},
+	}
+}
+
+// Window gives the most recent blocks of the main chain, oldest first.
+//
+func (m *ReorgMonitor) Window() []BlockID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]BlockID{}, m.window...)
+}
+
+// AltChains gives the alternative chains last reported by the node.
+//
+func (m *ReorgMonitor) AltChains() []AlternateChain {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]AlternateChain{}, m.alts...)
+}
+
+// Watch monitors the node, calling `fn` for every event, until either `fn`
+// fails or the context is done.
+//
+// The first poll only takes note of the current state of the chains (no
+// events are delivered for it).
+//
+func (m *ReorgMonitor) Watch(ctx context.Context, fn ChainEventFunc) error {
+	chainMain := m.opts.chainMain
+
+	events, err := m.Poll(ctx)
+
+	for {
+		if err != nil {
+			return err
+		}
+
+		if err := deliver(ctx, fn, events); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(m.opts.pollInterval):
+			events, err = m.Poll(ctx)
+		case notification, ok := <-chainMain:
+			if !ok {
+				chainMain = nil
+				events = nil
+
+				continue
+			}
+
+			events, err = m.ChainMain(ctx, notification)
+		}
+	}
+}
+
+// Poll checks the node for changes to the main and alternative chains since
+// the last time it was checked, giving the events that resulted from them.
+//
+func (m *ReorgMonitor) Poll(ctx context.Context) ([]ChainEvent, error) {
+	resp, err := m.client.GetLastBlockHeader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get last block header: %w", err)
+	}
+
+	tip := resp.BlockHeader
+	events := []ChainEvent{}
+
+	top, found := m.top()
+	if !found {
+		return events, m.prime(ctx, tip)
+	}
+
+	if tip.Hash != top.Hash {
+		events, err = m.follow(ctx, tip.Height)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	alts, err := m.altChains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, alts...), nil
+}
+
+// ChainMain takes note of the blocks added to the main chain that a
+// chain_main notification refers to, giving the events that resulted from
+// them.
+//
+func (m *ReorgMonitor) ChainMain(ctx context.Context, v ChainMain) ([]ChainEvent, error) {
+	if len(v.IDs) == 0 {
+		return nil, nil
+	}
+
+	blocks := make([]BlockID, len(v.IDs))
+	for idx, hash := range v.IDs {
+		blocks[idx] = BlockID{Height: v.FirstHeight + uint64(idx), Hash: hash}
+	}
+
+	m.mu.Lock()
+	events, ok := m.extend(v.FirstPrevID, blocks)
+	m.mu.Unlock()
+
+	// the notification doesn't build on top of any of the blocks we know
+	// about (e.g., some were missed): fall back to asking the node.
+	//
+	if !ok {
+		return m.Poll(ctx)
+	}
+
+	alts, err := m.altChains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(events, alts...), nil
+}
+
+// prime fills the window with the blocks up to `tip` and takes note of the
+// alternative chains already known.
+//
+func (m *ReorgMonitor) prime(ctx context.Context, tip BlockHeader) error {
+	start := uint64(0)
+	if tip.Height >= uint64(m.opts.window) {
+		start = tip.Height - uint64(m.opts.window) + 1
+	}
+
+	_, blocks, err := m.headers(ctx, start, tip.Height)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.client.GetAlternateChains(ctx)
+	if err != nil {
+		return fmt.Errorf("get alternate chains: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.window = blocks
+	m.trim()
+
+	m.alts = resp.Chains
+	for _, chain := range resp.Chains {
+		m.seen[chain.BlockHash] = chain.Height
+	}
+
+	return nil
+}
+
+// follow brings the window up to the block at height `tip`, finding out
+// where the chain forked off from the one we know about if needed.
+//
+func (m *ReorgMonitor) follow(ctx context.Context, tip uint64) ([]ChainEvent, error) {
+	top, _ := m.top()
+
+	if tip > top.Height {
+		prevHash, blocks, err := m.headers(ctx, top.Height+1, tip)
+		if err != nil {
+			return nil, err
+		}
+
+		m.mu.Lock()
+		events, ok := m.extend(prevHash, blocks)
+		m.mu.Unlock()
+
+		if ok {
+			return events, nil
+		}
+	}
+
+	fork, err := m.fork(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prevHash, blocks := fork.Hash, []BlockID{}
+	if tip > fork.Height {
+		prevHash, blocks, err = m.headers(ctx, fork.Height+1, tip)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	events, ok := m.extend(prevHash, blocks)
+	if !ok || prevHash != fork.Hash {
+		return nil, fmt.Errorf("chain changed while following it")
+	}
+
+	return events, nil
+}
+
+// fork finds the most recent block of the window still part of the main
+// chain.
+//
+func (m *ReorgMonitor) fork(ctx context.Context) (BlockID, error) {
+	window := m.Window()
+
+	for idx := len(window) - 1; idx >= 0; idx-- {
+		resp, err := m.client.GetBlockHeaderByHeight(ctx, window[idx].Height)
+		if err != nil {
+			if errors.Is(err, rpc.ErrTooBigHeight) {
+				continue
+			}
+
+			return BlockID{}, fmt.Errorf("get block header by height: %w", err)
+		}
+
+		if resp.BlockHeader.Hash == window[idx].Hash {
+			return window[idx], nil
+		}
+	}
+
+	return BlockID{}, fmt.Errorf("%w: no common block in the last %d",
+		ErrReorgTooDeep, len(window))
+}
+
+// extend adds `blocks` (consecutive ones, the first of which builds on top
+// of `prevHash`) to the window, orphaning those they replace (or, if there
+// are none to add, those past `prevHash`). It reports whether `prevHash` was
+// found in the window at all. Callers must hold the lock.
+//
+func (m *ReorgMonitor) extend(prevHash string, blocks []BlockID) ([]ChainEvent, bool) {
+	parent := -1
+	for idx := len(m.window) - 1; idx >= 0; idx-- {
+		if m.window[idx].Hash == prevHash {
+			parent = idx
+			break
+		}
+	}
+
+	if parent < 0 {
+		return nil, false
+	}
+
+	if len(blocks) > 0 {
+		if m.window[parent].Height+1 != blocks[0].Height {
+			return nil, false
+		}
+
+		// skip over the blocks that we already know about.
+		//
+		for len(blocks) > 0 && parent+1 < len(m.window) && m.window[parent+1] == blocks[0] {
+			parent++
+			blocks = blocks[1:]
+		}
+
+		if len(blocks) == 0 {
+			return nil, true
+		}
+	}
+
+	events := []ChainEvent{}
+
+	if orphaned := m.window[parent+1:]; len(orphaned) > 0 {
+		events = append(events, ChainEvent{
+			Reorg: &Reorg{
+				ForkHeight: m.window[parent].Height,
+				Depth:      uint64(len(orphaned)),
+				Orphaned:   append([]BlockID{}, orphaned...),
+			},
+		})
+	}
+
+	tip := m.window[parent]
+	if len(blocks) > 0 {
+		tip = blocks[len(blocks)-1]
+	}
+
+	m.window = append(m.window[:parent+1], blocks...)
+	m.trim()
+
+	return append(events, ChainEvent{NewTip: &NewTip{Block: tip}}), true
+}
+
+// altChains fetches the alternative chains known to the node, giving events
+// for those not seen before.
+//
+func (m *ReorgMonitor) altChains(ctx context.Context) ([]ChainEvent, error) {
+	resp, err := m.client.GetAlternateChains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get alternate chains: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	floor := m.floor()
+
+	events := []ChainEvent{}
+	for idx := range resp.Chains {
+		chain := resp.Chains[idx]
+		if chain.Height < floor {
+			continue
+		}
+
+		if _, seen := m.seen[chain.BlockHash]; seen {
+			continue
+		}
+
+		m.seen[chain.BlockHash] = chain.Height
+		events = append(events, ChainEvent{AltChain: &chain})
+	}
+
+	for hash, height := range m.seen {
+		if height < floor {
+			delete(m.seen, hash)
+		}
+	}
+
+	m.alts = resp.Chains
+	return events, nil
+}
+
+// floor gives the height of the oldest block in the window, below which
+// alternative chains are too deep to be kept track of. Callers must hold the
+// lock.
+//
+func (m *ReorgMonitor) floor() uint64 {
+	if len(m.window) == 0 {
+		return 0
+	}
+
+	return m.window[0].Height
+}
+
+// headers gives the ids of the blocks from `start` to `end` (verifying that
+// they form a chain), along with the hash of the block the first one builds
+// on top of.
+//
+func (m *ReorgMonitor) headers(
+	ctx context.Context, start, end uint64,
+) (string, []BlockID, error) {
+	resp, err := m.client.GetBlockHeadersRange(ctx, start, end)
+	if err != nil {
+		return "", nil, fmt.Errorf("get block headers range: %w", err)
+	}
+
+	if len(resp.Headers) == 0 {
+		return "", nil, fmt.Errorf("no headers from %d to %d", start, end)
+	}
+
+	blocks := make([]BlockID, len(resp.Headers))
+	for idx, header := range resp.Headers {
+		if idx > 0 && header.PrevHash != resp.Headers[idx-1].Hash {
+			return "", nil, fmt.Errorf("chain changed while fetching headers")
+		}
+
+		blocks[idx] = BlockID{Height: header.Height, Hash: header.Hash}
+	}
+
+	return resp.Headers[0].PrevHash, blocks, nil
+}
+
+func (m *ReorgMonitor) top() (BlockID, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Checkpoint{Blocks: m.window}.Top()
+}
+
+// trim drops the blocks that are too old to be remembered. Callers must hold
+// the lock.
+//
+func (m *ReorgMonitor) trim() {
+	if excess := len(m.window) - m.opts.window; excess > 0 {
+		m.window = append([]BlockID{}, m.window[excess:]...)
+	}
+}
+
+// WriteDOT renders the most recent blocks of the main chain along with the
+// alternative chains forking off from them as a Graphviz DOT graph.
+//
+func (m *ReorgMonitor) WriteDOT(w io.Writer) error {
+	return WriteChainsDOT(w, m.Window(), m.AltChains())
+}
+
+// WriteChainsDOT renders the blocks of the main chain (oldest first) along
+// with alternative chains forking off from it as a Graphviz DOT graph, e.g.,
+// to be turned into an image with `dot -Tsvg`.
+//
+func WriteChainsDOT(w io.Writer, main []BlockID, alts []AlternateChain) error {
+	g := &dotWriter{w: w, nodes: map[string]bool{}}
+
+	g.printf("digraph chain {\n")
+	g.printf("\trankdir=LR;\n")
+	g.printf("\tnode [shape=box, fontname=monospace];\n")
+
+	for idx, block := range main {
+		g.node(block, "")
+
+		if idx > 0 {
+			g.printf("\t%q -> %q;\n", main[idx-1].Hash, block.Hash)
+		}
+	}
+
+	for _, chain := range alts {
+		if len(chain.BlockHashes) == 0 ||
+			uint64(len(chain.BlockHashes)) > chain.Height {
+			continue
+		}
+
+		// block hashes go from the top of the chain down to the first
+		// diverging block, right after the main chain parent.
+		//
+		base := chain.Height - uint64(len(chain.BlockHashes)) + 1
+		prev := chain.MainChainParentBlock
+
+		g.node(BlockID{Height: base - 1, Hash: prev}, "")
+
+		for idx := len(chain.BlockHashes) - 1; idx >= 0; idx-- {
+			hash := chain.BlockHashes[idx]
+			height := base + uint64(len(chain.BlockHashes)-1-idx)
+
+			g.node(BlockID{Height: height, Hash: hash}, "style=dashed, color=red")
+			g.printf("\t%q -> %q [style=dashed, color=red];\n", prev, hash)
+
+			prev = hash
+		}
+	}
+
+	g.printf("}\n")
+	return g.err
+}
+
+// dotWriter writes a DOT graph, keeping track of the first error and of the
+// nodes declared so far.
+//
+type dotWriter struct {
+	w     io.Writer
+	err   error
+	nodes map[string]bool
+}
+
+func (g *dotWriter) printf(format string, args ...interface{}) {
+	if g.err != nil {
+		return
+	}
+
+	_, g.err = fmt.Fprintf(g.w, format, args...)
+}
+
+func (g *dotWriter) node(block BlockID, attrs string) {
+	if g.nodes[block.Hash] {
+		return
+	}
+
+	g.nodes[block.Hash] = true
+
+	hash := block.Hash
+	if len(hash) > 8 {
+		hash = hash[:8]
+	}
+
+	label := fmt.Sprintf("%d\\n%s", block.Height, hash)
+	if attrs != "" {
+		attrs = ", " + attrs
+	}
+
+	g.printf("\t%q [label=\"%s\"%s];\n", block.Hash, label, attrs)
+}
+
+func deliver(ctx context.Context, fn ChainEventFunc, events []ChainEvent) error {
+	for _, event := range events {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
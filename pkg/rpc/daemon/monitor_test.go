@@ -0,0 +1,263 @@
+package daemon_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestReorgMonitor(t *testing.T) {
+	spec.Run(t, "ReorgMonitor", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx     = context.Background()
+			server  *daemontest.Server
+			client  *daemon.Client
+			monitor *daemon.ReorgMonitor
+		)
+
+		id := func(height uint64) daemon.BlockID {
+			block, found := server.Block(height)
+			require.True(t, found)
+
+			return daemon.BlockID{Height: height, Hash: block.Hash}
+		}
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithBlocks(5))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+
+			monitor = daemon.NewReorgMonitor(client, daemon.WithMonitorWindow(3))
+
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+			assert.Equal(t, []daemon.BlockID{id(2), id(3), id(4)}, monitor.Window())
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("reports nothing if the chain didn't change", func() {
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+		})
+
+		it("reports new tips", func() {
+			server.GenerateBlocks(2)
+
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []daemon.ChainEvent{
+				{NewTip: &daemon.NewTip{Block: id(6)}},
+			}, events)
+			assert.Equal(t, []daemon.BlockID{id(4), id(5), id(6)}, monitor.Window())
+		})
+
+		it("reports reorgs along with the alt chain they left", func() {
+			orphaned := []daemon.BlockID{id(3), id(4)}
+			server.Reorg(2, 3)
+
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			require.Len(t, events, 3)
+
+			assert.Equal(t, &daemon.Reorg{
+				ForkHeight: 2,
+				Depth:      2,
+				Orphaned:   orphaned,
+			}, events[0].Reorg)
+			assert.Equal(t, &daemon.NewTip{Block: id(5)}, events[1].NewTip)
+
+			chain := events[2].AltChain
+			require.NotNil(t, chain)
+			assert.EqualValues(t, 4, chain.Height)
+			assert.EqualValues(t, 2, chain.Length)
+			assert.Equal(t, orphaned[1].Hash, chain.BlockHash)
+			assert.Equal(t, id(2).Hash, chain.MainChainParentBlock)
+
+			events, err = monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+		})
+
+		it("forgets alt chains once they're deeper than its window", func() {
+			server.Reorg(1, 1)
+
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			require.Len(t, events, 3)
+			require.NotNil(t, events[2].AltChain)
+
+			server.GenerateBlocks(3)
+
+			events, err = monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []daemon.ChainEvent{
+				{NewTip: &daemon.NewTip{Block: id(7)}},
+			}, events)
+
+			events, err = monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+		})
+
+		it("reports blocks popped off the chain", func() {
+			orphaned := []daemon.BlockID{id(3), id(4)}
+
+			_, err := client.PopBlocks(ctx, daemon.PopBlocksRequestParameters{NBlocks: 2})
+			require.NoError(t, err)
+
+			events, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []daemon.ChainEvent{
+				{Reorg: &daemon.Reorg{ForkHeight: 2, Depth: 2, Orphaned: orphaned}},
+				{NewTip: &daemon.NewTip{Block: id(2)}},
+			}, events)
+		})
+
+		it("fails on reorgs deeper than its window", func() {
+			server.Reorg(3, 4)
+
+			_, err := monitor.Poll(ctx)
+			assert.ErrorIs(t, err, daemon.ErrReorgTooDeep)
+		})
+
+		when("consuming chain_main notifications", func() {
+			it("reports new tips without polling", func() {
+				hashes := server.GenerateBlocks(1)
+				calls := server.Calls("get_last_block_header")
+
+				events, err := monitor.ChainMain(ctx, daemon.ChainMain{
+					FirstHeight: 5,
+					FirstPrevID: id(4).Hash,
+					IDs:         hashes,
+				})
+				require.NoError(t, err)
+				assert.Equal(t, []daemon.ChainEvent{
+					{NewTip: &daemon.NewTip{Block: id(5)}},
+				}, events)
+				assert.Equal(t, calls, server.Calls("get_last_block_header"))
+
+				events, err = monitor.ChainMain(ctx, daemon.ChainMain{
+					FirstHeight: 5,
+					FirstPrevID: id(4).Hash,
+					IDs:         hashes,
+				})
+				require.NoError(t, err)
+				assert.Empty(t, events)
+			})
+
+			it("reports reorgs", func() {
+				orphaned := id(4)
+				hashes := server.Reorg(1, 2)
+
+				events, err := monitor.ChainMain(ctx, daemon.ChainMain{
+					FirstHeight: 4,
+					FirstPrevID: id(3).Hash,
+					IDs:         hashes,
+				})
+				require.NoError(t, err)
+				require.Len(t, events, 3)
+				assert.Equal(t, &daemon.Reorg{
+					ForkHeight: 3,
+					Depth:      1,
+					Orphaned:   []daemon.BlockID{orphaned},
+				}, events[0].Reorg)
+				assert.Equal(t, &daemon.NewTip{Block: id(5)}, events[1].NewTip)
+				assert.NotNil(t, events[2].AltChain)
+			})
+
+			it("falls back to polling when blocks were missed", func() {
+				server.GenerateBlocks(2)
+
+				events, err := monitor.ChainMain(ctx, daemon.ChainMain{
+					FirstHeight: 6,
+					FirstPrevID: id(5).Hash,
+					IDs:         []string{id(6).Hash},
+				})
+				require.NoError(t, err)
+				assert.Equal(t, []daemon.ChainEvent{
+					{NewTip: &daemon.NewTip{Block: id(6)}},
+				}, events)
+			})
+
+			it("delivers the events while watching", func() {
+				var (
+					notifications = make(chan daemon.ChainMain)
+					events        []daemon.ChainEvent
+					prevID        = id(4).Hash
+				)
+
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+
+				monitor = daemon.NewReorgMonitor(client,
+					daemon.WithChainMain(notifications),
+					daemon.WithMonitorPollInterval(time.Hour),
+				)
+
+				// only mine once the monitor took note of the chain.
+				//
+				calls := server.Calls("get_alternate_chains")
+				go func() {
+					for server.Calls("get_alternate_chains") == calls {
+						time.Sleep(time.Millisecond)
+					}
+
+					notifications <- daemon.ChainMain{
+						FirstHeight: 5,
+						FirstPrevID: prevID,
+						IDs:         server.GenerateBlocks(1),
+					}
+				}()
+
+				err := monitor.Watch(ctx, func(_ context.Context, event daemon.ChainEvent) error {
+					events = append(events, event)
+					cancel()
+
+					return nil
+				})
+				assert.ErrorIs(t, err, context.Canceled)
+				assert.Equal(t, []daemon.ChainEvent{
+					{NewTip: &daemon.NewTip{Block: id(5)}},
+				}, events)
+			})
+		})
+
+		it("renders the chains as a DOT graph", func() {
+			orphaned := id(4)
+			server.Reorg(1, 1)
+
+			_, err := monitor.Poll(ctx)
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			require.NoError(t, monitor.WriteDOT(buf))
+
+			out := buf.String()
+			assert.Contains(t, out, "digraph chain {")
+			assert.Contains(t, out, fmt.Sprintf("%q -> %q;", id(3).Hash, id(4).Hash))
+			assert.Contains(t, out, fmt.Sprintf("%q -> %q [style=dashed, color=red];",
+				id(3).Hash, orphaned.Hash))
+			assert.Contains(t, out, fmt.Sprintf("%q [label=\"4\\n%s\"", orphaned.Hash,
+				orphaned.Hash[:8]))
+		})
+	}, spec.Report(report.Terminal{}))
+}
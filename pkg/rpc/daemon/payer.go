@@ -0,0 +1,417 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+const (
+	// DefaultPaymentRetryInterval is the default time to wait before
+	// retrying a call that required payment under `PaymentPolicyWait`.
+	//
+	DefaultPaymentRetryInterval = 10 * time.Second
+
+	// DefaultMaxPaymentRounds is the default number of times a call
+	// that required payment is retried after waiting or mining for
+	// credits.
+	//
+	DefaultMaxPaymentRounds = 10
+)
+
+// PaymentPolicy dictates what a Payer does when a node replies that a call
+// requires payment (i.e., the client hasn't got enough credits for it).
+//
+type PaymentPolicy string
+
+const (
+	// PaymentPolicyFail fails the call right away.
+	//
+	PaymentPolicyFail PaymentPolicy = "fail"
+
+	// PaymentPolicyWait pauses, retrying the call after a while, e.g.,
+	// for when credits are being earned by someone else (like a miner
+	// using the same key).
+	//
+	PaymentPolicyWait PaymentPolicy = "wait"
+
+	// PaymentPolicyMine earns credits by finding nonces through the
+	// NonceSolver supplied, retrying the call afterwards.
+	//
+	PaymentPolicyMine PaymentPolicy = "mine"
+)
+
+// PaymentPolicies is the set of all payment policies.
+//
+var PaymentPolicies = []PaymentPolicy{
+	PaymentPolicyFail,
+	PaymentPolicyWait,
+	PaymentPolicyMine,
+}
+
+// NonceSolver finds a nonce that, once set in the hashing blob from `info`,
+// gives a (RandomX) hash that meets the difficulty `info.Diff`.
+//
+type NonceSolver func(ctx context.Context, info *RPCAccessInfoResult) (uint32, error)
+
+type payerOptions struct {
+	policy        PaymentPolicy
+	solver        NonceSolver
+	retryInterval time.Duration
+	maxRounds     int
+}
+
+// PayerOption defines a functional option for overriding optional payer
+// configuration parameters.
+//
+type PayerOption func(o *payerOptions)
+
+// WithPaymentPolicy sets what to do when a call requires payment (default:
+// `PaymentPolicyFail`).
+//
+func WithPaymentPolicy(v PaymentPolicy) PayerOption {
+	return func(o *payerOptions) {
+		o.policy = v
+	}
+}
+
+// WithNonceSolver sets the function used for finding nonces when mining for
+// credits, also setting the policy to `PaymentPolicyMine`.
+//
+func WithNonceSolver(v NonceSolver) PayerOption {
+	return func(o *payerOptions) {
+		o.solver = v
+		o.policy = PaymentPolicyMine
+	}
+}
+
+// WithPaymentRetryInterval sets how long to wait before retrying a call that
+// required payment under `PaymentPolicyWait` (default:
+// `DefaultPaymentRetryInterval`).
+//
+func WithPaymentRetryInterval(v time.Duration) PayerOption {
+	return func(o *payerOptions) {
+		o.retryInterval = v
+	}
+}
+
+// WithMaxPaymentRounds sets how many times a call that required payment is
+// retried after waiting or mining for credits (default:
+// `DefaultMaxPaymentRounds`).
+//
+func WithMaxPaymentRounds(v int) PayerOption {
+	return func(o *payerOptions) {
+		o.maxRounds = v
+	}
+}
+
+// Payer is a Requester for nodes offering rpc for credits (started with
+// `--rpc-payment-address`): it identifies the client in every request made,
+// keeps track of the balance of credits reported back, and, when a call
+// requires payment, either fails it, waits, or mines for credits according to
+// its PaymentPolicy.
+//
+type Payer struct {
+	requester  Requester
+	privateKey []byte
+	opts       payerOptions
+
+	mu       sync.Mutex
+	credits  uint64
+	topHash  string
+	lastSign time.Time
+}
+
+var (
+	_ Requester       = (*Payer)(nil)
+	_ BinaryRequester = (*Payer)(nil)
+)
+
+// NewPayer instantiates a new Payer in front of the Requester supplied,
+// identifying the client with `privateKey` (see `monero.NewRPCPaymentKey`).
+//
+func NewPayer(r Requester, privateKey []byte, opts ...PayerOption) (*Payer, error) {
+	options := payerOptions{
+		policy:        PaymentPolicyFail,
+		retryInterval: DefaultPaymentRetryInterval,
+		maxRounds:     DefaultMaxPaymentRounds,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	switch options.policy {
+	case PaymentPolicyFail, PaymentPolicyWait, PaymentPolicyMine:
+	default:
+		return nil, fmt.Errorf("unknown payment policy '%s'", options.policy)
+	}
+
+	if options.policy == PaymentPolicyMine && options.solver == nil {
+		return nil, fmt.Errorf("policy '%s' requires a nonce solver",
+			options.policy)
+	}
+
+	p := &Payer{
+		requester:  r,
+		privateKey: privateKey,
+		opts:       options,
+	}
+
+	if _, err := p.Client(); err != nil {
+		return nil, fmt.Errorf("client: %w", err)
+	}
+
+	return p, nil
+}
+
+// Credits gives the last balance of credits reported by the node, along with
+// the hash of the top block at the time.
+//
+func (p *Payer) Credits() (uint64, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.credits, p.topHash
+}
+
+// Client generates a fresh signature identifying the client to be filled in
+// the `client` field of requests (see `monero.RPCPaymentSignature`).
+//
+func (p *Payer) Client() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// nodes refuse signatures with timestamps they've already seen, so
+	// make sure that they're always increasing.
+	//
+	now := time.Now()
+	if !now.After(p.lastSign) {
+		now = p.lastSign.Add(time.Microsecond)
+	}
+
+	p.lastSign = now
+
+	return monero.RPCPaymentSignature(p.privateKey, now)
+}
+
+// JSONRPC implements Requester, identifying the client in the parameters.
+//
+func (p *Payer) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return p.do(ctx, params, result, func(params interface{}) error {
+		return p.requester.JSONRPC(ctx, method, params, result)
+	})
+}
+
+// RawRequest implements Requester, identifying the client in the parameters.
+//
+func (p *Payer) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(ctx, params, response, func(params interface{}) error {
+		return p.requester.RawRequest(ctx, endpoint, params, response)
+	})
+}
+
+// BinaryRequest implements BinaryRequester by passing the request through to
+// the underlying Requester.
+//
+func (p *Payer) BinaryRequest(
+	ctx context.Context, endpoint string, body []byte,
+) ([]byte, error) {
+	requester, ok := p.requester.(BinaryRequester)
+	if !ok {
+		return nil, fmt.Errorf("requester doesn't support binary requests")
+	}
+
+	return requester.BinaryRequest(ctx, endpoint, body)
+}
+
+// Mine earns credits by finding a nonce for the current hashing blob and
+// submitting it.
+//
+func (p *Payer) Mine(ctx context.Context) error {
+	if p.opts.solver == nil {
+		return fmt.Errorf("no nonce solver")
+	}
+
+	// going straight to the underlying requester so that a node asking
+	// for payment for these doesn't get us mining recursively.
+	//
+	client := NewClient(p.requester)
+
+	signature, err := p.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	info, err := client.RPCAccessInfo(ctx, RPCAccessInfoRequestParameters{
+		Client: signature,
+	})
+	if err != nil {
+		return fmt.Errorf("rpc access info: %w", err)
+	}
+
+	p.observe(info)
+
+	nonce, err := p.opts.solver(ctx, info)
+	if err != nil {
+		return fmt.Errorf("solve: %w", err)
+	}
+
+	signature, err = p.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.RPCAccessSubmitNonce(ctx, RPCAccessSubmitNonceRequestParameters{
+		Client: signature,
+		Nonce:  nonce,
+		Cookie: info.Cookie,
+	})
+	if err != nil {
+		return fmt.Errorf("rpc access submit nonce: %w", err)
+	}
+
+	p.observe(resp)
+
+	return nil
+}
+
+// do performs a call through `fn` with the client identified in `params`,
+// dealing with payment being required according to the policy.
+//
+func (p *Payer) do(
+	ctx context.Context, params, result interface{},
+	fn func(params interface{}) error,
+) error {
+	for round := 0; ; round++ {
+		client, err := p.Client()
+		if err != nil {
+			return fmt.Errorf("client: %w", err)
+		}
+
+		identified, err := withClient(params, client)
+		if err != nil {
+			return fmt.Errorf("with client: %w", err)
+		}
+
+		err = fn(identified)
+		p.observe(result)
+
+		if !paymentRequired(err) {
+			return err
+		}
+
+		if p.opts.policy == PaymentPolicyFail || round >= p.opts.maxRounds {
+			credits, _ := p.Credits()
+			return fmt.Errorf("payment required (credits=%d): %w", credits, err)
+		}
+
+		if err := p.earn(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// earn either waits or mines for credits according to the policy.
+//
+func (p *Payer) earn(ctx context.Context) error {
+	if p.opts.policy == PaymentPolicyMine {
+		if err := p.Mine(ctx); err != nil {
+			return fmt.Errorf("mine: %w", err)
+		}
+
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("wait for credits: %w", ctx.Err())
+	case <-time.After(p.opts.retryInterval):
+	}
+
+	return nil
+}
+
+// creditsReporter is implemented by results that carry the balance of
+// credits (i.e., those embedding RPCResultFooter).
+//
+type creditsReporter interface {
+	RPCCredits() (uint64, string)
+}
+
+// observe keeps track of the balance reported in `result`, if any.
+//
+// ps.: the top hash is only ever filled when payments are enabled, which
+// tells apart a balance of 0 from the lack of one.
+//
+func (p *Payer) observe(result interface{}) {
+	reporter, ok := result.(creditsReporter)
+	if !ok {
+		return
+	}
+
+	credits, topHash := reporter.RPCCredits()
+	if topHash == "" {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.credits, p.topHash = credits, topHash
+}
+
+// paymentRequired tells whether `err` is the node asking for payment.
+//
+func paymentRequired(err error) bool {
+	return errors.Is(err, rpc.ErrStatusPaymentRequired) ||
+		errors.Is(err, rpc.ErrPaymentRequired)
+}
+
+// withClient fills the `client` field of `params` (if a JSON object, or none
+// at all) with `client`, unless already set.
+//
+func withClient(params interface{}, client string) (interface{}, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return map[string]string{"client": client}, nil
+	}
+
+	if len(b) == 0 || b[0] != '{' {
+		return params, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	if current, found := fields["client"]; found && string(current) != `""` {
+		return params, nil
+	}
+
+	encoded, err := json.Marshal(client)
+	if err != nil {
+		return nil, fmt.Errorf("marshal client: %w", err)
+	}
+
+	fields["client"] = encoded
+
+	return fields, nil
+}
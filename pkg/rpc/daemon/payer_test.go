@@ -0,0 +1,196 @@
+package daemon_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestPayer(t *testing.T) {
+	spec.Run(t, "Payer", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			key    []byte
+			admin  *daemon.Client
+		)
+
+		newPayer := func(opts ...daemon.PayerOption) (*daemon.Payer, *daemon.Client) {
+			c, err := server.NewClient()
+			require.NoError(t, err)
+
+			payer, err := daemon.NewPayer(c.Requester, key, opts...)
+			require.NoError(t, err)
+
+			return payer, daemon.NewClient(payer)
+		}
+
+		topUp := func(credits int64) {
+			client, err := monero.RPCPaymentSignature(key, time.Now())
+			require.NoError(t, err)
+
+			_, err = admin.RPCAccessAccount(ctx, daemon.RPCAccessAccountRequestParameters{
+				Client:       client,
+				DeltaBalance: credits,
+			})
+			require.NoError(t, err)
+		}
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(
+				daemontest.WithBlocks(5),
+				daemontest.WithRPCPayment(10, 25),
+			)
+
+			admin, err = server.NewClient()
+			require.NoError(t, err)
+
+			key = monero.NewRPCPaymentKey()
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("tracks the balance reported by the node", func() {
+			topUp(100)
+
+			payer, client := newPayer()
+
+			_, err := client.GetHeight(ctx)
+			require.NoError(t, err)
+
+			resp, err := client.GetInfo(ctx)
+			require.NoError(t, err)
+			assert.EqualValues(t, 80, resp.Credits)
+
+			credits, topHash := payer.Credits()
+			assert.EqualValues(t, 80, credits)
+			assert.Equal(t, resp.TopHash, topHash)
+		})
+
+		it("fails calls requiring payment by default", func() {
+			payer, client := newPayer()
+
+			_, err := client.GetInfo(ctx)
+			assert.ErrorIs(t, err, rpc.ErrStatusPaymentRequired)
+
+			credits, topHash := payer.Credits()
+			assert.Zero(t, credits)
+			assert.NotEmpty(t, topHash)
+		})
+
+		it("fails calls requiring payment without a client", func() {
+			_, err := admin.GetInfo(ctx)
+			assert.ErrorIs(t, err, rpc.ErrStatusPaymentRequired)
+		})
+
+		it("mines for credits", func() {
+			var nonce uint32
+
+			payer, client := newPayer(daemon.WithNonceSolver(
+				func(_ context.Context, info *daemon.RPCAccessInfoResult) (uint32, error) {
+					assert.NotEmpty(t, info.HashingBlob)
+					assert.EqualValues(t, 25, info.CreditsPerHashFound)
+
+					return atomic.AddUint32(&nonce, 1), nil
+				},
+			))
+
+			for i := 0; i < 5; i++ {
+				_, err := client.GetHeight(ctx)
+				require.NoError(t, err)
+			}
+
+			// 2 nonces (50 credits) for the first 5 calls (50 credits).
+			//
+			assert.EqualValues(t, 2, atomic.LoadUint32(&nonce))
+
+			credits, _ := payer.Credits()
+			assert.EqualValues(t, 0, credits)
+
+			data, err := admin.RPCAccessData(ctx)
+			require.NoError(t, err)
+			require.Len(t, data.Entries, 1)
+			assert.EqualValues(t, 2, data.Entries[0].NoncesGood)
+			assert.EqualValues(t, 50, data.Entries[0].CreditsUsed)
+		})
+
+		it("waits for credits", func() {
+			_, client := newPayer(
+				daemon.WithPaymentPolicy(daemon.PaymentPolicyWait),
+				daemon.WithPaymentRetryInterval(10*time.Millisecond),
+			)
+
+			go func() {
+				for server.Calls("/get_height") == 0 {
+					time.Sleep(time.Millisecond)
+				}
+
+				topUp(10)
+			}()
+
+			_, err := client.GetHeight(ctx)
+			require.NoError(t, err)
+		})
+
+		it("gives up after the max number of rounds", func() {
+			_, client := newPayer(
+				daemon.WithPaymentPolicy(daemon.PaymentPolicyWait),
+				daemon.WithPaymentRetryInterval(time.Millisecond),
+				daemon.WithMaxPaymentRounds(2),
+			)
+
+			_, err := client.GetHeight(ctx)
+			assert.ErrorIs(t, err, rpc.ErrStatusPaymentRequired)
+			assert.EqualValues(t, 3, server.Calls("/get_height"))
+		})
+
+		it("requires a solver for mining", func() {
+			_, err := daemon.NewPayer(admin.Requester, key,
+				daemon.WithPaymentPolicy(daemon.PaymentPolicyMine))
+			assert.Error(t, err)
+		})
+
+		it("fails w/ unknown policy", func() {
+			_, err := daemon.NewPayer(admin.Requester, key,
+				daemon.WithPaymentPolicy("fial"))
+			assert.Error(t, err)
+		})
+
+		it("pays for services", func() {
+			topUp(30)
+
+			payer, client := newPayer()
+
+			_, err := client.RPCAccessPay(ctx, daemon.RPCAccessPayRequestParameters{
+				PayingFor: "something",
+				Payment:   20,
+			})
+			require.NoError(t, err)
+
+			credits, _ := payer.Credits()
+			assert.EqualValues(t, 10, credits)
+
+			_, err = client.RPCAccessPay(ctx, daemon.RPCAccessPayRequestParameters{
+				PayingFor: "something else",
+				Payment:   20,
+			})
+			assert.ErrorIs(t, err, rpc.ErrStatusPaymentRequired)
+		})
+	}, spec.Report(report.Terminal{}))
+}
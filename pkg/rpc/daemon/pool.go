@@ -0,0 +1,594 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// Strategy defines how a Pool picks which node should serve a call.
+//
+type Strategy string
+
+const (
+	// StrategyPrimaryBackup sends every call to the first healthy node in
+	// the order they were supplied, using the others only as backups.
+	//
+	StrategyPrimaryBackup Strategy = "primary-backup"
+
+	// StrategyRoundRobin spreads calls evenly across all healthy nodes.
+	//
+	StrategyRoundRobin Strategy = "round-robin"
+
+	// StrategyLowestLatency prefers the healthy node that has been
+	// answering the fastest.
+	//
+	StrategyLowestLatency Strategy = "lowest-latency"
+
+	// StrategyHighestHeight prefers the healthy node with the tallest
+	// chain.
+	//
+	StrategyHighestHeight Strategy = "highest-height"
+)
+
+// Strategies lists all the strategies supported by Pool.
+//
+var Strategies = []Strategy{
+	StrategyPrimaryBackup,
+	StrategyRoundRobin,
+	StrategyLowestLatency,
+	StrategyHighestHeight,
+}
+
+// PoolNode is a single monero node that's part of a Pool.
+//
+type PoolNode struct {
+	// Address identifies the node (typically, the URL the requester has
+	// been configured to reach out to).
+	//
+	Address string
+
+	// Requester is what's used to make requests to the node.
+	//
+	Requester Requester
+}
+
+// NodeStatus is a snapshot of what a Pool knows about one of its nodes.
+//
+type NodeStatus struct {
+	// Address is the address of the node.
+	//
+	Address string
+
+	// Healthy indicates whether the node is eligible for serving calls.
+	//
+	Healthy bool
+
+	// Reason explains why the node is not healthy, if so.
+	//
+	Reason string
+
+	// Height is the chain height reported by the node in the last health
+	// check.
+	//
+	Height uint64
+
+	// Latency is a moving average of how long the node takes to reply.
+	//
+	Latency time.Duration
+
+	// LastCheck is when the node was last health-checked.
+	//
+	LastCheck time.Time
+}
+
+// ServedCall describes which node served a given call (see
+// `WithServedCallback`).
+//
+type ServedCall struct {
+	// Method is either the JSONRPC method or the raw endpoint requested.
+	//
+	Method string
+
+	// Address is the address of the node that served the call.
+	//
+	Address string
+
+	// Duration is how long the node took to serve the call.
+	//
+	Duration time.Duration
+
+	// Err is the error that the call resulted in, if any.
+	//
+	Err error
+}
+
+type poolOptions struct {
+	strategy        Strategy
+	maxHeightLag    uint64
+	failureCooldown time.Duration
+	onServed        func(ServedCall)
+}
+
+// PoolOption defines a functional option for overriding optional pool
+// configuration parameters.
+//
+type PoolOption func(o *poolOptions)
+
+// WithStrategy overrides the default strategy (primary/backup) used for
+// picking nodes.
+//
+func WithStrategy(v Strategy) PoolOption {
+	return func(o *poolOptions) {
+		o.strategy = v
+	}
+}
+
+// WithMaxHeightLag sets how many blocks a node might be behind the tallest
+// node in the pool before being ejected during health checks (default: 2).
+//
+func WithMaxHeightLag(v uint64) PoolOption {
+	return func(o *poolOptions) {
+		o.maxHeightLag = v
+	}
+}
+
+// WithFailureCooldown sets for how long a node that failed to serve a call is
+// only tried as a last resort before being given another chance (default:
+// 30s).
+//
+func WithFailureCooldown(v time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.failureCooldown = v
+	}
+}
+
+// WithServedCallback registers a function to be called after every call
+// with the details about which node served it.
+//
+func WithServedCallback(fn func(ServedCall)) PoolOption {
+	return func(o *poolOptions) {
+		o.onServed = fn
+	}
+}
+
+// poolNode is a PoolNode plus the pool's knowledge about it.
+//
+type poolNode struct {
+	PoolNode
+
+	healthy   bool
+	reason    string
+	height    uint64
+	latency   time.Duration
+	lastCheck time.Time
+
+	// failedAt is when the node was last marked unhealthy for failing to
+	// serve a call (rather than by a health check), if so.
+	//
+	failedAt time.Time
+}
+
+// Pool is a Requester that spreads calls across several nodes, failing over
+// to the next eligible node whenever one fails to serve a call - calls that
+// change state (`relay_tx`, `pop_blocks`, ...) being only failed over when
+// they certainly never reached the node, so that they're never performed
+// twice.
+//
+// Nodes are considered healthy until a health check (`HealthCheck` or `Run`)
+// or a failed call says otherwise. Those ejected for failing a call are given
+// another chance once the failure cooldown (see `WithFailureCooldown`) is
+// over, and become healthy again as soon as they successfully serve a call.
+//
+type Pool struct {
+	opts  poolOptions
+	next  uint64
+	mu    sync.RWMutex
+	nodes []*poolNode
+}
+
+// NewPool instantiates a new Pool out of a set of nodes.
+//
+func NewPool(nodes []PoolNode, opts ...PoolOption) (*Pool, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("at least one node must be supplied")
+	}
+
+	options := poolOptions{
+		strategy:        StrategyPrimaryBackup,
+		maxHeightLag:    2,
+		failureCooldown: 30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	switch options.strategy {
+	case StrategyPrimaryBackup, StrategyRoundRobin,
+		StrategyLowestLatency, StrategyHighestHeight:
+	default:
+		return nil, fmt.Errorf("unknown strategy '%s'", options.strategy)
+	}
+
+	pool := &Pool{opts: options}
+	for _, node := range nodes {
+		pool.nodes = append(pool.nodes, &poolNode{
+			PoolNode: node,
+			healthy:  true,
+		})
+	}
+
+	return pool, nil
+}
+
+// NewPoolFromAddresses instantiates a new Pool with an `rpc.Client` for each
+// of the addresses supplied.
+//
+func NewPoolFromAddresses(
+	addresses []string, clientOpts []rpc.ClientOption, opts ...PoolOption,
+) (*Pool, error) {
+	nodes := make([]PoolNode, len(addresses))
+
+	for idx, address := range addresses {
+		client, err := rpc.NewClient(address, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new client for '%s': %w",
+				address, err)
+		}
+
+		nodes[idx] = PoolNode{Address: address, Requester: client}
+	}
+
+	return NewPool(nodes, opts...)
+}
+
+// Nodes gives a snapshot of the status of all the nodes in the pool.
+//
+func (p *Pool) Nodes() []NodeStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	res := make([]NodeStatus, len(p.nodes))
+	for idx, node := range p.nodes {
+		res[idx] = NodeStatus{
+			Address:   node.Address,
+			Healthy:   node.healthy,
+			Reason:    node.reason,
+			Height:    node.height,
+			Latency:   node.latency,
+			LastCheck: node.lastCheck,
+		}
+	}
+
+	return res
+}
+
+// HealthCheck calls `get_info` on every node of the pool concurrently,
+// ejecting those that fail to respond, are busy syncing, untrusted, or lag
+// behind the tallest node by more than the configured maximum height lag.
+//
+func (p *Pool) HealthCheck(ctx context.Context) {
+	type check struct {
+		info    *GetInfoResult
+		latency time.Duration
+		err     error
+	}
+
+	checks := make([]check, len(p.nodes))
+
+	var wg sync.WaitGroup
+	for idx, node := range p.nodes {
+		wg.Add(1)
+
+		go func(idx int, node *poolNode) {
+			defer wg.Done()
+
+			start := time.Now()
+			info, err := NewClient(node.Requester).GetInfo(ctx)
+			checks[idx] = check{info, time.Since(start), err}
+		}(idx, node)
+	}
+
+	wg.Wait()
+
+	maxHeight := uint64(0)
+	for _, c := range checks {
+		if c.err == nil && c.info.Height > maxHeight {
+			maxHeight = c.info.Height
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for idx, node := range p.nodes {
+		c := checks[idx]
+
+		node.lastCheck = now
+		node.healthy, node.reason = false, ""
+		node.failedAt = time.Time{}
+
+		switch {
+		case c.err != nil:
+			node.reason = c.err.Error()
+			continue
+		case c.info.BusySyncing:
+			node.reason = "busy syncing"
+		case c.info.Untrusted:
+			node.reason = "untrusted"
+		case c.info.Height+p.opts.maxHeightLag < maxHeight:
+			node.reason = fmt.Sprintf("behind by %d blocks",
+				maxHeight-c.info.Height)
+		default:
+			node.healthy = true
+		}
+
+		node.height = c.info.Height
+		node.latency = averageLatency(node.latency, c.latency)
+	}
+}
+
+// Run health-checks the nodes of the pool every `interval` until the context
+// is cancelled.
+//
+func (p *Pool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.HealthCheck(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// JSONRPC implements Requester by issuing the call against the nodes picked
+// by the pool's strategy until one successfully serves it.
+//
+func (p *Pool) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return p.do(ctx, method, rpc.IsSafeMethod(method), func(node *poolNode) error {
+		return node.Requester.JSONRPC(ctx, method, params, result)
+	})
+}
+
+// RawRequest implements Requester by issuing the request against the nodes
+// picked by the pool's strategy until one successfully serves it.
+//
+func (p *Pool) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(ctx, endpoint, rpc.IsSafeMethod(endpoint), func(node *poolNode) error {
+		return node.Requester.RawRequest(ctx, endpoint, params, response)
+	})
+}
+
+// JSONRPCBatch implements BatchRequester by submitting the whole batch to a
+// single node, failing over to the next if the batch as a whole failed (as
+// long as all of the calls are safe to be issued again, see `Pool.do`).
+//
+func (p *Pool) JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	safe := true
+	for _, call := range calls {
+		safe = safe && rpc.IsSafeMethod(call.Method)
+	}
+
+	return p.do(ctx, "batch", safe, func(node *poolNode) error {
+		return NewClient(node.Requester).jsonrpcBatch(ctx, calls)
+	})
+}
+
+// BinaryRequest implements BinaryRequester by issuing the request against the
+// nodes picked by the pool's strategy until one successfully serves it.
+//
+func (p *Pool) BinaryRequest(
+	ctx context.Context, endpoint string, body []byte,
+) ([]byte, error) {
+	var response []byte
+
+	err := p.do(ctx, endpoint, rpc.IsSafeMethod(endpoint), func(node *poolNode) error {
+		requester, ok := node.Requester.(BinaryRequester)
+		if !ok {
+			return fmt.Errorf("requester doesn't support binary requests")
+		}
+
+		resp, err := requester.BinaryRequest(ctx, endpoint, body)
+		if err != nil {
+			return err
+		}
+
+		// decode to look at the status so that busy nodes are
+		// failed over just like with the other requests.
+		//
+		if _, err := decodeBinaryResponse(resp); err != nil {
+			return err
+		}
+
+		response = resp
+		return nil
+	})
+
+	return response, err
+}
+
+// do issues a call to `method` (through `fn`) against the candidate nodes
+// until one serves it.
+//
+// Calls that change state (i.e., not `safe` ones, see `rpc.IsSafeMethod`)
+// are only sent to the next node when the request certainly never reached
+// the previous one: a timeout, an unreadable response, or a node refusing to
+// serve it might come after the action was already performed, and sending it
+// again (`relay_tx`, `pop_blocks`, ...) could perform it twice.
+//
+func (p *Pool) do(
+	ctx context.Context, method string, safe bool,
+	fn func(node *poolNode) error,
+) error {
+	var errs []error
+
+	for _, node := range p.candidates() {
+		start := time.Now()
+		err := fn(node)
+		duration := time.Since(start)
+
+		if p.opts.onServed != nil {
+			p.opts.onServed(ServedCall{
+				Method:   method,
+				Address:  node.Address,
+				Duration: duration,
+				Err:      err,
+			})
+		}
+
+		p.observe(node, duration, err)
+
+		if err == nil || !shouldFailover(ctx, err) {
+			return err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", node.Address, err))
+
+		if !safe && !isUnsent(err) {
+			break
+		}
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return fmt.Errorf("all %d nodes failed, last: %w",
+		len(errs), errs[len(errs)-1])
+}
+
+// observe updates the knowledge about a node based on the outcome of a call
+// it served.
+//
+func (p *Pool) observe(node *poolNode, duration time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil && shouldFailover(context.Background(), err) {
+		node.healthy, node.reason = false, err.Error()
+		node.failedAt = time.Now()
+		return
+	}
+
+	if err == nil && !node.failedAt.IsZero() {
+		node.healthy, node.reason = true, ""
+		node.failedAt = time.Time{}
+	}
+
+	node.latency = averageLatency(node.latency, duration)
+}
+
+// candidates lists, in order of preference, the nodes that should be tried
+// for serving a call, with those considered unhealthy only being put at the
+// end as a last resort - unless they were ejected for failing a call longer
+// than the failure cooldown ago.
+//
+func (p *Pool) candidates() []*poolNode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := time.Now()
+
+	var healthy, unhealthy []*poolNode
+	for _, node := range p.nodes {
+		cooledDown := !node.failedAt.IsZero() &&
+			now.Sub(node.failedAt) >= p.opts.failureCooldown
+
+		if node.healthy || cooledDown {
+			healthy = append(healthy, node)
+		} else {
+			unhealthy = append(unhealthy, node)
+		}
+	}
+
+	switch p.opts.strategy {
+	case StrategyRoundRobin:
+		if len(healthy) > 0 {
+			offset := int(atomic.AddUint64(&p.next, 1) % uint64(len(healthy)))
+
+			rotated := make([]*poolNode, 0, len(healthy))
+			rotated = append(rotated, healthy[offset:]...)
+			healthy = append(rotated, healthy[:offset]...)
+		}
+	case StrategyLowestLatency:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].latency < healthy[j].latency
+		})
+	case StrategyHighestHeight:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].height > healthy[j].height
+		})
+	case StrategyPrimaryBackup:
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// shouldFailover classifies errors between those that indicate that the node
+// is not in a position to serve the call (e.g., unreachable, busy,
+// restricted), and those that another node would reply the same way to
+// (e.g., wrong parameters).
+//
+func shouldFailover(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var rpcErr *rpc.Error
+	if errors.As(err, &rpcErr) {
+		return errors.Is(err, rpc.ErrCoreBusy) ||
+			errors.Is(err, rpc.ErrRestricted) ||
+			errors.Is(err, rpc.ErrMethodNotFound) ||
+			errors.Is(err, rpc.ErrPaymentRequired)
+	}
+
+	var statusErr *rpc.StatusError
+	if errors.As(err, &statusErr) {
+		return errors.Is(err, rpc.ErrStatusBusy) ||
+			errors.Is(err, rpc.ErrStatusPaymentRequired)
+	}
+
+	return true
+}
+
+// isUnsent tells whether an error indicates that the request never made it to
+// the node, i.e., that the connection to it couldn't even be established.
+//
+func isUnsent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// averageLatency computes an exponentially weighted moving average of the
+// latency of a node.
+//
+func averageLatency(current, sample time.Duration) time.Duration {
+	if current == 0 {
+		return sample
+	}
+
+	return (current*4 + sample) / 5
+}
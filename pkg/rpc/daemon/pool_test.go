@@ -0,0 +1,257 @@
+package daemon_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+)
+
+// node is a fake monerod that replies to every request with a `get_info`
+// result (which also serves for any other call in these tests).
+//
+type node struct {
+	*httptest.Server
+
+	height      uint64
+	busySyncing bool
+	down        bool
+	latency     time.Duration
+	served      int
+}
+
+func newNode(height uint64) *node {
+	n := &node{height: height}
+
+	n.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if n.down {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			n.served++
+			time.Sleep(n.latency)
+
+			fmt.Fprintf(w, `{"id":"0", "jsonrpc":"2.0", "result": {
+				"height": %d, "busy_syncing": %t, "status": "OK"
+			}}`, n.height, n.busySyncing)
+		},
+	))
+
+	return n
+}
+
+func newPool(t *testing.T, opts []daemon.PoolOption, nodes ...*node) *daemon.Pool {
+	poolNodes := make([]daemon.PoolNode, len(nodes))
+
+	for idx, n := range nodes {
+		httpClient := n.Client()
+		httpClient.Timeout = 250 * time.Millisecond
+
+		client, err := rpc.NewClient(n.URL, rpc.WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		poolNodes[idx] = daemon.PoolNode{Address: n.URL, Requester: client}
+	}
+
+	pool, err := daemon.NewPool(poolNodes, opts...)
+	require.NoError(t, err)
+
+	return pool
+}
+
+// nolint:funlen
+func TestPool(t *testing.T) {
+	spec.Run(t, "Pool", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx            = context.Background()
+			primary, extra *node
+		)
+
+		it.Before(func() {
+			primary, extra = newNode(100), newNode(100)
+		})
+
+		it.After(func() {
+			primary.Close()
+			extra.Close()
+		})
+
+		it("fails w/ unknown strategy", func() {
+			_, err := daemon.NewPool([]daemon.PoolNode{{}},
+				daemon.WithStrategy("foo"))
+			assert.Error(t, err)
+		})
+
+		it("sticks to the primary when healthy", func() {
+			pool := newPool(t, nil, primary, extra)
+
+			for i := 0; i < 3; i++ {
+				_, err := daemon.NewClient(pool).GetInfo(ctx)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, 3, primary.served)
+			assert.Equal(t, 0, extra.served)
+		})
+
+		it("fails over to the backup", func() {
+			var served []string
+
+			pool := newPool(t, []daemon.PoolOption{
+				daemon.WithServedCallback(func(c daemon.ServedCall) {
+					served = append(served, c.Address)
+				}),
+			}, primary, extra)
+
+			primary.down = true
+
+			_, err := daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{primary.URL, extra.URL}, served)
+			assert.False(t, pool.Nodes()[0].Healthy)
+		})
+
+		it("gives failed nodes another chance after the cooldown", func() {
+			pool := newPool(t, []daemon.PoolOption{
+				daemon.WithFailureCooldown(50 * time.Millisecond),
+			}, primary, extra)
+
+			primary.down = true
+
+			_, err := daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+
+			primary.down = false
+
+			_, err = daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 0, primary.served)
+			assert.Equal(t, 2, extra.served)
+
+			time.Sleep(100 * time.Millisecond)
+
+			_, err = daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, primary.served)
+			assert.True(t, pool.Nodes()[0].Healthy)
+		})
+
+		it("marks failed nodes healthy once they serve a call", func() {
+			pool := newPool(t, nil, primary)
+
+			primary.down = true
+
+			_, err := daemon.NewClient(pool).GetInfo(ctx)
+			require.Error(t, err)
+			assert.False(t, pool.Nodes()[0].Healthy)
+
+			primary.down = false
+
+			_, err = daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+			assert.True(t, pool.Nodes()[0].Healthy)
+			assert.Empty(t, pool.Nodes()[0].Reason)
+		})
+
+		it("fails over calls that change state only when unsent", func() {
+			pool := newPool(t, nil, primary, extra)
+
+			primary.Close()
+
+			_, err := daemon.NewClient(pool).PopBlocks(ctx,
+				daemon.PopBlocksRequestParameters{NBlocks: 1})
+			require.NoError(t, err)
+			assert.Equal(t, 1, extra.served)
+		})
+
+		it("doesn't resend calls that change state after a timeout", func() {
+			pool := newPool(t, nil, primary, extra)
+
+			primary.latency = time.Second
+
+			_, err := daemon.NewClient(pool).PopBlocks(ctx,
+				daemon.PopBlocksRequestParameters{NBlocks: 1})
+			assert.Error(t, err)
+
+			primary.Close() // waits for the request to be done with.
+			assert.Equal(t, 1, primary.served)
+			assert.Equal(t, 0, extra.served)
+			assert.False(t, pool.Nodes()[0].Healthy)
+
+			// reads, on the other hand, are safe to be sent elsewhere.
+			//
+			_, err = daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, extra.served)
+		})
+
+		it("errors when all nodes fail", func() {
+			pool := newPool(t, nil, primary, extra)
+
+			primary.down, extra.down = true, true
+
+			_, err := daemon.NewClient(pool).GetInfo(ctx)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "all 2 nodes failed")
+		})
+
+		it("spreads calls w/ round-robin", func() {
+			pool := newPool(t, []daemon.PoolOption{
+				daemon.WithStrategy(daemon.StrategyRoundRobin),
+			}, primary, extra)
+
+			for i := 0; i < 4; i++ {
+				_, err := daemon.NewClient(pool).GetInfo(ctx)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, 2, primary.served)
+			assert.Equal(t, 2, extra.served)
+		})
+
+		it("prefers the tallest node w/ highest-height", func() {
+			extra.height = 101
+
+			pool := newPool(t, []daemon.PoolOption{
+				daemon.WithStrategy(daemon.StrategyHighestHeight),
+			}, primary, extra)
+			pool.HealthCheck(ctx)
+
+			resp, err := daemon.NewClient(pool).GetInfo(ctx)
+			require.NoError(t, err)
+			assert.EqualValues(t, 101, resp.Height)
+		})
+
+		it("ejects nodes that are behind or busy syncing", func() {
+			lagging := newNode(90)
+			defer lagging.Close()
+
+			extra.busySyncing = true
+
+			pool := newPool(t, nil, primary, extra, lagging)
+			pool.HealthCheck(ctx)
+
+			nodes := pool.Nodes()
+			assert.True(t, nodes[0].Healthy)
+
+			assert.False(t, nodes[1].Healthy)
+			assert.Equal(t, "busy syncing", nodes[1].Reason)
+
+			assert.False(t, nodes[2].Healthy)
+			assert.Equal(t, "behind by 10 blocks", nodes[2].Reason)
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -3,25 +3,37 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
 )
 
 const (
-	endpointGetHeight               = "/get_height"
-	endpointGetLimit                = "/get_limit"
-	endpointGetNetStats             = "/get_net_stats"
-	endpointGetOuts                 = "/get_outs"
-	endpointGetPeerList             = "/get_peer_list"
-	endpointGetPublicNodes          = "/get_public_nodes"
-	endpointGetTransactionPool      = "/get_transaction_pool"
-	endpointGetTransactionPoolStats = "/get_transaction_pool_stats"
-	endpointGetTransactions         = "/get_transactions"
-	endpointMiningStatus            = "/mining_status"
-	endpointSetLimit                = "/set_limit"
-	endpointSetLogLevel             = "/set_log_level"
-	endpointSetLogCategories        = "/set_log_categories"
-	endpointStartMining             = "/start_mining"
-	endpointStopMining              = "/stop_mining"
+	endpointGetAltBlocksHashes       = "/get_alt_blocks_hashes"
+	endpointGetHeight                = "/get_height"
+	endpointGetLimit                 = "/get_limit"
+	endpointGetNetStats              = "/get_net_stats"
+	endpointGetOuts                  = "/get_outs"
+	endpointGetPeerList              = "/get_peer_list"
+	endpointGetPublicNodes           = "/get_public_nodes"
+	endpointGetTransactionPool       = "/get_transaction_pool"
+	endpointGetTransactionPoolHashes = "/get_transaction_pool_hashes"
+	endpointGetTransactionPoolStats  = "/get_transaction_pool_stats"
+	endpointGetTransactions          = "/get_transactions"
+	endpointInPeers                  = "/in_peers"
+	endpointIsKeyImageSpent          = "/is_key_image_spent"
+	endpointMiningStatus             = "/mining_status"
+	endpointOutPeers                 = "/out_peers"
+	endpointPopBlocks                = "/pop_blocks"
+	endpointSaveBc                   = "/save_bc"
+	endpointSendRawTransaction       = "/send_raw_transaction"
+	endpointSetLimit                 = "/set_limit"
+	endpointSetLogLevel              = "/set_log_level"
+	endpointSetLogCategories         = "/set_log_categories"
+	endpointStartMining              = "/start_mining"
+	endpointStopMining               = "/stop_mining"
+	endpointUpdate                   = "/update"
 )
 
 func (c *Client) StopMining(
@@ -255,5 +267,201 @@ func (c *Client) GetTransactions(
 		return nil, fmt.Errorf("raw request: %w", err)
 	}
 
+	if c.verify {
+		if err := resp.Verify(); err != nil {
+			return nil, fmt.Errorf("verify: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// SendRawTransactionRequestParameters is the set of parameters to be passed to
+// the SendRawTransaction RPC endpoint.
+//
+type SendRawTransactionRequestParameters struct {
+	// TxAsHex is the hex-encoded signed transaction.
+	//
+	TxAsHex string `json:"tx_as_hex"`
+
+	// DoNotRelay makes the daemon keep the transaction in its pool
+	// without broadcasting it to the network.
+	//
+	DoNotRelay bool `json:"do_not_relay"`
+
+	// DoSanityChecks makes the daemon verify the transaction against
+	// heuristics that avoid privacy mistakes (e.g., a ring with outputs
+	// too concentrated).
+	//
+	DoSanityChecks bool `json:"do_sanity_checks"`
+}
+
+// SendRawTransaction submits a signed transaction to the daemon, which, if
+// valid, adds it to its transaction pool, relaying it to the network unless
+// told otherwise.
+//
+// When the daemon refuses the transaction, a `*TxRejectedError` is returned
+// with the reasons for it (see the `Err*` sentinel values, e.g.,
+// `ErrDoubleSpend`).
+//
+func (c *Client) SendRawTransaction(
+	ctx context.Context, params SendRawTransactionRequestParameters,
+) (*SendRawTransactionResult, error) {
+	resp := &SendRawTransactionResult{}
+
+	err := c.RawRequest(ctx, endpointSendRawTransaction, params, resp)
+	if err != nil {
+		var statusErr *rpc.StatusError
+		if errors.As(err, &statusErr) && !errors.Is(err, rpc.ErrStatusBusy) &&
+			!errors.Is(err, rpc.ErrStatusPaymentRequired) {
+			return nil, &TxRejectedError{
+				Reasons: resp.RejectionReasons(),
+				Reason:  resp.Reason,
+				Result:  resp,
+				err:     err,
+			}
+		}
+
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// IsKeyImageSpent checks whether the key images supplied (hex-encoded) have
+// been spent, either by transactions already mined or by those still in the
+// pool.
+//
+// Statuses are in the same order as `keyImages`.
+//
+func (c *Client) IsKeyImageSpent(
+	ctx context.Context, keyImages []string,
+) (*IsKeyImageSpentResult, error) {
+	resp := &IsKeyImageSpentResult{}
+	params := map[string][]string{
+		"key_images": keyImages,
+	}
+
+	err := c.RawRequest(ctx, endpointIsKeyImageSpent, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PopBlocks removes blocks from the top of the chain, sending their
+// transactions back to the pool.
+//
+// (restricted).
+//
+func (c *Client) PopBlocks(
+	ctx context.Context, params PopBlocksRequestParameters,
+) (*PopBlocksResult, error) {
+	resp := &PopBlocksResult{}
+
+	err := c.RawRequest(ctx, endpointPopBlocks, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SaveBc flushes the chain to disk.
+//
+// (restricted).
+//
+func (c *Client) SaveBc(ctx context.Context) (*SaveBcResult, error) {
+	resp := &SaveBcResult{}
+
+	err := c.RawRequest(ctx, endpointSaveBc, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// InPeers retrieves, or updates when `Set` is true, the maximum number of
+// incoming connections.
+//
+// (restricted).
+//
+func (c *Client) InPeers(
+	ctx context.Context, params InPeersRequestParameters,
+) (*InPeersResult, error) {
+	resp := &InPeersResult{}
+
+	err := c.RawRequest(ctx, endpointInPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// OutPeers retrieves, or updates when `Set` is true, the maximum number of
+// outgoing connections.
+//
+// (restricted).
+//
+func (c *Client) OutPeers(
+	ctx context.Context, params OutPeersRequestParameters,
+) (*OutPeersResult, error) {
+	resp := &OutPeersResult{}
+
+	err := c.RawRequest(ctx, endpointOutPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Update checks for, or downloads, an update of monerod.
+//
+// (restricted).
+//
+func (c *Client) Update(
+	ctx context.Context, params UpdateRequestParameters,
+) (*UpdateResult, error) {
+	resp := &UpdateResult{}
+
+	err := c.RawRequest(ctx, endpointUpdate, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetAltBlocksHashes retrieves the hashes of the blocks that the node knows
+// about but are not part of the main chain.
+//
+func (c *Client) GetAltBlocksHashes(ctx context.Context) (*GetAltBlocksHashesResult, error) {
+	resp := &GetAltBlocksHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetAltBlocksHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTransactionPoolHashes retrieves the hashes of the transactions in the
+// pool.
+//
+func (c *Client) GetTransactionPoolHashes(
+	ctx context.Context,
+) (*GetTransactionPoolHashesResult, error) {
+	resp := &GetTransactionPoolHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetTransactionPoolHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
 	return resp, nil
 }
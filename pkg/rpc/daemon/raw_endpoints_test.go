@@ -0,0 +1,160 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestSendRawTransaction(t *testing.T) {
+	spec.Run(t, "SendRawTransaction", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = daemontest.NewServer(daemontest.WithTxValidator(
+				func(blob []byte, sanityChecks bool) []daemon.RejectionReason {
+					switch {
+					case blob[0] == 0xff:
+						return []daemon.RejectionReason{
+							daemon.RejectionFeeTooLow,
+							daemon.RejectionLowMixin,
+						}
+					case sanityChecks && blob[0] == 0xfe:
+						return []daemon.RejectionReason{
+							daemon.RejectionSanityCheckFailed,
+						}
+					}
+
+					return nil
+				},
+			))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("accepts valid transactions", func() {
+			resp, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex:    "0102",
+				DoNotRelay: true,
+			})
+			require.NoError(t, err)
+			assert.True(t, resp.NotRelayed)
+
+			pool, err := client.GetTransactionPool(ctx)
+			require.NoError(t, err)
+			require.Len(t, pool.Transactions, 1)
+			assert.Equal(t, "0102", pool.Transactions[0].TxBlob)
+		})
+
+		it("rejects double spends", func() {
+			params := daemon.SendRawTransactionRequestParameters{TxAsHex: "0102"}
+
+			_, err := client.SendRawTransaction(ctx, params)
+			require.NoError(t, err)
+
+			_, err = client.SendRawTransaction(ctx, params)
+			assert.ErrorIs(t, err, daemon.ErrDoubleSpend)
+			assert.ErrorIs(t, err, rpc.ErrStatusFailed)
+		})
+
+		it("rejects transactions too big", func() {
+			_, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex: strings.Repeat("01", 200000),
+			})
+			assert.ErrorIs(t, err, daemon.ErrTooBig)
+		})
+
+		it("gives all the reasons for a rejection", func() {
+			_, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex: "ff",
+			})
+
+			var rejected *daemon.TxRejectedError
+			require.True(t, errors.As(err, &rejected))
+			assert.Equal(t, []daemon.RejectionReason{
+				daemon.RejectionFeeTooLow,
+				daemon.RejectionLowMixin,
+			}, rejected.Reasons)
+			assert.Equal(t, "fee_too_low", rejected.Reason)
+
+			assert.ErrorIs(t, err, daemon.ErrFeeTooLow)
+			assert.ErrorIs(t, err, daemon.ErrLowMixin)
+			assert.False(t, errors.Is(err, daemon.ErrDoubleSpend))
+		})
+
+		it("performs sanity checks only if asked to", func() {
+			_, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex:        "fe",
+				DoSanityChecks: true,
+			})
+			assert.ErrorIs(t, err, daemon.ErrSanityCheckFailed)
+
+			_, err = client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex: "fe",
+			})
+			assert.NoError(t, err)
+		})
+
+		it("rejects malformed transactions without reasons", func() {
+			_, err := client.SendRawTransaction(ctx, daemon.SendRawTransactionRequestParameters{
+				TxAsHex: "not hex",
+			})
+
+			var rejected *daemon.TxRejectedError
+			require.True(t, errors.As(err, &rejected))
+			assert.Empty(t, rejected.Reasons)
+			assert.NotEmpty(t, rejected.Reason)
+		})
+	}, spec.Report(report.Terminal{}))
+}
+
+func TestIsKeyImageSpent(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		server = daemontest.NewServer()
+		mined  = strings.Repeat("a", 64)
+		pooled = strings.Repeat("b", 64)
+		unseen = strings.Repeat("c", 64)
+	)
+
+	defer server.Close()
+
+	client, err := server.NewClient()
+	require.NoError(t, err)
+
+	server.AddTx(daemontest.Tx{Hash: "aa", KeyImages: []string{mined}})
+	server.GenerateBlocks(1)
+	server.AddTx(daemontest.Tx{Hash: "bb", KeyImages: []string{pooled}})
+
+	resp, err := client.IsKeyImageSpent(ctx, []string{mined, pooled, unseen})
+	require.NoError(t, err)
+
+	assert.Equal(t, []daemon.KeyImageSpentStatus{
+		daemon.KeyImageSpentInChain,
+		daemon.KeyImageSpentInPool,
+		daemon.KeyImageUnspent,
+	}, resp.SpentStatus)
+	assert.Equal(t, "spent in pool", resp.SpentStatus[1].String())
+}
@@ -1,5 +1,14 @@
 package daemon
 
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
 // RPCResultFooter contains the set of fields that every RPC result message
 // will contain.
 //
@@ -25,53 +34,73 @@ type RPCResultFooter struct {
 	TopHash string `json:"top_hash,omitempty"`
 }
 
+// RPCStatus gives the status reported by the daemon, allowing the rpc client
+// to surface non-OK statuses as errors.
+//
+func (f RPCResultFooter) RPCStatus() string {
+	return f.Status
+}
+
+// RPCCredits gives the balance of credits (and the top block hash that it
+// refers to) reported by nodes offering rpc for credits.
+//
+func (f RPCResultFooter) RPCCredits() (uint64, string) {
+	return f.Credits, f.TopHash
+}
+
 // GetAlternateChainsResult is the result of a call to the GetAlternateChains
 // RPC method.
 //
 type GetAlternateChainsResult struct {
 	// Chains is the array of alternate chains seen by the node.
 	//
-	Chains []struct {
-		// BlockHash is the hash of the first diverging block of this
-		// alternative chain.
-		//
-		BlockHash string `json:"block_hash"`
+	Chains []AlternateChain `json:"chains"`
 
-		// BlockHashes TODO
-		//
-		BlockHashes []string `json:"block_hashes"`
+	RPCResultFooter `json:",inline"`
+}
 
-		// Difficulty is the cumulative difficulty of all blocks in the
-		// alternative chain.
-		//
-		Difficulty int64 `json:"difficulty"`
+// AlternateChain is a chain seen by the node that forked off from the main
+// one.
+//
+type AlternateChain struct {
+	// BlockHash is the hash of the top block of this alternative chain.
+	//
+	BlockHash string `json:"block_hash"`
 
-		// DifficultyTop64 is the most-significant 64 bits of the
-		// 128-bit network difficulty.
-		//
-		DifficultyTop64 int `json:"difficulty_top64"`
+	// BlockHashes are the hashes of the blocks of this alternative chain,
+	// from its top block down to the first diverging one.
+	//
+	BlockHashes []string `json:"block_hashes"`
 
-		// Height is the block height of the first diverging block of
-		// this alternative chain.
-		//
-		Height uint64 `json:"height"`
+	// Difficulty is the cumulative difficulty of all blocks in the
+	// alternative chain.
+	//
+	Difficulty int64 `json:"difficulty"`
 
-		// Length is the length in blocks of this alternative chain,
-		// after divergence.
-		//
-		Length uint64 `json:"length"`
+	// DifficultyTop64 is the most-significant 64 bits of the
+	// 128-bit network difficulty.
+	//
+	DifficultyTop64 int `json:"difficulty_top64"`
 
-		// MainChainParentBlock TODO
-		//
-		MainChainParentBlock string `json:"main_chain_parent_block"`
+	// Height is the block height of the top block of this alternative
+	// chain.
+	//
+	Height uint64 `json:"height"`
 
-		// WideDifficulty is the network difficulty as a hexadecimal
-		// string representing a 128-bit number.
-		//
-		WideDifficulty string `json:"wide_difficulty"`
-	} `json:"chains"`
+	// Length is the length in blocks of this alternative chain,
+	// after divergence.
+	//
+	Length uint64 `json:"length"`
 
-	RPCResultFooter `json:",inline"`
+	// MainChainParentBlock is the hash of the block of the main chain
+	// that this alternative chain forked off from.
+	//
+	MainChainParentBlock string `json:"main_chain_parent_block"`
+
+	// WideDifficulty is the network difficulty as a hexadecimal
+	// string representing a 128-bit number.
+	//
+	WideDifficulty string `json:"wide_difficulty"`
 }
 
 // AccessTrackingResult is the result of a call to the RPCAccessTracking RPC
@@ -102,6 +131,114 @@ type RPCAccessTrackingResult struct {
 	RPCResultFooter `json:",inline"`
 }
 
+// RPCAccessInfoResult is the result of a call to the RPCAccessInfo RPC method.
+//
+type RPCAccessInfoResult struct {
+	// HashingBlob is the hex-encoded blob to find a nonce for.
+	//
+	HashingBlob string `json:"hashing_blob"`
+
+	// SeedHeight is the height of the block whose hash is the seed for
+	// the RandomX hashing.
+	//
+	SeedHeight uint64 `json:"seed_height"`
+
+	// SeedHash is the RandomX seed hash.
+	//
+	SeedHash string `json:"seed_hash"`
+
+	// NextSeedHash is the RandomX seed hash to be used next, if about to
+	// change.
+	//
+	NextSeedHash string `json:"next_seed_hash"`
+
+	// Cookie identifies the hashing blob, having to be sent back when
+	// submitting a nonce.
+	//
+	Cookie uint32 `json:"cookie"`
+
+	// Diff is the difficulty that a nonce must meet.
+	//
+	Diff uint64 `json:"diff"`
+
+	// CreditsPerHashFound is the number of credits given for each nonce
+	// meeting the difficulty.
+	//
+	CreditsPerHashFound uint64 `json:"credits_per_hash_found"`
+
+	// Height is the current height of the chain.
+	//
+	Height uint64 `json:"height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessSubmitNonceResult is the result of a call to the
+// RPCAccessSubmitNonce RPC method.
+//
+type RPCAccessSubmitNonceResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessPayResult is the result of a call to the RPCAccessPay RPC method.
+//
+type RPCAccessPayResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessAccountResult is the result of a call to the RPCAccessAccount RPC
+// method, with `Credits` being the balance of the client.
+//
+type RPCAccessAccountResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessDataEntry is the account of a client of a node offering rpc for
+// credits.
+//
+type RPCAccessDataEntry struct {
+	// Client is the hex-encoded public key identifying the client.
+	//
+	Client string `json:"client"`
+
+	// Balance is the number of credits currently available to the
+	// client.
+	//
+	Balance uint64 `json:"balance"`
+
+	// LastUpdateTime is the unix timestamp of the last time the account
+	// has been updated.
+	//
+	LastUpdateTime uint64 `json:"last_update_time"`
+
+	// CreditsTotal is the number of credits ever earned by the client.
+	//
+	CreditsTotal uint64 `json:"credits_total"`
+
+	// CreditsUsed is the number of credits ever spent by the client.
+	//
+	CreditsUsed uint64 `json:"credits_used"`
+
+	NoncesGood  uint64 `json:"nonces_good"`
+	NoncesStale uint64 `json:"nonces_stale"`
+	NoncesBad   uint64 `json:"nonces_bad"`
+	NoncesDupe  uint64 `json:"nonces_dupe"`
+}
+
+// RPCAccessDataResult is the result of a call to the RPCAccessData RPC method.
+//
+type RPCAccessDataResult struct {
+	// Entries are the accounts of all the clients known to the node.
+	//
+	Entries []RPCAccessDataEntry `json:"entries"`
+
+	// Hashrate is the hashrate that clients have been mining at.
+	//
+	Hashrate uint32 `json:"hashrate"`
+
+	RPCResultFooter `json:",inline"`
+}
+
 // HardForkInfoResult is the result of a call to the HardForkInfo RPC method.
 //
 type HardForkInfoResult struct {
@@ -260,6 +397,15 @@ type GetBlockTemplateResult struct {
 	// Difficulty is the difficulty of the next block.
 	Difficulty int64 `json:"difficulty"`
 
+	// WideDifficulty is the difficulty of the next block as a
+	// hex-encoded 128-bit number.
+	//
+	WideDifficulty string `json:"wide_difficulty"`
+
+	// DifficultyTop64 is the upper 64 bits of the 128-bit difficulty.
+	//
+	DifficultyTop64 uint64 `json:"difficulty_top64"`
+
 	// ExpectedReward is the coinbase reward expected to be received if the
 	// block is successfully mined.
 	//
@@ -274,10 +420,136 @@ type GetBlockTemplateResult struct {
 	//
 	PrevHash string `json:"prev_hash"`
 
-	// ReservedOffset TODO
+	// ReservedOffset is the offset (in bytes) in the block template blob
+	// of the area reserved for an extra nonce (see `ReserveSize` in
+	// `GetBlockTemplateRequestParameters`).
 	//
 	ReservedOffset int `json:"reserved_offset"`
 
+	// SeedHeight is the height of the block whose hash is the RandomX
+	// seed.
+	//
+	SeedHeight uint64 `json:"seed_height"`
+
+	// SeedHash is the RandomX seed hash.
+	//
+	SeedHash string `json:"seed_hash"`
+
+	// NextSeedHash is the RandomX seed hash to be used next, if about to
+	// change.
+	//
+	NextSeedHash string `json:"next_seed_hash"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// WithExtraNonce gives the (hex-encoded) block template blob with
+// `extraNonce` written to the area reserved for it at `ReservedOffset`.
+//
+// ps.: `extraNonce` must not be bigger than the `ReserveSize` the template has
+// been requested with, which the daemon doesn't report back.
+//
+func (r *GetBlockTemplateResult) WithExtraNonce(extraNonce []byte) (string, error) {
+	blob, err := hex.DecodeString(r.BlocktemplateBlob)
+	if err != nil {
+		return "", fmt.Errorf("decode blocktemplate blob: %w", err)
+	}
+
+	if r.ReservedOffset < 0 || r.ReservedOffset+len(extraNonce) > len(blob) {
+		return "", fmt.Errorf("extra nonce of %d bytes at offset %d "+
+			"doesn't fit in blob of %d bytes",
+			len(extraNonce), r.ReservedOffset, len(blob))
+	}
+
+	copy(blob[r.ReservedOffset:], extraNonce)
+
+	return hex.EncodeToString(blob), nil
+}
+
+// SubmitBlockResult is the result of a call to the SubmitBlock RPC method.
+//
+type SubmitBlockResult struct {
+	// BlockID is the hash of the block accepted.
+	//
+	BlockID string `json:"block_id"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// MinerDataTx is a transaction in the pool as reported by GetMinerData.
+//
+type MinerDataTx struct {
+	ID     string `json:"id"`
+	Weight uint64 `json:"weight"`
+	Fee    uint64 `json:"fee"`
+}
+
+// GetMinerDataResult is the result of a call to the GetMinerData RPC method.
+//
+type GetMinerDataResult struct {
+	// MajorVersion is the major version of the next block.
+	//
+	MajorVersion uint8 `json:"major_version"`
+
+	// Height is the height of the next block.
+	//
+	Height uint64 `json:"height"`
+
+	// PrevID is the hash of the block to mine on top of.
+	//
+	PrevID string `json:"prev_id"`
+
+	// SeedHash is the RandomX seed hash for the next block.
+	//
+	SeedHash string `json:"seed_hash"`
+
+	// Difficulty is the (hex-encoded, 128-bit) difficulty of the next
+	// block.
+	//
+	Difficulty string `json:"difficulty"`
+
+	// MedianWeight is the median weight of the last blocks, used for
+	// computing the block reward.
+	//
+	MedianWeight uint64 `json:"median_weight"`
+
+	// AlreadyGeneratedCoins is the number of atomic units emitted so far.
+	//
+	AlreadyGeneratedCoins uint64 `json:"already_generated_coins"`
+
+	// TxBacklog are the transactions in the pool that could go in the
+	// next block.
+	//
+	TxBacklog []MinerDataTx `json:"tx_backlog"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// AddAuxPowResult is the result of a call to the AddAuxPow RPC method.
+//
+type AddAuxPowResult struct {
+	// BlocktemplateBlob is the block template with the merge mining
+	// commitment.
+	//
+	BlocktemplateBlob string `json:"blocktemplate_blob"`
+
+	// BlockhashingBlob is the hashing blob of the new block template.
+	//
+	BlockhashingBlob string `json:"blockhashing_blob"`
+
+	// MerkleRoot is the root of the merkle tree of the merge-mined
+	// chains' blocks.
+	//
+	MerkleRoot string `json:"merkle_root"`
+
+	// MerkleTreeDepth is the depth of the merkle tree.
+	//
+	MerkleTreeDepth uint32 `json:"merkle_tree_depth"`
+
+	// AuxPow are the merge-mined chains' blocks committed to.
+	//
+	AuxPow []AuxPow `json:"aux_pow"`
+
 	RPCResultFooter `json:",inline"`
 }
 
@@ -544,97 +816,15 @@ type GetBlockResult struct {
 
 // GetBlockResultJSON is the internal json-formatted block information.
 //
-type GetBlockResultJSON struct {
-	// MajorVersion (same as in the block header)
-	//
-	MajorVersion uint `json:"major_version"`
-
-	// MinorVersion (same as in the block header)
-	//
-	MinorVersion uint `json:"minor_version"`
-
-	// Timestamp (same as in the block header)
-	//
-	Timestamp uint64 `json:"timestamp"`
-
-	// PrevID (same as `block_hash` in the block header)
-	//
-	PrevID string `json:"prev_id"`
-
-	// Nonce (same as in the block header)
-	//
-	Nonce int `json:"nonce"`
-
-	// MinerTx contains the miner transaction information.
-	//
-	MinerTx struct {
-		// Version is the transaction version number
-		//
-		Version int `json:"version"`
-
-		// UnlockTime is the block height when the coinbase transaction
-		// becomes spendable.
-		//
-		UnlockTime int `json:"unlock_time"`
-
-		// Vin lists the transaction inputs.
-		//
-		Vin []struct {
-			Gen struct {
-				Height int `json:"height"`
-			} `json:"gen"`
-		} `json:"vin"`
-
-		// Vout lists the transaction outputs.
-		//
-		Vout []struct {
-			Amount uint64 `json:"amount"`
-			Target struct {
-				Key string `json:"key"`
-			} `json:"target"`
-		} `json:"vout"`
-		// Extra (aka the transaction id) can be used to include any
-		// random 32byte/64char hex string.
-		//
-		Extra []int `json:"extra"`
-
-		// RctSignatures contain the signatures of tx signers.
-		//
-		// ps.: coinbase txs DO NOT have signatures.
-		//
-		RctSignatures struct {
-			Type int `json:"type"`
-		} `json:"rct_signatures"`
-	} `json:"miner_tx"`
-
-	// TxHashes is the list of hashes of non-coinbase transactions in the
-	// block.
-	//
-	TxHashes []string `json:"tx_hashes"`
-}
-
-func (c *GetBlockResultJSON) MinerOutputs() uint64 {
-	res := uint64(0)
-
-	for _, vout := range c.MinerTx.Vout {
-		res += vout.Amount
-	}
-
-	return res
-}
+type GetBlockResultJSON = monero.Block
 
 // SyncInfoResult is the result of a call to the SyncInfo RPC method.
 //
 type SyncInfoResult struct {
-	Credits uint64 `json:"credits"`
-
 	Height                uint64 `json:"height"`
 	NextNeededPruningSeed uint64 `json:"next_needed_pruning_seed"`
 	Overview              string `json:"overview"`
-	Status                string `json:"status"`
 	TargetHeight          uint64 `json:"target_height"`
-	TopHash               string `json:"top_hash"`
-	Untrusted             bool   `json:"untrusted"`
 	Peers                 []struct {
 		Info struct {
 			Address           string `json:"address"`
@@ -766,71 +956,27 @@ type GetTransactionsResultTransaction struct {
 }
 
 type GetTransactionsResult struct {
-	Credits   int                                `json:"credits"`
-	Status    string                             `json:"status"`
-	TopHash   string                             `json:"top_hash"`
-	Txs       []GetTransactionsResultTransaction `json:"txs"`
-	TxsAsHex  []string                           `json:"txs_as_hex"`
-	Untrusted bool                               `json:"untrusted"`
-}
-
-type TransactionJSON struct {
-	Version    int `json:"version"`
-	UnlockTime int `json:"unlock_time"`
-	Vin        []struct {
-		Key struct {
-			Amount     int    `json:"amount"`
-			KeyOffsets []uint `json:"key_offsets"`
-			KImage     string `json:"k_image"`
-		} `json:"key"`
-	} `json:"vin"`
-	Vout []struct {
-		Amount uint64 `json:"amount"`
-		Target struct {
-			Key string `json:"key"`
-		} `json:"target"`
-	} `json:"vout"`
-	Extra         []byte `json:"extra"`
-	RctSignatures struct {
-		Type     int    `json:"type"`
-		Txnfee   uint64 `json:"txnFee"`
-		Ecdhinfo []struct {
-			Amount string `json:"amount"`
-		} `json:"ecdhInfo"`
-		Outpk []string `json:"outPk"`
-	} `json:"rct_signatures"`
-	RctsigPrunable struct {
-		Nbp int `json:"nbp"`
-		Bp  []struct {
-			A      string   `json:"A"`
-			S      string   `json:"S"`
-			T1     string   `json:"T1"`
-			T2     string   `json:"T2"`
-			Taux   string   `json:"taux"`
-			Mu     string   `json:"mu"`
-			L      []string `json:"L"`
-			R      []string `json:"R"`
-			LowerA string   `json:"a"`
-			B      string   `json:"b"`
-			T      string   `json:"t"`
-		} `json:"bp"`
-		Clsags []struct {
-			S  []string `json:"s"`
-			C1 string   `json:"c1"`
-			D  string   `json:"D"`
-		} `json:"CLSAGs"`
-		Pseudoouts []string `json:"pseudoOuts"`
-	} `json:"rctsig_prunable"`
+	Txs      []GetTransactionsResultTransaction `json:"txs"`
+	TxsAsHex []string                           `json:"txs_as_hex"`
+
+	// MissedTx lists the hashes of the transactions requested that the
+	// node doesn't know about.
+	//
+	MissedTx []string `json:"missed_tx,omitempty"`
+
+	RPCResultFooter `json:",inline"`
 }
 
+// TransactionJSON is the json-formatted transaction information (the
+// `as_json` field of `/get_transactions`).
+//
+type TransactionJSON = monero.Transaction
+
 type GetTransactionPoolResult struct {
-	Credits        int `json:"credits"`
 	SpentKeyImages []struct {
 		IDHash    string   `json:"id_hash"`
 		TxsHashes []string `json:"txs_hashes"`
 	} `json:"spent_key_images"`
-	Status       string `json:"status"`
-	TopHash      string `json:"top_hash"`
 	Transactions []struct {
 		BlobSize           uint64 `json:"blob_size"`
 		DoNotRelay         bool   `json:"do_not_relay"`
@@ -849,7 +995,8 @@ type GetTransactionPoolResult struct {
 		TxJSON             string `json:"tx_json"`
 		Weight             uint64 `json:"weight"`
 	} `json:"transactions"`
-	Untrusted bool `json:"untrusted"`
+
+	RPCResultFooter `json:",inline"`
 }
 
 type SetLogCategoriesRequestParameters struct {
@@ -925,3 +1072,673 @@ type StartMiningResult struct {
 type StopMiningResult struct {
 	RPCResultFooter `json:",inline"`
 }
+
+type GetBlocksBinRequestParameters struct {
+	// BlockIDs is a list of block hashes from the most recent to the
+	// oldest, which must end with the genesis block.
+	//
+	BlockIDs []string
+
+	// StartHeight is the minimum height from which to start retrieving
+	// blocks.
+	//
+	StartHeight uint64
+
+	// Prune indicates whether the prunable part of the transactions
+	// should be left out.
+	//
+	Prune bool
+
+	// NoMinerTx indicates whether the miner transactions should be left
+	// out.
+	//
+	NoMinerTx bool
+}
+
+// BlockCompleteEntry is a block in binary form alongside its transactions.
+//
+type BlockCompleteEntry struct {
+	// Pruned indicates whether the transactions are pruned.
+	//
+	Pruned bool `json:"pruned"`
+
+	// Block is the binary form of the block.
+	//
+	Block []byte `json:"block"`
+
+	// BlockWeight is the weight of the block (only filled when pruned).
+	//
+	BlockWeight uint64 `json:"block_weight"`
+
+	// Txs are the transactions included in the block (not including the
+	// miner transaction).
+	//
+	Txs []TxBlobEntry `json:"txs"`
+}
+
+// TxBlobEntry is a transaction in binary form.
+//
+type TxBlobEntry struct {
+	// Blob is the binary form of the transaction (without the prunable
+	// part when pruned).
+	//
+	Blob []byte `json:"blob"`
+
+	// PrunableHash is the hash of the prunable part of the transaction
+	// (only filled when pruned).
+	//
+	PrunableHash string `json:"prunable_hash,omitempty"`
+}
+
+type GetBlocksBinResult struct {
+	Blocks        []BlockCompleteEntry `json:"blocks"`
+	StartHeight   uint64               `json:"start_height"`
+	CurrentHeight uint64               `json:"current_height"`
+
+	// OutputIndices are the global output indexes of the outputs of
+	// each transaction (miner tx first) of each block.
+	//
+	OutputIndices [][][]uint64 `json:"output_indices"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetBlocksByHeightBinResult struct {
+	Blocks []BlockCompleteEntry `json:"blocks"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetHashesBinResult struct {
+	BlockIDs      []string `json:"m_block_ids"`
+	StartHeight   uint64   `json:"start_height"`
+	CurrentHeight uint64   `json:"current_height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetOIndexesBinResult struct {
+	OIndexes []uint64 `json:"o_indexes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetOutsBinOutput struct {
+	Amount uint64 `json:"amount"`
+	Index  uint64 `json:"index"`
+}
+
+type OutKey struct {
+	Key      string `json:"key"`
+	Mask     string `json:"mask"`
+	Unlocked bool   `json:"unlocked"`
+	Height   uint64 `json:"height"`
+	TxID     string `json:"txid"`
+}
+
+type GetOutsBinResult struct {
+	Outs []OutKey `json:"outs"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetOutputDistributionRequestParameters struct {
+	// Amounts are the amounts to look for (0 for RingCT outputs).
+	//
+	Amounts []uint64 `json:"amounts"`
+
+	// FromHeight is the starting height to check from.
+	//
+	FromHeight uint64 `json:"from_height"`
+
+	// ToHeight is the ending height to check up to.
+	//
+	ToHeight uint64 `json:"to_height"`
+
+	// Cumulative indicates whether the distribution should be
+	// cumulative.
+	//
+	Cumulative bool `json:"cumulative"`
+
+	// Binary indicates whether the distribution should be sent in binary
+	// form rather than as a JSON array. It's ignored by
+	// `GetOutputDistributionBin`, which always asks for it.
+	//
+	Binary bool `json:"binary"`
+
+	// Compress indicates whether the distribution should be compressed
+	// by the daemon (only meaningful for binary distributions).
+	//
+	Compress bool `json:"compress"`
+}
+
+// Distribution is the number of outputs of a given amount created at each
+// height starting from `StartHeight`.
+//
+// When `Cumulative` is requested, each entry is the total number of outputs
+// created up to that height, `Base` being the number of outputs created
+// before `StartHeight`.
+//
+type Distribution struct {
+	Amount       uint64   `json:"amount"`
+	StartHeight  uint64   `json:"start_height"`
+	Base         uint64   `json:"base"`
+	Distribution []uint64 `json:"distribution"`
+}
+
+// UnmarshalJSON decodes a distribution in any of the forms the daemon may
+// send it: a plain JSON array, or a blob of little-endian integers
+// (optionally compressed as varints) embedded in a JSON string.
+//
+func (d *Distribution) UnmarshalJSON(b []byte) error {
+	raw := struct {
+		Amount         uint64          `json:"amount"`
+		StartHeight    uint64          `json:"start_height"`
+		Base           uint64          `json:"base"`
+		Binary         bool            `json:"binary"`
+		Compress       bool            `json:"compress"`
+		Distribution   json.RawMessage `json:"distribution"`
+		CompressedData json.RawMessage `json:"compressed_data"`
+	}{}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*d = Distribution{
+		Amount:      raw.Amount,
+		StartHeight: raw.StartHeight,
+		Base:        raw.Base,
+	}
+
+	switch {
+	case !raw.Binary:
+		if len(raw.Distribution) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(raw.Distribution, &d.Distribution)
+	case raw.Compress:
+		blob, err := unquoteBlob(raw.CompressedData)
+		if err != nil {
+			return fmt.Errorf("compressed data: %w", err)
+		}
+
+		v, err := decodeCompressedDistribution(blob)
+		if err != nil {
+			return fmt.Errorf("decompress: %w", err)
+		}
+
+		d.Distribution = v
+	default:
+		blob, err := unquoteBlob(raw.Distribution)
+		if err != nil {
+			return fmt.Errorf("distribution: %w", err)
+		}
+
+		v, err := decodeDistributionBlob(blob)
+		if err != nil {
+			return err
+		}
+
+		d.Distribution = v
+	}
+
+	return nil
+}
+
+type GetOutputDistributionResult struct {
+	Distributions []Distribution `json:"distributions"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type GetOutputHistogramRequestParameters struct {
+	// Amounts are the amounts to look for. When empty, every amount known
+	// to the daemon is considered.
+	//
+	Amounts []uint64 `json:"amounts"`
+
+	// MinCount is the minimum number of outputs of an amount for it to
+	// be included.
+	//
+	MinCount uint64 `json:"min_count"`
+
+	// MaxCount is the maximum number of outputs of an amount for it to be
+	// included (0 for no limit).
+	//
+	MaxCount uint64 `json:"max_count"`
+
+	// Unlocked indicates whether only spendable outputs should be
+	// counted.
+	//
+	Unlocked bool `json:"unlocked"`
+
+	// RecentCutoff is the unix timestamp from which outputs are
+	// considered recent.
+	//
+	RecentCutoff uint64 `json:"recent_cutoff"`
+}
+
+// HistogramEntry is the number of outputs of a given amount known to the
+// daemon.
+//
+type HistogramEntry struct {
+	Amount            uint64 `json:"amount"`
+	TotalInstances    uint64 `json:"total_instances"`
+	UnlockedInstances uint64 `json:"unlocked_instances"`
+	RecentInstances   uint64 `json:"recent_instances"`
+}
+
+type GetOutputHistogramResult struct {
+	Histogram []HistogramEntry `json:"histogram"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SendRawTransactionResult is the result of a call to the SendRawTransaction
+// RPC endpoint, with the flags indicating why the transaction has been
+// rejected, if so.
+//
+type SendRawTransactionResult struct {
+	// Reason is the reason given by the daemon for rejecting the
+	// transaction, if any.
+	//
+	Reason string `json:"reason"`
+
+	// NotRelayed indicates that the transaction has not been relayed to
+	// the network (e.g., due to `do_not_relay`).
+	//
+	NotRelayed bool `json:"not_relayed"`
+
+	DoubleSpend       bool `json:"double_spend"`
+	FeeTooLow         bool `json:"fee_too_low"`
+	InvalidInput      bool `json:"invalid_input"`
+	InvalidOutput     bool `json:"invalid_output"`
+	LowMixin          bool `json:"low_mixin"`
+	NonzeroUnlockTime bool `json:"nonzero_unlock_time"`
+	Overspend         bool `json:"overspend"`
+	SanityCheckFailed bool `json:"sanity_check_failed"`
+	TooBig            bool `json:"too_big"`
+	TooFewOutputs     bool `json:"too_few_outputs"`
+	TxExtraTooBig     bool `json:"tx_extra_too_big"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// RejectionReasons gives the reasons for the transaction having been rejected
+// according to the flags set in the result.
+//
+func (r *SendRawTransactionResult) RejectionReasons() []RejectionReason {
+	reasons := []RejectionReason{}
+
+	for _, flag := range []struct {
+		set    bool
+		reason RejectionReason
+	}{
+		{r.DoubleSpend, RejectionDoubleSpend},
+		{r.FeeTooLow, RejectionFeeTooLow},
+		{r.InvalidInput, RejectionInvalidInput},
+		{r.InvalidOutput, RejectionInvalidOutput},
+		{r.LowMixin, RejectionLowMixin},
+		{r.NonzeroUnlockTime, RejectionNonzeroUnlockTime},
+		{r.Overspend, RejectionOverspend},
+		{r.SanityCheckFailed, RejectionSanityCheckFailed},
+		{r.TooBig, RejectionTooBig},
+		{r.TooFewOutputs, RejectionTooFewOutputs},
+		{r.TxExtraTooBig, RejectionTxExtraTooBig},
+	} {
+		if flag.set {
+			reasons = append(reasons, flag.reason)
+		}
+	}
+
+	return reasons
+}
+
+// KeyImageSpentStatus is the status of a key image as reported by the
+// IsKeyImageSpent RPC endpoint.
+//
+type KeyImageSpentStatus int
+
+const (
+	// KeyImageUnspent indicates that the key image has not been seen
+	// either in the chain or in the transaction pool.
+	//
+	KeyImageUnspent KeyImageSpentStatus = iota
+
+	// KeyImageSpentInChain indicates that the key image has been spent
+	// by a transaction already mined.
+	//
+	KeyImageSpentInChain
+
+	// KeyImageSpentInPool indicates that the key image has been spent by
+	// a transaction still in the transaction pool.
+	//
+	KeyImageSpentInPool
+)
+
+func (s KeyImageSpentStatus) String() string {
+	switch s {
+	case KeyImageUnspent:
+		return "unspent"
+	case KeyImageSpentInChain:
+		return "spent"
+	case KeyImageSpentInPool:
+		return "spent in pool"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// IsKeyImageSpentResult is the result of a call to the IsKeyImageSpent RPC
+// endpoint.
+//
+type IsKeyImageSpentResult struct {
+	// SpentStatus is the status of each of the key images checked, in
+	// the same order as they've been supplied.
+	//
+	SpentStatus []KeyImageSpentStatus `json:"spent_status"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type FlushTxpoolRequestParameters struct {
+	// TxIDs are the hashes of the transactions to drop from the pool. All
+	// of the transactions are dropped if none is given.
+	//
+	TxIDs []string `json:"txids,omitempty"`
+}
+
+// FlushTxpoolResult is the result of a call to the FlushTxpool RPC method.
+//
+type FlushTxpoolResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+type FlushCacheRequestParameters struct {
+	// BadTxs indicates whether the cache of transactions deemed invalid
+	// should be flushed.
+	//
+	BadTxs bool `json:"bad_txs"`
+
+	// BadBlocks indicates whether the cache of blocks deemed invalid
+	// should be flushed.
+	//
+	BadBlocks bool `json:"bad_blocks"`
+}
+
+// FlushCacheResult is the result of a call to the FlushCache RPC method.
+//
+type FlushCacheResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+type PopBlocksRequestParameters struct {
+	// NBlocks is the number of blocks to remove from the top of the
+	// chain.
+	//
+	NBlocks uint64 `json:"nblocks"`
+}
+
+// PopBlocksResult is the result of a call to the PopBlocks RPC endpoint.
+//
+type PopBlocksResult struct {
+	// Height is the height of the chain once the blocks have been
+	// removed.
+	//
+	Height uint64 `json:"height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type PruneBlockchainRequestParameters struct {
+	// Check indicates whether the daemon should only report on the
+	// current pruning state rather than pruning the chain.
+	//
+	Check bool `json:"check"`
+}
+
+// PruneBlockchainResult is the result of a call to the PruneBlockchain RPC
+// method.
+//
+type PruneBlockchainResult struct {
+	// Pruned indicates whether the chain is pruned.
+	//
+	Pruned bool `json:"pruned"`
+
+	// PruningSeed is the seed determining which stripe of the chain the
+	// node keeps (0 if not pruned).
+	//
+	PruningSeed uint32 `json:"pruning_seed"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SaveBcResult is the result of a call to the SaveBc RPC endpoint.
+//
+type SaveBcResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+type InPeersRequestParameters struct {
+	// Set indicates whether the limit should be updated to `InPeers`
+	// (otherwise, the current one is only reported).
+	//
+	Set bool `json:"set"`
+
+	// InPeers is the maximum number of incoming connections.
+	//
+	InPeers uint32 `json:"in_peers"`
+}
+
+// InPeersResult is the result of a call to the InPeers RPC endpoint.
+//
+type InPeersResult struct {
+	// InPeers is the maximum number of incoming connections in effect.
+	//
+	InPeers uint32 `json:"in_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type OutPeersRequestParameters struct {
+	// Set indicates whether the limit should be updated to `OutPeers`
+	// (otherwise, the current one is only reported).
+	//
+	Set bool `json:"set"`
+
+	// OutPeers is the maximum number of outgoing connections.
+	//
+	OutPeers uint32 `json:"out_peers"`
+}
+
+// OutPeersResult is the result of a call to the OutPeers RPC endpoint.
+//
+type OutPeersResult struct {
+	// OutPeers is the maximum number of outgoing connections in effect.
+	//
+	OutPeers uint32 `json:"out_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// UpdateCommand is what the daemon is asked to do about updates.
+//
+type UpdateCommand string
+
+const (
+	// UpdateCheck only checks whether an update is available.
+	//
+	UpdateCheck UpdateCommand = "check"
+
+	// UpdateDownload downloads the update, if any.
+	//
+	UpdateDownload UpdateCommand = "download"
+)
+
+type UpdateRequestParameters struct {
+	// Command is either `UpdateCheck` or `UpdateDownload`.
+	//
+	Command UpdateCommand `json:"command"`
+
+	// Path is where the update should be downloaded to (defaults to the
+	// daemon's data directory).
+	//
+	Path string `json:"path,omitempty"`
+}
+
+// UpdateResult is the result of a call to the Update RPC endpoint.
+//
+type UpdateResult struct {
+	// Update indicates whether an update is available.
+	//
+	Update bool `json:"update"`
+
+	// Version is the version available.
+	//
+	Version string `json:"version"`
+
+	// UserURI is the URI of the update for users to download.
+	//
+	UserURI string `json:"user_uri"`
+
+	// AutoURI is the URI of the update for automatic downloads.
+	//
+	AutoURI string `json:"auto_uri"`
+
+	// Hash is the hash of the update.
+	//
+	Hash string `json:"hash"`
+
+	// Path is where the update has been downloaded to.
+	//
+	Path string `json:"path"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// TxpoolBacklogEntry describes a transaction in the pool.
+//
+type TxpoolBacklogEntry struct {
+	Weight     uint64 `json:"weight"`
+	Fee        uint64 `json:"fee"`
+	TimeInPool uint64 `json:"time_in_pool"`
+}
+
+// txpoolBacklogEntrySize is the size of an entry of the backlog as packed
+// by the daemon.
+//
+const txpoolBacklogEntrySize = 24
+
+// GetTxpoolBacklogResult is the result of a call to the GetTxpoolBacklog RPC
+// method.
+//
+type GetTxpoolBacklogResult struct {
+	// Backlog has an entry for each transaction in the pool.
+	//
+	Backlog []TxpoolBacklogEntry `json:"backlog"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// UnmarshalJSON decodes the backlog either as a JSON array, or packed in a
+// blob the way the daemon sends it.
+//
+func (r *GetTxpoolBacklogResult) UnmarshalJSON(b []byte) error {
+	raw := struct {
+		Backlog json.RawMessage `json:"backlog"`
+
+		RPCResultFooter
+	}{}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*r = GetTxpoolBacklogResult{RPCResultFooter: raw.RPCResultFooter}
+
+	if len(raw.Backlog) != 0 && raw.Backlog[0] == '[' {
+		return json.Unmarshal(raw.Backlog, &r.Backlog)
+	}
+
+	blob, err := unquoteBlob(raw.Backlog)
+	if err != nil {
+		return fmt.Errorf("backlog: %w", err)
+	}
+
+	if len(blob)%txpoolBacklogEntrySize != 0 {
+		return fmt.Errorf("backlog blob size %d not multiple of %d",
+			len(blob), txpoolBacklogEntrySize)
+	}
+
+	for ; len(blob) > 0; blob = blob[txpoolBacklogEntrySize:] {
+		r.Backlog = append(r.Backlog, TxpoolBacklogEntry{
+			Weight:     binary.LittleEndian.Uint64(blob),
+			Fee:        binary.LittleEndian.Uint64(blob[8:]),
+			TimeInPool: binary.LittleEndian.Uint64(blob[16:]),
+		})
+	}
+
+	return nil
+}
+
+// GetAltBlocksHashesResult is the result of a call to the GetAltBlocksHashes
+// RPC endpoint.
+//
+type GetAltBlocksHashesResult struct {
+	// BlksHashes are the hashes of the blocks known to the node that
+	// are not part of the main chain.
+	//
+	BlksHashes []string `json:"blks_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetTransactionPoolHashesResult is the result of a call to the
+// GetTransactionPoolHashes RPC endpoint.
+//
+type GetTransactionPoolHashesResult struct {
+	// TxHashes are the hashes of the transactions in the pool.
+	//
+	TxHashes []string `json:"tx_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// BannedResult is the result of a call to the Banned RPC method.
+//
+type BannedResult struct {
+	// Banned indicates whether the address is banned.
+	//
+	Banned bool `json:"banned"`
+
+	// Seconds is how long the ban still lasts for.
+	//
+	Seconds uint32 `json:"seconds"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+type SetBootstrapDaemonRequestParameters struct {
+	// Address is the address of the daemon to use while syncing, "auto"
+	// for having one picked amongst public nodes, or empty for not using
+	// any.
+	//
+	Address string `json:"address"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Proxy is the address of a proxy to reach the bootstrap daemon
+	// through.
+	//
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// SetBootstrapDaemonResult is the result of a call to the SetBootstrapDaemon
+// RPC method.
+//
+type SetBootstrapDaemonResult struct {
+	RPCResultFooter `json:",inline"`
+}
@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+// Verify checks that every transaction in the result hashes to what the
+// daemon claims it does, failing with `ErrHashMismatch` otherwise (see
+// `GetTransactionsResultTransaction.Verify`).
+//
+func (r *GetTransactionsResult) Verify() error {
+	for idx := range r.Txs {
+		if err := r.Txs[idx].Verify(); err != nil {
+			return fmt.Errorf("tx %s: %w", r.Txs[idx].TxHash, err)
+		}
+	}
+
+	return nil
+}
+
+// Verify checks that the transaction hashes to `TxHash`, computing it from
+// its blob (either `AsHex`, or `PrunedAsHex` along with either
+// `PrunableAsHex` or `PrunableHash`), and that its JSON representation, if
+// any, has the same prefix, failing with `ErrHashMismatch` otherwise.
+//
+func (t *GetTransactionsResultTransaction) Verify() error {
+	txn, err := t.decode()
+	if err != nil {
+		return err
+	}
+
+	var hash string
+
+	if txn.Version > 1 && txn.RctSigPrunable == nil && t.PrunableHash != "" {
+		hash, err = txn.HashWithPrunableHash(t.PrunableHash)
+	} else {
+		hash, err = txn.Hash()
+	}
+
+	if err != nil {
+		return fmt.Errorf("hash: %w", err)
+	}
+
+	if hash != t.TxHash {
+		return fmt.Errorf("%w: blob hashes to %s", ErrHashMismatch, hash)
+	}
+
+	if t.AsJSON == "" {
+		return nil
+	}
+
+	txnJSON := &TransactionJSON{}
+	if err := json.Unmarshal([]byte(t.AsJSON), txnJSON); err != nil {
+		return fmt.Errorf("unmarshal as_json: %w", err)
+	}
+
+	return samePrefix(txn, txnJSON)
+}
+
+// decode decodes the transaction from whichever blob the daemon included.
+//
+func (t *GetTransactionsResultTransaction) decode() (*monero.Transaction, error) {
+	blobHex := t.AsHex
+	if blobHex == "" {
+		blobHex = t.PrunedAsHex + t.PrunableAsHex
+	}
+
+	if blobHex == "" {
+		return nil, fmt.Errorf("no blob to verify")
+	}
+
+	blob, err := hex.DecodeString(blobHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode hex: %w", err)
+	}
+
+	return monero.DecodeTransaction(blob)
+}
+
+// Verify checks that the block hashes to the hash in its header, computing
+// it from its blob as well as from its JSON representation, and that its
+// coinbase transaction hashes to `MinerTxHash`, failing with
+// `ErrHashMismatch` otherwise.
+//
+func (r *GetBlockResult) Verify() error {
+	blob, err := hex.DecodeString(r.Blob)
+	if err != nil {
+		return fmt.Errorf("decode hex: %w", err)
+	}
+
+	block, err := monero.DecodeBlock(blob)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyBlock(block, r.BlockHeader.Hash, r.MinerTxHash); err != nil {
+		return fmt.Errorf("blob: %w", err)
+	}
+
+	if r.JSON == "" {
+		return nil
+	}
+
+	blockJSON, err := r.InnerJSON()
+	if err != nil {
+		return fmt.Errorf("inner json: %w", err)
+	}
+
+	if err := verifyBlock(blockJSON, r.BlockHeader.Hash, r.MinerTxHash); err != nil {
+		return fmt.Errorf("json: %w", err)
+	}
+
+	return nil
+}
+
+func verifyBlock(block *monero.Block, hash, minerTxHash string) error {
+	id, err := block.Hash()
+	if err != nil {
+		return fmt.Errorf("hash: %w", err)
+	}
+
+	if id != hash {
+		return fmt.Errorf("%w: block %s hashes to %s", ErrHashMismatch, hash, id)
+	}
+
+	if minerTxHash == "" {
+		return nil
+	}
+
+	computed, err := block.MinerTx.Hash()
+	if err != nil {
+		return fmt.Errorf("miner tx hash: %w", err)
+	}
+
+	if computed != minerTxHash {
+		return fmt.Errorf("%w: miner tx %s hashes to %s",
+			ErrHashMismatch, minerTxHash, computed)
+	}
+
+	return nil
+}
+
+// samePrefix makes sure that the JSON representation of a transaction
+// matches the one decoded from its blob, comparing their prefixes (what's
+// signed).
+//
+func samePrefix(txn, txnJSON *monero.Transaction) error {
+	expected, err := txn.PrefixHash()
+	if err != nil {
+		return fmt.Errorf("prefix hash: %w", err)
+	}
+
+	actual, err := txnJSON.PrefixHash()
+	if err != nil {
+		return fmt.Errorf("as_json prefix hash: %w", err)
+	}
+
+	if actual != expected {
+		return fmt.Errorf("%w: as_json prefix hashes to %s rather than %s",
+			ErrHashMismatch, actual, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+func coinbase(height uint64) monero.Transaction {
+	return monero.Transaction{
+		Version:    2,
+		UnlockTime: height + 60,
+		Vin: []monero.TxInput{
+			{Gen: &monero.TxInputGen{Height: height}},
+		},
+		Vout: []monero.TxOutput{
+			{
+				Amount: 600000000000,
+				Target: monero.TxOutputTarget{Key: strings.Repeat("ab", 32)},
+			},
+		},
+		Extra: append([]byte{monero.TxExtraTagPublicKey},
+			make([]byte, monero.KeySize)...),
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+// blockResult is what a daemon would reply to `get_block` for `block`.
+//
+func blockResult(t *testing.T, block monero.Block) *daemon.GetBlockResult {
+	b, err := block.Encode()
+	require.NoError(t, err)
+
+	id, err := block.Hash()
+	require.NoError(t, err)
+
+	minerTxHash, err := block.MinerTx.Hash()
+	require.NoError(t, err)
+
+	return &daemon.GetBlockResult{
+		Blob:        hex.EncodeToString(b),
+		BlockHeader: daemon.BlockHeader{Hash: id},
+		JSON:        mustJSON(t, block),
+		MinerTxHash: minerTxHash,
+	}
+}
+
+func testBlock(height uint64) monero.Block {
+	return monero.Block{
+		MajorVersion: 16,
+		MinorVersion: 16,
+		Timestamp:    1660000000,
+		PrevID:       strings.Repeat("cd", 32),
+		Nonce:        42,
+		MinerTx:      coinbase(height),
+		TxHashes:     []string{strings.Repeat("ef", 32)},
+	}
+}
+
+// nolint:funlen
+func TestVerification(t *testing.T) {
+	spec.Run(t, "WithVerification", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+
+			txn  monero.Transaction
+			hash string
+			blob string
+		)
+
+		it.Before(func() {
+			server = daemontest.NewServer()
+
+			c, err := server.NewClient()
+			require.NoError(t, err)
+
+			client = daemon.NewClient(c.Requester, daemon.WithVerification())
+
+			txn = coinbase(100)
+
+			b, err := txn.Encode()
+			require.NoError(t, err)
+
+			blob = hex.EncodeToString(b)
+
+			hash, err = txn.Hash()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		when("retrieving transactions", func() {
+			it("accepts transactions matching their hash", func() {
+				server.AddTx(daemontest.Tx{
+					Hash: hash,
+					Blob: blob,
+					JSON: mustJSON(t, txn),
+				})
+
+				resp, err := client.GetTransactions(ctx, []string{hash})
+				require.NoError(t, err)
+				require.Len(t, resp.Txs, 1)
+			})
+
+			it("rejects transactions not matching their hash", func() {
+				wrong := strings.Repeat("00", 32)
+
+				server.AddTx(daemontest.Tx{Hash: wrong, Blob: blob})
+
+				_, err := client.GetTransactions(ctx, []string{wrong})
+				assert.ErrorIs(t, err, daemon.ErrHashMismatch)
+			})
+
+			it("rejects transactions with a json not matching their blob", func() {
+				tampered := txn
+				tampered.UnlockTime++
+
+				server.AddTx(daemontest.Tx{
+					Hash: hash,
+					Blob: blob,
+					JSON: mustJSON(t, tampered),
+				})
+
+				_, err := client.GetTransactions(ctx, []string{hash})
+				assert.ErrorIs(t, err, daemon.ErrHashMismatch)
+			})
+
+			it("rejects transactions that can't be decoded", func() {
+				server.AddTx(daemontest.Tx{Hash: hash, Blob: "0102"})
+
+				_, err := client.GetTransactions(ctx, []string{hash})
+				assert.Error(t, err)
+			})
+		})
+
+		when("retrieving blocks", func() {
+			it("rejects blocks that can't be decoded", func() {
+				server.GenerateBlocks(1)
+
+				_, err := client.GetBlock(ctx, daemon.GetBlockRequestParameters{
+					Height: 1,
+				})
+				assert.Error(t, err)
+			})
+
+			it("verifies each block of a batch", func() {
+				good := blockResult(t, testBlock(2000000))
+				bad := blockResult(t, testBlock(2000001))
+				bad.BlockHeader.Hash = strings.Repeat("00", 32)
+
+				node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprintf(w, `[
+						{"id":"0", "jsonrpc":"2.0", "result": %s},
+						{"id":"1", "jsonrpc":"2.0", "result": %s}
+					]`, mustJSON(t, good), mustJSON(t, bad))
+				}))
+				defer node.Close()
+
+				c, err := rpc.NewClient(node.URL, rpc.WithHTTPClient(node.Client()))
+				require.NoError(t, err)
+
+				resps, err := daemon.NewClient(c, daemon.WithVerification()).BatchGetBlock(ctx,
+					[]daemon.GetBlockRequestParameters{{Height: 2000000}, {Height: 2000001}})
+				require.NoError(t, err)
+				require.Len(t, resps, 2)
+
+				require.NoError(t, resps[0].Error)
+				assert.Equal(t, good.BlockHeader.Hash, resps[0].Result.BlockHeader.Hash)
+
+				assert.ErrorIs(t, resps[1].Error, daemon.ErrHashMismatch)
+				assert.Nil(t, resps[1].Result)
+			})
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+// nolint:funlen
+func TestGetBlockResultVerify(t *testing.T) {
+	spec.Run(t, "GetBlockResult.Verify", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			block  monero.Block
+			result *daemon.GetBlockResult
+		)
+
+		it.Before(func() {
+			block = testBlock(2000000)
+			result = blockResult(t, block)
+		})
+
+		it("accepts a consistent block", func() {
+			assert.NoError(t, result.Verify())
+		})
+
+		it("rejects a block not matching its hash", func() {
+			result.BlockHeader.Hash = strings.Repeat("00", 32)
+
+			assert.ErrorIs(t, result.Verify(), daemon.ErrHashMismatch)
+		})
+
+		it("rejects a miner tx not matching its hash", func() {
+			result.MinerTxHash = strings.Repeat("00", 32)
+
+			assert.ErrorIs(t, result.Verify(), daemon.ErrHashMismatch)
+		})
+
+		it("rejects a json not matching the blob", func() {
+			block.Nonce++
+			result.JSON = mustJSON(t, block)
+
+			assert.ErrorIs(t, result.Verify(), daemon.ErrHashMismatch)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
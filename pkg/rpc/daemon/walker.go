@@ -0,0 +1,634 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+const (
+	// DefaultWalkerBatchSize is the default number of blocks that a
+	// ChainWalker fetches at once.
+	//
+	DefaultWalkerBatchSize = 100
+
+	// DefaultWalkerConcurrency is the default number of requests that a
+	// ChainWalker has in flight at once.
+	//
+	DefaultWalkerConcurrency = 4
+
+	// DefaultTxChunkSize is the default number of transactions that a
+	// ChainWalker asks for in a single `/get_transactions` call (the
+	// most that a restricted node serves).
+	//
+	DefaultTxChunkSize = 100
+
+	// DefaultReorgDepth is the default number of blocks that a
+	// ChainWalker remembers for finding where a reorg forked off.
+	//
+	DefaultReorgDepth = 100
+
+	// DefaultPollInterval is the default interval at which a ChainWalker
+	// following the tip checks for new blocks.
+	//
+	DefaultPollInterval = 10 * time.Second
+)
+
+// BlockID identifies a block of the chain.
+//
+type BlockID struct {
+	Height uint64 `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// Checkpoint is the progress made by a ChainWalker: the last blocks it
+// delivered (oldest first), enough for resuming later on and still detecting
+// a reorg that happened in the meantime.
+//
+type Checkpoint struct {
+	Blocks []BlockID `json:"blocks"`
+}
+
+// Top gives the last block delivered, if any.
+//
+func (c Checkpoint) Top() (BlockID, bool) {
+	if len(c.Blocks) == 0 {
+		return BlockID{}, false
+	}
+
+	return c.Blocks[len(c.Blocks)-1], true
+}
+
+// WalkedBlock is a block delivered by a ChainWalker.
+//
+type WalkedBlock struct {
+	*GetBlockResult
+
+	// Transactions are the transactions of the block (the coinbase one
+	// excluded), in the order they appear in it.
+	//
+	Transactions []GetTransactionsResultTransaction
+}
+
+// ID gives the height and hash of the block.
+//
+func (b *WalkedBlock) ID() BlockID {
+	return BlockID{
+		Height: b.BlockHeader.Height,
+		Hash:   b.BlockHeader.Hash,
+	}
+}
+
+// Rollback tells that a block previously delivered is no longer part of the
+// chain.
+//
+type Rollback struct {
+	// Block is the block dropped from the chain.
+	//
+	Block BlockID
+
+	// ForkHeight is the height of the last block that both the old and
+	// the new chain have in common.
+	//
+	ForkHeight uint64
+}
+
+// WalkEvent is what a ChainWalker delivers: either a block that comes next
+// in the chain, or a block that must be rolled back due to a reorg.
+//
+type WalkEvent struct {
+	// Block is the next block of the chain (nil for rollbacks).
+	//
+	Block *WalkedBlock
+
+	// Rollback is the block being rolled back (nil for new blocks).
+	//
+	Rollback *Rollback
+}
+
+// WalkFunc is called by a ChainWalker for every event, one at a time, in
+// order. Returning an error stops the walk.
+//
+type WalkFunc func(ctx context.Context, event WalkEvent) error
+
+type walkerOptions struct {
+	start        uint64
+	end          *uint64
+	checkpoint   *Checkpoint
+	checkpointer func(Checkpoint) error
+	batchSize    uint64
+	concurrency  int
+	txChunkSize  int
+	skipTxs      bool
+	reorgDepth   int
+	pollInterval time.Duration
+}
+
+// WalkerOption defines a functional option for overriding optional chain
+// walker configuration parameters.
+//
+type WalkerOption func(o *walkerOptions)
+
+// WithStartHeight sets the height of the first block to walk (default: 0).
+//
+func WithStartHeight(v uint64) WalkerOption {
+	return func(o *walkerOptions) {
+		o.start = v
+	}
+}
+
+// WithEndHeight sets the height of the last block to walk, waiting for it to
+// be mined if needed. Without it, the walker keeps on following the tip of
+// the chain.
+//
+func WithEndHeight(v uint64) WalkerOption {
+	return func(o *walkerOptions) {
+		o.end = &v
+	}
+}
+
+// WithCheckpoint resumes the walk right after the last block of the
+// checkpoint supplied (taking precedence over `WithStartHeight`), rolling
+// back those no longer part of the chain.
+//
+func WithCheckpoint(v Checkpoint) WalkerOption {
+	return func(o *walkerOptions) {
+		o.checkpoint = &v
+	}
+}
+
+// WithCheckpointer registers a function to be called with the progress made
+// every time a batch of events has been handled, e.g., for persisting it.
+//
+func WithCheckpointer(fn func(Checkpoint) error) WalkerOption {
+	return func(o *walkerOptions) {
+		o.checkpointer = fn
+	}
+}
+
+// WithWalkerBatchSize sets how many blocks are fetched at once (default:
+// `DefaultWalkerBatchSize`).
+//
+func WithWalkerBatchSize(v uint64) WalkerOption {
+	return func(o *walkerOptions) {
+		o.batchSize = v
+	}
+}
+
+// WithWalkerConcurrency sets how many requests are in flight at once
+// (default: `DefaultWalkerConcurrency`).
+//
+func WithWalkerConcurrency(v int) WalkerOption {
+	return func(o *walkerOptions) {
+		o.concurrency = v
+	}
+}
+
+// WithTxChunkSize sets how many transactions are asked for in a single call
+// (default: `DefaultTxChunkSize`).
+//
+func WithTxChunkSize(v int) WalkerOption {
+	return func(o *walkerOptions) {
+		o.txChunkSize = v
+	}
+}
+
+// WithoutTransactions has blocks delivered without their transactions.
+//
+func WithoutTransactions() WalkerOption {
+	return func(o *walkerOptions) {
+		o.skipTxs = true
+	}
+}
+
+// WithReorgDepth sets how many of the last blocks delivered are remembered
+// for finding where a reorg forked off (default: `DefaultReorgDepth`).
+// Reorgs deeper than that fail the walk with `ErrReorgTooDeep`.
+//
+func WithReorgDepth(v int) WalkerOption {
+	return func(o *walkerOptions) {
+		o.reorgDepth = v
+	}
+}
+
+// WithPollInterval sets how often the walker checks for new blocks once it
+// caught up with the tip (default: `DefaultPollInterval`).
+//
+func WithPollInterval(v time.Duration) WalkerOption {
+	return func(o *walkerOptions) {
+		o.pollInterval = v
+	}
+}
+
+// ChainWalker walks the chain block by block, either over a range of heights
+// or following the tip, fetching blocks and their transactions concurrently
+// while delivering them in order.
+//
+// Reorgs are detected by verifying that every block builds on top of the
+// last one delivered, in which case rollback events are delivered for every
+// block back to the fork point before carrying on with the new chain.
+//
+type ChainWalker struct {
+	client *Client
+	opts   walkerOptions
+
+	mu     sync.Mutex
+	recent []BlockID
+}
+
+// NewChainWalker instantiates a new ChainWalker fetching data through the
+// client supplied.
+//
+func NewChainWalker(client *Client, opts ...WalkerOption) *ChainWalker {
+	options := walkerOptions{
+		batchSize:    DefaultWalkerBatchSize,
+		concurrency:  DefaultWalkerConcurrency,
+		txChunkSize:  DefaultTxChunkSize,
+		reorgDepth:   DefaultReorgDepth,
+		pollInterval: DefaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.batchSize < 1 {
+		options.batchSize = 1
+	}
+
+	if options.concurrency < 1 {
+		options.concurrency = 1
+	}
+
+	if options.txChunkSize < 1 {
+		options.txChunkSize = 1
+	}
+
+	if options.reorgDepth < 1 {
+		options.reorgDepth = 1
+	}
+
+	w := &ChainWalker{
+		client: client,
+		opts:   options,
+	}
+
+	if options.checkpoint != nil {
+		w.recent = append(w.recent, options.checkpoint.Blocks...)
+		w.trim()
+	}
+
+	return w
+}
+
+// Checkpoint gives the progress made so far.
+//
+func (w *ChainWalker) Checkpoint() Checkpoint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return Checkpoint{
+		Blocks: append([]BlockID{}, w.recent...),
+	}
+}
+
+// Walk walks the chain, calling `fn` for every event, until either the end
+// height is reached, `fn` fails, or the context is done.
+//
+func (w *ChainWalker) Walk(ctx context.Context, fn WalkFunc) error {
+	next := w.opts.start
+	if last, found := w.last(); found {
+		next = last.Height + 1
+	}
+
+	for {
+		if w.opts.end != nil && next > *w.opts.end {
+			return nil
+		}
+
+		top, err := w.client.GetLastBlockHeader(ctx)
+		if err != nil {
+			return fmt.Errorf("get last block header: %w", err)
+		}
+
+		tip := top.BlockHeader
+		if last, found := w.last(); found && (tip.Height < last.Height ||
+			(tip.Height == last.Height && tip.Hash != last.Hash)) {
+			next, err = w.rollback(ctx, fn)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if next > tip.Height {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.opts.pollInterval):
+			}
+
+			continue
+		}
+
+		end := next + w.opts.batchSize - 1
+		if end > tip.Height {
+			end = tip.Height
+		}
+
+		if w.opts.end != nil && end > *w.opts.end {
+			end = *w.opts.end
+		}
+
+		next, err = w.batch(ctx, fn, next, end)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// batch delivers the blocks from `start` to `end`, giving the height to carry
+// on from.
+//
+func (w *ChainWalker) batch(ctx context.Context, fn WalkFunc, start, end uint64) (uint64, error) {
+	resp, err := w.client.GetBlockHeadersRange(ctx, start, end)
+	if err != nil {
+		// the chain got shorter in between: start over.
+		//
+		if errors.Is(err, rpc.ErrTooBigHeight) {
+			return start, nil
+		}
+
+		return 0, fmt.Errorf("get block headers range: %w", err)
+	}
+
+	headers := resp.Headers
+	if len(headers) == 0 {
+		return start, nil
+	}
+
+	if last, found := w.last(); found && headers[0].PrevHash != last.Hash {
+		return w.rollback(ctx, fn)
+	}
+
+	for idx := 1; idx < len(headers); idx++ {
+		if headers[idx].PrevHash != headers[idx-1].Hash {
+			return start, nil
+		}
+	}
+
+	blocks, err := w.fetch(ctx, headers)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, block := range blocks {
+		if err := fn(ctx, WalkEvent{Block: block}); err != nil {
+			return 0, err
+		}
+
+		w.record(block.ID())
+	}
+
+	if err := w.checkpoint(); err != nil {
+		return 0, err
+	}
+
+	return end + 1, nil
+}
+
+// rollback walks back the blocks delivered until finding one that is still
+// part of the chain, delivering rollback events for those that are not, and
+// gives the height to carry on from.
+//
+func (w *ChainWalker) rollback(ctx context.Context, fn WalkFunc) (uint64, error) {
+	recent := w.Checkpoint().Blocks
+	dropped := []BlockID{}
+
+	for len(recent) > 0 {
+		last := recent[len(recent)-1]
+
+		resp, err := w.client.GetBlockHeaderByHeight(ctx, last.Height)
+		if err == nil && resp.BlockHeader.Hash == last.Hash {
+			break
+		}
+
+		if err != nil && !errors.Is(err, rpc.ErrTooBigHeight) {
+			return 0, fmt.Errorf("get block header by height: %w", err)
+		}
+
+		dropped = append(dropped, last)
+		recent = recent[:len(recent)-1]
+	}
+
+	if len(recent) == 0 {
+		return 0, fmt.Errorf("%w: no common block in the last %d",
+			ErrReorgTooDeep, len(dropped))
+	}
+
+	fork := recent[len(recent)-1].Height
+
+	for _, block := range dropped {
+		event := WalkEvent{
+			Rollback: &Rollback{Block: block, ForkHeight: fork},
+		}
+
+		if err := fn(ctx, event); err != nil {
+			return 0, err
+		}
+
+		w.mu.Lock()
+		w.recent = w.recent[:len(w.recent)-1]
+		w.mu.Unlock()
+	}
+
+	if err := w.checkpoint(); err != nil {
+		return 0, err
+	}
+
+	return fork + 1, nil
+}
+
+// fetch retrieves the blocks (and their transactions) that `headers` refer
+// to.
+//
+func (w *ChainWalker) fetch(ctx context.Context, headers []BlockHeader) ([]*WalkedBlock, error) {
+	blocks := make([]*WalkedBlock, len(headers))
+
+	err := w.parallel(ctx, len(headers), func(ctx context.Context, idx int) error {
+		resp, err := w.client.GetBlock(ctx, GetBlockRequestParameters{
+			Hash: headers[idx].Hash,
+		})
+		if err != nil {
+			return fmt.Errorf("get block '%s': %w", headers[idx].Hash, err)
+		}
+
+		blocks[idx] = &WalkedBlock{GetBlockResult: resp}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if w.opts.skipTxs {
+		return blocks, nil
+	}
+
+	hashes := []string{}
+	blockHashes := make([][]string, len(blocks))
+
+	for idx, block := range blocks {
+		inner, err := block.InnerJSON()
+		if err != nil {
+			return nil, fmt.Errorf("block '%s': %w", block.BlockHeader.Hash, err)
+		}
+
+		blockHashes[idx] = inner.TxHashes
+		hashes = append(hashes, inner.TxHashes...)
+	}
+
+	txs, err := w.transactions(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, block := range blocks {
+		block.Transactions = make([]GetTransactionsResultTransaction, 0, len(blockHashes[idx]))
+
+		for _, hash := range blockHashes[idx] {
+			tx, found := txs[hash]
+			if !found {
+				return nil, fmt.Errorf("tx '%s' of block '%s' not found",
+					hash, block.BlockHeader.Hash)
+			}
+
+			block.Transactions = append(block.Transactions, tx)
+		}
+	}
+
+	return blocks, nil
+}
+
+// transactions retrieves the transactions `hashes` refer to, in chunks.
+//
+func (w *ChainWalker) transactions(
+	ctx context.Context, hashes []string,
+) (map[string]GetTransactionsResultTransaction, error) {
+	var (
+		mu     sync.Mutex
+		txs    = make(map[string]GetTransactionsResultTransaction, len(hashes))
+		size   = w.opts.txChunkSize
+		chunks = (len(hashes) + size - 1) / size
+	)
+
+	err := w.parallel(ctx, chunks, func(ctx context.Context, idx int) error {
+		chunk := hashes[idx*size:]
+		if len(chunk) > size {
+			chunk = chunk[:size]
+		}
+
+		resp, err := w.client.GetTransactions(ctx, chunk)
+		if err != nil {
+			return fmt.Errorf("get transactions: %w", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, tx := range resp.Txs {
+			txs[tx.TxHash] = tx
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+// parallel calls `fn` for every index up to `n`, with at most as many calls
+// in flight as the concurrency configured, stopping at the first failure.
+//
+func (w *ChainWalker) parallel(
+	ctx context.Context, n int, fn func(ctx context.Context, idx int) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, w.opts.concurrency)
+	)
+
+	for idx := 0; idx < n && ctx.Err() == nil; idx++ {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(idx int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := fn(ctx, idx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(idx)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return ctx.Err()
+}
+
+func (w *ChainWalker) last() (BlockID, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return Checkpoint{Blocks: w.recent}.Top()
+}
+
+func (w *ChainWalker) record(id BlockID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.recent = append(w.recent, id)
+	w.trim()
+}
+
+// trim drops the blocks that are too old to be remembered. Callers must hold
+// the lock.
+//
+func (w *ChainWalker) trim() {
+	if excess := len(w.recent) - w.opts.reorgDepth; excess > 0 {
+		w.recent = append([]BlockID{}, w.recent[excess:]...)
+	}
+}
+
+func (w *ChainWalker) checkpoint() error {
+	if w.opts.checkpointer == nil {
+		return nil
+	}
+
+	if err := w.opts.checkpointer(w.Checkpoint()); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon"
+	"github.com/jjsteel/go-monero/pkg/rpc/daemon/daemontest"
+)
+
+// nolint:funlen
+func TestChainWalker(t *testing.T) {
+	spec.Run(t, "ChainWalker", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *daemontest.Server
+			client *daemon.Client
+			events []daemon.WalkEvent
+		)
+
+		collect := func(_ context.Context, event daemon.WalkEvent) error {
+			events = append(events, event)
+			return nil
+		}
+
+		heights := func() []uint64 {
+			res := []uint64{}
+			for _, event := range events {
+				if event.Block != nil {
+					res = append(res, event.Block.ID().Height)
+				}
+			}
+
+			return res
+		}
+
+		it.Before(func() {
+			var err error
+
+			events = nil
+			server = daemontest.NewServer(daemontest.WithBlocks(5))
+
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("delivers the blocks of a range in order, with their txs", func() {
+			server.AddTx(daemontest.Tx{Hash: "aa", Blob: "00"})
+			server.AddTx(daemontest.Tx{Hash: "bb", Blob: "01"})
+			server.GenerateBlocks(1)
+
+			walker := daemon.NewChainWalker(client,
+				daemon.WithStartHeight(1),
+				daemon.WithEndHeight(5),
+				daemon.WithWalkerBatchSize(2),
+				daemon.WithTxChunkSize(1),
+			)
+
+			require.NoError(t, walker.Walk(ctx, collect))
+			assert.Equal(t, []uint64{1, 2, 3, 4, 5}, heights())
+
+			for _, event := range events {
+				block, _ := server.Block(event.Block.ID().Height)
+				assert.Equal(t, block.Hash, event.Block.ID().Hash)
+			}
+
+			txs := events[4].Block.Transactions
+			require.Len(t, txs, 2)
+			assert.Equal(t, "aa", txs[0].TxHash)
+			assert.Equal(t, "bb", txs[1].TxHash)
+			assert.EqualValues(t, 2, server.Calls("/get_transactions"))
+		})
+
+		it("skips transactions if asked to", func() {
+			server.AddTx(daemontest.Tx{Hash: "aa", Blob: "00"})
+			server.GenerateBlocks(1)
+
+			walker := daemon.NewChainWalker(client,
+				daemon.WithStartHeight(5),
+				daemon.WithEndHeight(5),
+				daemon.WithoutTransactions(),
+			)
+
+			require.NoError(t, walker.Walk(ctx, collect))
+			require.Len(t, events, 1)
+			assert.Empty(t, events[0].Block.Transactions)
+			assert.EqualValues(t, 0, server.Calls("/get_transactions"))
+		})
+
+		it("follows the tip until the end height", func() {
+			walker := daemon.NewChainWalker(client,
+				daemon.WithStartHeight(3),
+				daemon.WithEndHeight(6),
+				daemon.WithPollInterval(time.Millisecond),
+			)
+
+			err := walker.Walk(ctx, func(ctx context.Context, event daemon.WalkEvent) error {
+				if event.Block.ID().Height == 4 {
+					server.GenerateBlocks(2)
+				}
+
+				return collect(ctx, event)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{3, 4, 5, 6}, heights())
+		})
+
+		it("stops when the context is done", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			walker := daemon.NewChainWalker(client,
+				daemon.WithPollInterval(time.Millisecond),
+			)
+
+			err := walker.Walk(ctx, func(ctx context.Context, event daemon.WalkEvent) error {
+				if event.Block.ID().Height == 4 {
+					cancel()
+				}
+
+				return collect(ctx, event)
+			})
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, []uint64{0, 1, 2, 3, 4}, heights())
+		})
+
+		it("stops when the callback fails", func() {
+			failure := errors.New("failure")
+
+			walker := daemon.NewChainWalker(client)
+
+			err := walker.Walk(ctx, func(ctx context.Context, event daemon.WalkEvent) error {
+				if event.Block.ID().Height == 2 {
+					return failure
+				}
+
+				return collect(ctx, event)
+			})
+			assert.ErrorIs(t, err, failure)
+			assert.Equal(t, []uint64{0, 1}, heights())
+
+			top, found := walker.Checkpoint().Top()
+			require.True(t, found)
+			assert.EqualValues(t, 1, top.Height)
+		})
+
+		it("rolls back to the fork point on reorgs", func() {
+			old, _ := server.Block(4)
+			reorged := false
+
+			walker := daemon.NewChainWalker(client,
+				daemon.WithEndHeight(5),
+				daemon.WithPollInterval(time.Millisecond),
+			)
+
+			err := walker.Walk(ctx, func(ctx context.Context, event daemon.WalkEvent) error {
+				if event.Block != nil && event.Block.ID().Height == 4 && !reorged {
+					reorged = true
+					server.Reorg(2, 3)
+				}
+
+				return collect(ctx, event)
+			})
+			require.NoError(t, err)
+			require.Len(t, events, 10)
+
+			rollbacks := events[5:7]
+			for idx, height := range []uint64{4, 3} {
+				require.NotNil(t, rollbacks[idx].Rollback)
+				assert.EqualValues(t, height, rollbacks[idx].Rollback.Block.Height)
+				assert.EqualValues(t, 2, rollbacks[idx].Rollback.ForkHeight)
+			}
+			assert.Equal(t, old.Hash, rollbacks[0].Rollback.Block.Hash)
+
+			for idx, event := range events[7:] {
+				require.NotNil(t, event.Block)
+
+				block, _ := server.Block(uint64(3 + idx))
+				assert.Equal(t, block.Hash, event.Block.ID().Hash)
+			}
+		})
+
+		when("resuming from a checkpoint", func() {
+			var checkpoint daemon.Checkpoint
+
+			it.Before(func() {
+				walker := daemon.NewChainWalker(client,
+					daemon.WithEndHeight(4),
+					daemon.WithWalkerBatchSize(2),
+					daemon.WithReorgDepth(2),
+					daemon.WithCheckpointer(func(c daemon.Checkpoint) error {
+						checkpoint = c
+						return nil
+					}),
+				)
+
+				require.NoError(t, walker.Walk(ctx, collect))
+				require.Len(t, checkpoint.Blocks, 2)
+				assert.EqualValues(t, 3, checkpoint.Blocks[0].Height)
+				assert.EqualValues(t, 4, checkpoint.Blocks[1].Height)
+
+				events = nil
+			})
+
+			it("carries on after the last block", func() {
+				server.GenerateBlocks(2)
+
+				walker := daemon.NewChainWalker(client,
+					daemon.WithCheckpoint(checkpoint),
+					daemon.WithEndHeight(6),
+				)
+
+				require.NoError(t, walker.Walk(ctx, collect))
+				assert.Equal(t, []uint64{5, 6}, heights())
+			})
+
+			it("rolls back blocks reorged in the meantime", func() {
+				server.Reorg(1, 2)
+
+				walker := daemon.NewChainWalker(client,
+					daemon.WithCheckpoint(checkpoint),
+					daemon.WithEndHeight(5),
+				)
+
+				require.NoError(t, walker.Walk(ctx, collect))
+				require.Len(t, events, 3)
+				require.NotNil(t, events[0].Rollback)
+				assert.Equal(t, checkpoint.Blocks[1], events[0].Rollback.Block)
+				assert.EqualValues(t, 3, events[0].Rollback.ForkHeight)
+				assert.Equal(t, []uint64{4, 5}, heights())
+			})
+
+			it("fails on reorgs deeper than the blocks remembered", func() {
+				server.Reorg(3, 4)
+
+				walker := daemon.NewChainWalker(client,
+					daemon.WithCheckpoint(checkpoint),
+					daemon.WithReorgDepth(2),
+					daemon.WithEndHeight(6),
+				)
+
+				err := walker.Walk(ctx, collect)
+				assert.ErrorIs(t, err, daemon.ErrReorgTooDeep)
+				assert.Empty(t, events)
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
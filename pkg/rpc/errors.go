@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Error codes that `monerod` might fill the `error.code` field of a JSONRPC
+// response with (see `core_rpc_server_error_codes.h` in monero's source
+// tree), as well as the generic ones defined by the JSONRPC 2.0
+// specification.
+//
+const (
+	CodeWrongParam           = -1
+	CodeTooBigHeight         = -2
+	CodeTooBigReserveSize    = -3
+	CodeWrongWalletAddress   = -4
+	CodeInternalError        = -5
+	CodeWrongBlockblob       = -6
+	CodeBlockNotAccepted     = -7
+	CodeCoreBusy             = -9
+	CodeWrongBlockblobSize   = -10
+	CodeUnsupportedRPC       = -11
+	CodeMiningToSubaddress   = -12
+	CodeRegtestRequired      = -13
+	CodePaymentRequired      = -14
+	CodeInvalidClient        = -15
+	CodePaymentTooLow        = -16
+	CodeDuplicatePayment     = -17
+	CodeStalePayment         = -18
+	CodeRestricted           = -19
+	CodeUnsupportedBootstrap = -20
+	CodePaymentNotRequired   = -21
+
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+)
+
+// Values that `monerod` might fill the `status` field of its responses with
+// (see `core_rpc_server_commands_defs.h`).
+//
+const (
+	StatusOK              = "OK"
+	StatusBusy            = "BUSY"
+	StatusNotMining       = "NOT MINING"
+	StatusPaymentRequired = "PAYMENT REQUIRED"
+	StatusFailed          = "Failed"
+)
+
+// Sentinel values for the most common failures reported by `monerod`. They
+// are meant to be used with `errors.Is`, which matches solely on the code
+// (for `*Error`), the HTTP status code (for `*HTTPError`), or the status
+// string (for `*StatusError`), for instance:
+//
+// 	if errors.Is(err, rpc.ErrCoreBusy) {
+// 		// try again later
+// 	}
+//
+var (
+	ErrWrongParam       = &Error{Code: CodeWrongParam}
+	ErrTooBigHeight     = &Error{Code: CodeTooBigHeight}
+	ErrInternalError    = &Error{Code: CodeInternalError}
+	ErrWrongBlockblob   = &Error{Code: CodeWrongBlockblob}
+	ErrBlockNotAccepted = &Error{Code: CodeBlockNotAccepted}
+	ErrCoreBusy         = &Error{Code: CodeCoreBusy}
+	ErrPaymentRequired  = &Error{Code: CodePaymentRequired}
+	ErrRestricted       = &Error{Code: CodeRestricted}
+	ErrMethodNotFound   = &Error{Code: CodeMethodNotFound}
+
+	ErrUnauthorized = &HTTPError{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &HTTPError{StatusCode: http.StatusForbidden}
+	ErrNotFound     = &HTTPError{StatusCode: http.StatusNotFound}
+
+	ErrStatusBusy            = &StatusError{Status: StatusBusy}
+	ErrStatusNotMining       = &StatusError{Status: StatusNotMining}
+	ErrStatusPaymentRequired = &StatusError{Status: StatusPaymentRequired}
+	ErrStatusFailed          = &StatusError{Status: StatusFailed}
+)
+
+// Error is the error that a JSONRPC server replies with in the `error` field
+// of the response envelope.
+//
+type Error struct {
+	// Code is the numeric code identifying the class of error (see the
+	// `Code*` constants).
+	//
+	Code int `json:"code"`
+
+	// Message is the human-readable description of the error as given by
+	// the server.
+	//
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error: code=%d message=%s", e.Code, e.Message)
+}
+
+// Is reports whether `target` is an `*Error` with the same code, allowing the
+// sentinel values (e.g., `ErrCoreBusy`) to be matched regardless of the
+// message.
+//
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return t.Code == e.Code
+}
+
+// HTTPError is the error returned when the server replies with a status code
+// outside of the 2xx range, e.g., 401 when digest authentication failed.
+//
+type HTTPError struct {
+	// StatusCode is the HTTP status code the server replied with.
+	//
+	StatusCode int
+
+	// Status is the HTTP status line (e.g., "401 Unauthorized").
+	//
+	Status string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("non-2xx status code: %d", e.StatusCode)
+}
+
+// Is reports whether `target` is an `*HTTPError` with the same status code.
+//
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	if !ok {
+		return false
+	}
+
+	return t.StatusCode == e.StatusCode
+}
+
+// StatusError is the error returned when a response was successfully decoded
+// but its `status` field indicates that the daemon did not fulfill the
+// request (i.e., it's neither empty nor "OK").
+//
+type StatusError struct {
+	// Status is the content of the `status` field of the response.
+	//
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("rpc status: %s", e.Status)
+}
+
+// Is reports whether `target` is a `*StatusError` with the same status.
+//
+func (e *StatusError) Is(target error) bool {
+	t, ok := target.(*StatusError)
+	if !ok {
+		return false
+	}
+
+	return t.Status == e.Status
+}
+
+// StatusReporter is implemented by responses that carry monerod's `status`
+// field (e.g., any `daemon` result embedding `daemon.RPCResultFooter`).
+//
+// Responses implementing it have their status verified by the client once
+// decoded so that non-OK statuses are surfaced as `*StatusError`s.
+//
+type StatusReporter interface {
+	RPCStatus() string
+}
+
+// checkStatus verifies whether the response `v`, if reporting a status, has
+// one that indicates success.
+//
+func checkStatus(v interface{}) error {
+	reporter, ok := v.(StatusReporter)
+	if !ok {
+		return nil
+	}
+
+	status := reporter.RPCStatus()
+	if status == "" || status == StatusOK {
+		return nil
+	}
+
+	return &StatusError{Status: status}
+}
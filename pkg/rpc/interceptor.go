@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+)
+
+// CallKind identifies the kind of request that a Call corresponds to.
+//
+type CallKind string
+
+const (
+	// CallKindJSONRPC is a call to a method under `/json_rpc`.
+	//
+	CallKindJSONRPC CallKind = "jsonrpc"
+
+	// CallKindRaw is a request to one of the "raw" (JSON) endpoints,
+	// like `/get_height`.
+	//
+	CallKindRaw CallKind = "raw"
+
+	// CallKindBinary is a request to one of the binary (`.bin`)
+	// endpoints.
+	//
+	CallKindBinary CallKind = "binary"
+
+	// CallKindBatch is a set of calls under `/json_rpc` submitted in a
+	// single request (see `JSONRPCBatch`).
+	//
+	CallKindBatch CallKind = "batch"
+)
+
+// methodBatch is the name that JSONRPC batches are reported under.
+//
+const methodBatch = "batch"
+
+// Call describes a single request made by the client, as seen by
+// interceptors.
+//
+type Call struct {
+	// Kind is the kind of request being made.
+	//
+	Kind CallKind
+
+	// Method is the name of the JSONRPC method (e.g., "get_info") or the
+	// endpoint (e.g., "/get_height") being reached out to. For batches,
+	// it's always "batch".
+	//
+	Method string
+
+	// Address is the address of the server the request is made to.
+	//
+	Address string
+
+	// Params are the parameters of the call: those to be marshaled to
+	// JSON for JSONRPC and raw requests, the body for binary ones, and the
+	// set of `*BatchCall` for batches.
+	//
+	Params interface{}
+
+	// Result is where the result of the call gets unmarshalled to (for
+	// binary requests, a `*[]byte` that receives the response body), only
+	// filled once the call has been invoked.
+	//
+	Result interface{}
+}
+
+// Invoker performs a call, either by actually submitting the request or by
+// passing it down to the next interceptor in the chain.
+//
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor is a middleware that sits in between the client and the server,
+// seeing every request made: it might act before and after invoking the call
+// through `invoke` (e.g., for logging, measuring or rate limiting), or even
+// not invoke it at all, failing it instead.
+//
+// Interceptors see each attempt of a call that's retried (see
+// `WithRetryPolicy`) as a separate call.
+//
+type Interceptor func(ctx context.Context, call *Call, invoke Invoker) error
+
+// WithInterceptors is a functional option for having every request made by the
+// client go through the interceptors supplied, in the order they've been
+// supplied (i.e., the first one is the outermost).
+//
+// Can be supplied multiple times, with the interceptors being appended.
+//
+func WithInterceptors(v ...Interceptor) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Interceptors = append(o.Interceptors, v...)
+	}
+}
+
+// ChainInterceptors combines a set of interceptors into a single one, with the
+// first being the outermost.
+//
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		return chain(interceptors, invoke)(ctx, call)
+	}
+}
+
+// chain wraps `invoke` with the interceptors so that the first one is the
+// first to see the call.
+//
+func chain(interceptors []Interceptor, invoke Invoker) Invoker {
+	for idx := len(interceptors) - 1; idx >= 0; idx-- {
+		interceptor, next := interceptors[idx], invoke
+
+		invoke = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return invoke
+}
+
+// intercept makes `call` go through the interceptors configured before being
+// performed by `invoke`.
+//
+func (c *Client) intercept(
+	ctx context.Context, call *Call, invoke func(ctx context.Context) error,
+) error {
+	if len(c.interceptors) == 0 {
+		return invoke(ctx)
+	}
+
+	call.Address = c.address.String()
+
+	return chain(c.interceptors, func(ctx context.Context, _ *Call) error {
+		return invoke(ctx)
+	})(ctx, call)
+}
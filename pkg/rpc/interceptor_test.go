@@ -0,0 +1,185 @@
+package rpc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// nolint:funlen
+func TestInterceptors(t *testing.T) {
+	spec.Run(t, "WithInterceptors", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			daemon *httptest.Server
+		)
+
+		it.Before(func() {
+			daemon = httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/fail" {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "result": {"status": "OK"}}`)
+				},
+			))
+		})
+
+		it.After(func() {
+			daemon.Close()
+		})
+
+		newClient := func(interceptors ...rpc.Interceptor) *rpc.Client {
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithInterceptors(interceptors...),
+			)
+			require.NoError(t, err)
+
+			return client
+		}
+
+		recorder := func(name string, seen *[]string) rpc.Interceptor {
+			return func(ctx context.Context, call *rpc.Call, invoke rpc.Invoker) error {
+				*seen = append(*seen, name+">"+call.Method)
+				err := invoke(ctx, call)
+				*seen = append(*seen, name+"<"+call.Method)
+
+				return err
+			}
+		}
+
+		it("goes through interceptors in order", func() {
+			var seen []string
+
+			client := newClient(recorder("a", &seen), recorder("b", &seen))
+
+			err := client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/get_height", nil, &statusResult{})
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{
+				"a>get_info", "b>get_info", "b<get_info", "a<get_info",
+				"a>/get_height", "b>/get_height", "b</get_height", "a</get_height",
+			}, seen)
+		})
+
+		it("lets interceptors see the call", func() {
+			var seen rpc.Call
+
+			client := newClient(func(ctx context.Context, call *rpc.Call, invoke rpc.Invoker) error {
+				err := invoke(ctx, call)
+				seen = *call
+
+				return err
+			})
+
+			result := &statusResult{}
+			err := client.JSONRPC(ctx, "get_block", map[string]int{"height": 1}, result)
+			require.NoError(t, err)
+
+			assert.Equal(t, rpc.CallKindJSONRPC, seen.Kind)
+			assert.Equal(t, daemon.URL, seen.Address)
+			assert.Equal(t, map[string]int{"height": 1}, seen.Params)
+			assert.Same(t, result, seen.Result)
+			assert.Equal(t, "OK", result.Status)
+		})
+
+		it("lets interceptors fail calls", func() {
+			sentinel := errors.New("nope")
+
+			client := newClient(func(ctx context.Context, call *rpc.Call, invoke rpc.Invoker) error {
+				return sentinel
+			})
+
+			err := client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+			assert.True(t, errors.Is(err, sentinel))
+		})
+
+		when("logging", func() {
+			it("logs calls in logfmt", func() {
+				buf := &bytes.Buffer{}
+				client := newClient(rpc.NewLoggingInterceptor(buf))
+
+				require.NoError(t, client.JSONRPC(ctx, "get_info", nil, &statusResult{}))
+				require.Error(t, client.RawRequest(ctx, "/fail", nil, &statusResult{}))
+
+				lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+				require.Len(t, lines, 2)
+
+				assert.Contains(t, string(lines[0]), "level=info kind=jsonrpc method=get_info")
+				assert.Contains(t, string(lines[1]), "level=error kind=raw method=/fail")
+				assert.Contains(t, string(lines[1]), `error="`)
+			})
+		})
+
+		when("metrics", func() {
+			it("counts calls and errors per method", func() {
+				metrics := rpc.NewMetrics(1, 10)
+				client := newClient(metrics.Interceptor())
+
+				require.NoError(t, client.JSONRPC(ctx, "get_info", nil, &statusResult{}))
+				require.NoError(t, client.JSONRPC(ctx, "get_info", nil, &statusResult{}))
+				require.Error(t, client.RawRequest(ctx, "/fail", nil, &statusResult{}))
+
+				snapshot := metrics.Snapshot()
+				assert.EqualValues(t, 2, snapshot["get_info"].Calls)
+				assert.EqualValues(t, 0, snapshot["get_info"].Errors)
+				assert.Equal(t, []uint64{2, 2}, snapshot["get_info"].Buckets)
+				assert.EqualValues(t, 1, snapshot["/fail"].Errors)
+
+				buf := &bytes.Buffer{}
+				_, err := metrics.WriteTo(buf)
+				require.NoError(t, err)
+
+				assert.Contains(t, buf.String(), `monero_rpc_calls_total{method="get_info"} 2`)
+				assert.Contains(t, buf.String(), `monero_rpc_errors_total{method="/fail"} 1`)
+				assert.Contains(t, buf.String(),
+					`monero_rpc_call_duration_seconds_bucket{method="get_info",le="+Inf"} 2`)
+			})
+		})
+
+		when("rate limiting", func() {
+			it("refills the bucket over time", func() {
+				now := time.Unix(0, 0)
+				bucket := rpc.NewTokenBucket(2, 2, func() time.Time { return now })
+
+				assert.Zero(t, rpc.Reserve(bucket))
+				assert.Zero(t, rpc.Reserve(bucket))
+				assert.Equal(t, 500*time.Millisecond, rpc.Reserve(bucket))
+
+				now = now.Add(time.Second)
+				assert.Zero(t, rpc.Reserve(bucket))
+				assert.Equal(t, 500*time.Millisecond, rpc.Reserve(bucket))
+			})
+
+			it("fails calls whose context is done while waiting", func() {
+				client := newClient(rpc.NewRateLimiter(1, 1))
+
+				require.NoError(t, client.JSONRPC(ctx, "get_info", nil, &statusResult{}))
+
+				ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+				defer cancel()
+
+				err := client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+				assert.True(t, errors.Is(err, context.DeadlineExceeded))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewLoggingInterceptor instantiates an interceptor that writes a structured
+// (logfmt) line to `w` for every call once it's done, e.g.:
+//
+//	time=2021-10-01T00:00:00Z level=info kind=jsonrpc method=get_info
+//	address=http://localhost:18081 duration=1.2ms
+//
+// with failed calls being logged with `level=error` and their error.
+//
+func NewLoggingInterceptor(w io.Writer) Interceptor {
+	var mu sync.Mutex
+
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		start := time.Now()
+		err := invoke(ctx, call)
+		duration := time.Since(start)
+
+		fields := [][2]string{
+			{"time", start.UTC().Format(time.RFC3339Nano)},
+			{"level", "info"},
+			{"kind", string(call.Kind)},
+			{"method", call.Method},
+			{"address", call.Address},
+			{"duration", duration.String()},
+		}
+
+		if err != nil {
+			fields[1][1] = "error"
+			fields = append(fields, [2]string{"error", err.Error()})
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		fmt.Fprintln(w, logfmt(fields))
+
+		return err
+	}
+}
+
+// logfmt encodes key-value pairs in the logfmt format, quoting values when
+// needed.
+//
+func logfmt(fields [][2]string) string {
+	pairs := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		value := field[1]
+		if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+			value = strconv.Quote(value)
+		}
+
+		pairs = append(pairs, field[0]+"="+value)
+	}
+
+	return strings.Join(pairs, " ")
+}
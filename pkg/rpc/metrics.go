@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds (in seconds) of the buckets that
+// call latencies are distributed across.
+//
+var DefaultLatencyBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// MethodMetrics are the metrics gathered for a single method or endpoint.
+//
+type MethodMetrics struct {
+	// Calls is the number of calls made.
+	//
+	Calls uint64
+
+	// Errors is the number of calls that failed.
+	//
+	Errors uint64
+
+	// Duration is the total time spent on calls.
+	//
+	Duration time.Duration
+
+	// Buckets holds, for each of the latency buckets, how many calls took
+	// at most the bucket's upper bound.
+	//
+	Buckets []uint64
+}
+
+// Metrics gathers per-method call, error, and latency metrics from the calls
+// it sees through its interceptor (see `Interceptor`), which can then be
+// exported in Prometheus' text exposition format.
+//
+type Metrics struct {
+	buckets []float64
+
+	mu      sync.Mutex
+	methods map[string]*MethodMetrics
+}
+
+// NewMetrics instantiates a new Metrics, distributing latencies across the
+// buckets supplied, or `DefaultLatencyBuckets` if none.
+//
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		buckets: sorted,
+		methods: map[string]*MethodMetrics{},
+	}
+}
+
+// Interceptor gives the interceptor that records the metrics of every call
+// that passes through it.
+//
+func (m *Metrics) Interceptor() Interceptor {
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		start := time.Now()
+		err := invoke(ctx, call)
+
+		m.observe(call.Method, time.Since(start), err)
+
+		return err
+	}
+}
+
+func (m *Metrics) observe(method string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics, found := m.methods[method]
+	if !found {
+		metrics = &MethodMetrics{
+			Buckets: make([]uint64, len(m.buckets)),
+		}
+		m.methods[method] = metrics
+	}
+
+	metrics.Calls++
+	metrics.Duration += duration
+	if err != nil {
+		metrics.Errors++
+	}
+
+	for idx, bound := range m.buckets {
+		if duration.Seconds() <= bound {
+			metrics.Buckets[idx]++
+		}
+	}
+}
+
+// Snapshot gives a copy of the metrics gathered so far, indexed by method.
+//
+func (m *Metrics) Snapshot() map[string]MethodMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make(map[string]MethodMetrics, len(m.methods))
+	for method, metrics := range m.methods {
+		copied := *metrics
+		copied.Buckets = append([]uint64{}, metrics.Buckets...)
+
+		res[method] = copied
+	}
+
+	return res
+}
+
+// WriteTo writes the metrics in Prometheus' text exposition format to `w`.
+//
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	snapshot := m.Snapshot()
+
+	methods := make([]string, 0, len(snapshot))
+	for method := range snapshot {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintln(buf, "# HELP monero_rpc_calls_total Number of RPC calls made.")
+	fmt.Fprintln(buf, "# TYPE monero_rpc_calls_total counter")
+	for _, method := range methods {
+		fmt.Fprintf(buf, "monero_rpc_calls_total{method=%q} %d\n",
+			method, snapshot[method].Calls)
+	}
+
+	fmt.Fprintln(buf, "# HELP monero_rpc_errors_total Number of RPC calls that failed.")
+	fmt.Fprintln(buf, "# TYPE monero_rpc_errors_total counter")
+	for _, method := range methods {
+		fmt.Fprintf(buf, "monero_rpc_errors_total{method=%q} %d\n",
+			method, snapshot[method].Errors)
+	}
+
+	fmt.Fprintln(buf, "# HELP monero_rpc_call_duration_seconds Latency of RPC calls.")
+	fmt.Fprintln(buf, "# TYPE monero_rpc_call_duration_seconds histogram")
+	for _, method := range methods {
+		metrics := snapshot[method]
+
+		for idx, bound := range m.buckets {
+			fmt.Fprintf(buf, "monero_rpc_call_duration_seconds_bucket{method=%q,le=%q} %d\n",
+				method, strconv.FormatFloat(bound, 'g', -1, 64), metrics.Buckets[idx])
+		}
+
+		fmt.Fprintf(buf, "monero_rpc_call_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n",
+			method, metrics.Calls)
+		fmt.Fprintf(buf, "monero_rpc_call_duration_seconds_sum{method=%q} %s\n",
+			method, strconv.FormatFloat(metrics.Duration.Seconds(), 'g', -1, 64))
+		fmt.Fprintf(buf, "monero_rpc_call_duration_seconds_count{method=%q} %d\n",
+			method, metrics.Calls)
+	}
+
+	n, err := w.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), fmt.Errorf("write: %w", err)
+	}
+
+	return int64(n), nil
+}
+
+// ServeHTTP serves the metrics in Prometheus' text exposition format, so that
+// Metrics can be registered as the handler of a `/metrics` endpoint.
+//
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	_, _ = m.WriteTo(w)
+}
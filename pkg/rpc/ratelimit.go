@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NewRateLimiter instantiates an interceptor that limits the rate at which
+// calls are made to `rate` calls per second, allowing bursts of up to `burst`
+// calls, by means of a token bucket.
+//
+// Calls that would go over the limit wait for their turn, unless the context
+// is done first. A non-positive `rate` means no limit at all.
+//
+func NewRateLimiter(rate float64, burst int) Interceptor {
+	if rate <= 0 {
+		return func(ctx context.Context, call *Call, invoke Invoker) error {
+			return invoke(ctx, call)
+		}
+	}
+
+	bucket := newTokenBucket(rate, burst, time.Now)
+
+	return func(ctx context.Context, call *Call, invoke Invoker) error {
+		if err := bucket.wait(ctx); err != nil {
+			return fmt.Errorf("rate limit: %w", err)
+		}
+
+		return invoke(ctx, call)
+	}
+}
+
+// tokenBucket is a token bucket that's refilled at `rate` tokens per second,
+// holding at most `burst` tokens.
+//
+type tokenBucket struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		now:    now,
+		tokens: float64(burst),
+		last:   now(),
+	}
+}
+
+// reserve takes a token from the bucket, giving how long to wait before it's
+// actually available.
+//
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back a token previously reserved.
+//
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+}
+
+// wait blocks until a token is available or the context is done.
+//
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,310 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// safeMethods is the set of JSONRPC methods and raw endpoints (from both
+// `monerod` and `monero-wallet-rpc`) that only read state, and thus can be
+// retried without the risk of performing an action twice.
+//
+var safeMethods = map[string]bool{
+	// daemon - jsonrpc
+	//
+	"add_aux_pow":                true,
+	"banned":                     true,
+	"calc_pow":                   true,
+	"get_alternate_chains":       true,
+	"get_bans":                   true,
+	"get_block":                  true,
+	"get_block_count":            true,
+	"get_block_header_by_hash":   true,
+	"get_block_header_by_height": true,
+	"get_block_headers_range":    true,
+	"get_block_template":         true,
+	"get_coinbase_tx_sum":        true,
+	"get_connections":            true,
+	"get_fee_estimate":           true,
+	"get_info":                   true,
+	"get_last_block_header":      true,
+	"get_miner_data":             true,
+	"get_output_distribution":    true,
+	"get_output_histogram":       true,
+	"get_txpool_backlog":         true,
+	"get_version":                true,
+	"hard_fork_info":             true,
+	"on_get_block_hash":          true,
+	"sync_info":                  true,
+
+	// daemon - raw endpoints
+	//
+	"/get_alt_blocks_hashes":       true,
+	"/get_height":                  true,
+	"/get_limit":                   true,
+	"/get_net_stats":               true,
+	"/get_outs":                    true,
+	"/get_peer_list":               true,
+	"/get_public_nodes":            true,
+	"/get_transaction_pool":        true,
+	"/get_transaction_pool_hashes": true,
+	"/get_transaction_pool_stats":  true,
+	"/get_transactions":            true,
+	"/is_key_image_spent":          true,
+	"/mining_status":               true,
+
+	// daemon - binary endpoints
+	//
+	"/get_blocks.bin":              true,
+	"/get_blocks_by_height.bin":    true,
+	"/get_hashes.bin":              true,
+	"/get_o_indexes.bin":           true,
+	"/get_output_distribution.bin": true,
+	"/get_outs.bin":                true,
+
+	// wallet
+	//
+	"get_accounts": true,
+	"get_address":  true,
+	"get_balance":  true,
+	"get_height":   true,
+}
+
+// IsSafeMethod tells whether the JSONRPC method or raw endpoint `method` only
+// reads state, and thus can be issued more than once (be it by retrying it or
+// by sending it to another node) without the risk of performing an action
+// twice.
+//
+func IsSafeMethod(method string) bool {
+	return safeMethods[method]
+}
+
+// RetryPolicy configures how calls that failed due to transient errors (see
+// `IsTransient`) should be retried.
+//
+// Only calls that are safe to be performed more than once (reads like
+// `get_info`, `get_block`, `/get_transactions`, etc) are retried - those that
+// change state (`relay_tx`, `set_bans`, `/start_mining`, `generateblocks`,
+// ...) are never retried unless explicitly listed in `UnsafeMethods`.
+//
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a call is attempted
+	// (including the first one).
+	//
+	MaxAttempts int
+
+	// InitialBackoff is how long to wait before the first retry.
+	//
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps how long to wait between two attempts.
+	//
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor by which the backoff grows after each
+	// attempt.
+	//
+	Multiplier float64
+
+	// Jitter is the fraction (from 0 to 1) of the backoff by which it
+	// should be randomly varied so that clients don't retry in lockstep.
+	//
+	Jitter float64
+
+	// Retryable classifies errors between those worth retrying or not.
+	// Defaults to `IsTransient`.
+	//
+	Retryable func(err error) bool
+
+	// UnsafeMethods lists JSONRPC methods or raw endpoints (e.g.,
+	// "relay_tx" or "/start_mining") that should be retried despite
+	// not being safe to.
+	//
+	UnsafeMethods []string
+}
+
+// DefaultRetryPolicy is a RetryPolicy with sensible defaults: up to 4
+// attempts, with backoffs starting at 250ms and growing twice as long each
+// time, up to 5s, with 20% of jitter.
+//
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// WithRetryPolicy is a functional option for having calls that failed due to
+// transient errors retried according to the policy supplied.
+//
+func WithRetryPolicy(v RetryPolicy) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.RetryPolicy = &v
+	}
+}
+
+// IsTransient classifies an error as transient, i.e., one that might not
+// happen again if the same call is retried: network failures (timeouts,
+// refused or reset connections, replies cut short), 5xx and 429 HTTP
+// statuses, and the daemon being busy.
+//
+// Anything else (bad URLs, TLS certificate failures, malformed replies, ...)
+// is taken as permanent, as retrying it would only fail the same way again.
+//
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return errors.Is(err, ErrCoreBusy)
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return errors.Is(err, ErrStatusBusy)
+	}
+
+	var decodeErr *decodeError
+	if errors.As(err, &decodeErr) {
+		return false
+	}
+
+	return isNetworkFailure(err)
+}
+
+// isNetworkFailure tells whether `err` comes from the connection to the
+// server failing rather than from the request itself being unacceptable.
+//
+func isNetworkFailure(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+
+	// `*url.Error` implements `net.Error` for whatever it wraps, so only
+	// timeouts can be trusted from the interface alone.
+	//
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// shouldRetry determines whether a call to `method` can be retried at all.
+//
+func (p *RetryPolicy) shouldRetry(method string) bool {
+	if IsSafeMethod(method) {
+		return true
+	}
+
+	for _, m := range p.UnsafeMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff computes how long to wait before the attempt number `attempt`
+// (starting from 1 for the first retry).
+//
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		// nolint:gosec
+		d += d * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(d)
+}
+
+// retry calls `fn` (performing calls to `methods`) until it either succeeds,
+// fails with an error that's not worth retrying, or the attempts are
+// exhausted.
+//
+// `fn` is retried only if all of the methods are safe to be retried.
+//
+func (c *Client) retry(ctx context.Context, methods []string, fn func() error) error {
+	policy := c.retryPolicy
+	if policy == nil {
+		return fn()
+	}
+
+	for _, method := range methods {
+		if !policy.shouldRetry(method) {
+			return fn()
+		}
+	}
+
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// decodeError indicates that the response couldn't be decoded, which
+// retrying wouldn't help with.
+//
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string {
+	return "decode: " + e.err.Error()
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
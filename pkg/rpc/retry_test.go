@@ -0,0 +1,271 @@
+package rpc_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+)
+
+// nolint:funlen
+func TestRetry(t *testing.T) {
+	spec.Run(t, "WithRetryPolicy", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			policy = rpc.RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Millisecond,
+				Multiplier:     2,
+			}
+		)
+
+		// failingRawServer replies with `failure` for the first
+		// `failures` requests, and with `success` after that.
+		//
+		failingRawServer := func(
+			failures int32, failure func(w http.ResponseWriter), success string,
+		) (*httptest.Server, *int32) {
+			var count int32
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&count, 1) <= failures {
+					failure(w)
+					return
+				}
+
+				fmt.Fprintln(w, success)
+			}
+
+			return httptest.NewServer(http.HandlerFunc(handler)), &count
+		}
+
+		failingServer := func(
+			failures int32, failure func(w http.ResponseWriter),
+		) (*httptest.Server, *int32) {
+			return failingRawServer(failures, failure,
+				`{"id":"0", "jsonrpc":"2.0", "result": {"status": "OK"}}`)
+		}
+
+		unavailable := func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		it("retries safe methods on transient errors", func() {
+			daemon, count := failingServer(2, unavailable)
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+			assert.NoError(t, err)
+			assert.EqualValues(t, 3, atomic.LoadInt32(count))
+		})
+
+		it("retries when the daemon reports being busy", func() {
+			daemon, count := failingRawServer(1, func(w http.ResponseWriter) {
+				fmt.Fprintln(w, `{"status": "BUSY"}`)
+			}, `{"status": "OK"}`)
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/get_transactions", nil, &statusResult{})
+			assert.NoError(t, err)
+			assert.EqualValues(t, 2, atomic.LoadInt32(count))
+		})
+
+		it("gives up after max attempts", func() {
+			daemon, count := failingServer(10, unavailable)
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+			assert.Error(t, err)
+			assert.EqualValues(t, 3, atomic.LoadInt32(count))
+		})
+
+		it("doesn't retry non-transient errors", func() {
+			daemon, count := failingServer(10, func(w http.ResponseWriter) {
+				fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "error": {"code": -2, "message": "too big"}}`)
+			})
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "get_block", nil, &statusResult{})
+			assert.Error(t, err)
+			assert.EqualValues(t, 1, atomic.LoadInt32(count))
+		})
+
+		it("doesn't retry state-changing methods", func() {
+			daemon, count := failingServer(2, unavailable)
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "relay_tx", nil, &statusResult{})
+			assert.Error(t, err)
+			assert.EqualValues(t, 1, atomic.LoadInt32(count))
+		})
+
+		it("retries state-changing methods when opted in", func() {
+			daemon, count := failingServer(2, unavailable)
+			defer daemon.Close()
+
+			optedIn := policy
+			optedIn.UnsafeMethods = []string{"relay_tx"}
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(optedIn),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "relay_tx", nil, &statusResult{})
+			assert.NoError(t, err)
+			assert.EqualValues(t, 3, atomic.LoadInt32(count))
+		})
+
+		it("doesn't retry calls that clear the daemon's tracking data", func() {
+			daemon, count := failingServer(2, unavailable)
+			defer daemon.Close()
+
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(daemon.Client()),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "rpc_access_tracking", map[string]bool{"clear": true}, &statusResult{})
+			assert.Error(t, err)
+			assert.EqualValues(t, 1, atomic.LoadInt32(count))
+		})
+
+		it("doesn't retry certificate failures", func() {
+			var connections int32
+
+			daemon := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "result": {"status": "OK"}}`)
+			}))
+			daemon.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&connections, 1)
+				}
+			}
+			daemon.StartTLS()
+			defer daemon.Close()
+
+			// the default client doesn't trust the test server's
+			// certificate.
+			//
+			client, err := rpc.NewClient(daemon.URL,
+				rpc.WithHTTPClient(&http.Client{}),
+				rpc.WithRetryPolicy(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "get_info", nil, &statusResult{})
+			assert.Error(t, err)
+			assert.EqualValues(t, 1, atomic.LoadInt32(&connections))
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel())
+}
+
+func TestIsTransient(t *testing.T) {
+	spec.Run(t, "IsTransient", func(t *testing.T, when spec.G, it spec.S) {
+		urlErr := func(err error) error {
+			return &url.Error{Op: "Post", URL: "http://localhost:18081/json_rpc", Err: err}
+		}
+
+		refused := &net.OpError{
+			Op:  "dial",
+			Net: "tcp",
+			Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED},
+		}
+
+		badHost := &url.Error{Op: "parse", URL: "http://[::1", Err: errors.New("missing ']' in host")}
+
+		when("the error comes from the network", func() {
+			for name, err := range map[string]error{
+				"connection refused":  urlErr(refused),
+				"connection reset":    urlErr(fmt.Errorf("read: %w", syscall.ECONNRESET)),
+				"reply cut short":     urlErr(io.ErrUnexpectedEOF),
+				"connection closed":   urlErr(io.EOF),
+				"timeout":             urlErr(timeoutError{}),
+				"503 service unavail": &rpc.HTTPError{StatusCode: http.StatusServiceUnavailable},
+				"429 too many":        &rpc.HTTPError{StatusCode: http.StatusTooManyRequests},
+			} {
+				name, err := name, err
+
+				it("retries "+name, func() {
+					assert.True(t, rpc.IsTransient(err))
+				})
+			}
+		})
+
+		when("the error is permanent", func() {
+			for name, err := range map[string]error{
+				"unknown certificate authority": urlErr(x509.UnknownAuthorityError{}),
+				"invalid certificate":           urlErr(x509.CertificateInvalidError{Reason: x509.Expired}),
+				"bad url":                       urlErr(errors.New("unsupported protocol scheme \"\"")),
+				"bad host":                      badHost,
+				"400 bad request":               &rpc.HTTPError{StatusCode: http.StatusBadRequest},
+				"401 unauthorized":              &rpc.HTTPError{StatusCode: http.StatusUnauthorized},
+				"context canceled":              urlErr(context.Canceled),
+				"anything else":                 errors.New("boom"),
+			} {
+				name, err := name, err
+
+				it("doesn't retry "+name, func() {
+					assert.False(t, rpc.IsTransient(err))
+				})
+			}
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel())
+}
+
+// timeoutError is a net.Error that timed out.
+//
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,4 @@
+// Package wallettest provides an in-memory fake of monero-wallet-rpc (see
+// `Server`) for testing code that makes use of `wallet.Client`.
+//
+package wallettest
@@ -0,0 +1,332 @@
+package wallettest
+
+import (
+	"encoding/json"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/wallet"
+)
+
+// error codes as defined in monero-wallet-rpc's
+// `wallet_rpc_server_error_codes.h`.
+//
+const (
+	codeUnknownError            = -1
+	codeAccountIndexOutOfBounds = -14
+	codeAddressIndexOutOfBounds = -15
+)
+
+// maxCreateAddressCount is the maximum number of addresses that can be
+// created at once.
+//
+const maxCreateAddressCount = 64
+
+var (
+	errAccountIndexOutOfBounds = &rpc.Error{
+		Code:    codeAccountIndexOutOfBounds,
+		Message: "account index is out of bound",
+	}
+
+	errAddressIndexOutOfBounds = &rpc.Error{
+		Code:    codeAddressIndexOutOfBounds,
+		Message: "address index is out of bound",
+	}
+)
+
+type handler func(w *walletState, params []byte) (interface{}, error)
+
+var handlers = map[string]handler{
+	"auto_refresh":   autoRefresh,
+	"create_address": createAddress,
+	"get_accounts":   getAccounts,
+	"get_address":    getAddress,
+	"get_balance":    getBalance,
+	"get_height":     getHeight,
+	"refresh":        refresh,
+}
+
+func decode(b []byte, v interface{}) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return &rpc.Error{Code: rpc.CodeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func autoRefresh(w *walletState, params []byte) (interface{}, error) {
+	req := &struct {
+		Enable bool  `json:"enable"`
+		Period int64 `json:"period"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	w.autoRefresh = req.Enable
+	if req.Period > 0 {
+		w.autoRefreshPeriod = req.Period
+	}
+
+	if w.autoRefresh {
+		w.refresh()
+	}
+
+	return &wallet.AutoRefreshResult{}, nil
+}
+
+func createAddress(w *walletState, params []byte) (interface{}, error) {
+	req := &struct {
+		AccountIndex uint   `json:"account_index"`
+		Count        uint   `json:"count"`
+		Label        string `json:"label"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.account(req.AccountIndex); err != nil {
+		return nil, err
+	}
+
+	if req.Count == 0 {
+		req.Count = 1
+	}
+
+	if req.Count > maxCreateAddressCount {
+		return nil, &rpc.Error{
+			Code:    codeUnknownError,
+			Message: "Count must be less than 65",
+		}
+	}
+
+	resp := &wallet.CreateAddressResult{}
+	for i := uint(0); i < req.Count; i++ {
+		idx := w.createAddress(req.AccountIndex, req.Label)
+		address := w.accounts[req.AccountIndex].subaddresses[idx].address
+
+		resp.AddressIndices = append(resp.AddressIndices, idx)
+		resp.Addresses = append(resp.Addresses, address)
+	}
+
+	resp.Address = resp.Addresses[0]
+	resp.AddressIndex = resp.AddressIndices[0]
+
+	return resp, nil
+}
+
+func getAccounts(w *walletState, params []byte) (interface{}, error) {
+	req := &wallet.GetAccountsRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	type subaddressAccount struct {
+		AccountIndex    uint   `json:"account_index"`
+		Balance         uint64 `json:"balance"`
+		BaseAddress     string `json:"base_address"`
+		Label           string `json:"label"`
+		Tag             string `json:"tag"`
+		UnlockedBalance uint64 `json:"unlocked_balance"`
+	}
+
+	var (
+		accounts             = []subaddressAccount{}
+		total, totalUnlocked uint64
+	)
+
+	for idx, acc := range w.accounts {
+		if req.Tag != "" && acc.tag != req.Tag {
+			continue
+		}
+
+		accountIndex := uint(idx)
+		balance, unlocked, _, _ := w.balance(func(t *Transfer) bool {
+			return t.AccountIndex == accountIndex
+		})
+
+		accounts = append(accounts, subaddressAccount{
+			AccountIndex:    accountIndex,
+			Balance:         balance,
+			BaseAddress:     acc.subaddresses[0].address,
+			Label:           acc.label,
+			Tag:             acc.tag,
+			UnlockedBalance: unlocked,
+		})
+
+		total += balance
+		totalUnlocked += unlocked
+	}
+
+	return map[string]interface{}{
+		"subaddress_accounts":    accounts,
+		"total_balance":          total,
+		"total_unlocked_balance": totalUnlocked,
+	}, nil
+}
+
+func getAddress(w *walletState, params []byte) (interface{}, error) {
+	req := &wallet.GetAddressRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	acc, err := w.account(req.AccountIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := req.AddressIndices
+	if len(indices) == 0 {
+		for idx := range acc.subaddresses {
+			indices = append(indices, uint(idx))
+		}
+	}
+
+	type address struct {
+		Address      string `json:"address"`
+		AddressIndex uint   `json:"address_index"`
+		Label        string `json:"label"`
+		Used         bool   `json:"used"`
+	}
+
+	addresses := []address{}
+	for _, idx := range indices {
+		if idx >= uint(len(acc.subaddresses)) {
+			return nil, errAddressIndexOutOfBounds
+		}
+
+		addresses = append(addresses, address{
+			Address:      acc.subaddresses[idx].address,
+			AddressIndex: idx,
+			Label:        acc.subaddresses[idx].label,
+			Used:         w.used(req.AccountIndex, idx),
+		})
+	}
+
+	return map[string]interface{}{
+		"address":   acc.subaddresses[0].address,
+		"addresses": addresses,
+	}, nil
+}
+
+func getBalance(w *walletState, params []byte) (interface{}, error) {
+	req := &wallet.GetBalanceRequestParameters{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	accounts := []uint{req.AccountIndex}
+	if req.AllAccounts {
+		accounts = make([]uint, len(w.accounts))
+		for idx := range accounts {
+			accounts[idx] = uint(idx)
+		}
+	}
+
+	resp := &wallet.GetBalanceResult{
+		PerSubaddress: []wallet.SubAddress{},
+	}
+
+	for _, accountIndex := range accounts {
+		acc, err := w.account(accountIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		balance, unlocked, blocksToUnlock, _ := w.balance(func(t *Transfer) bool {
+			return t.AccountIndex == accountIndex
+		})
+
+		resp.Balance += balance
+		resp.UnlockedBalance += int64(unlocked)
+		if blocksToUnlock > resp.BlocksToUnlock {
+			resp.BlocksToUnlock = blocksToUnlock
+		}
+
+		for _, idx := range req.AddressIndices {
+			if idx >= uint(len(acc.subaddresses)) {
+				return nil, errAddressIndexOutOfBounds
+			}
+		}
+
+		for idx, sub := range acc.subaddresses {
+			addressIndex := uint(idx)
+			if !wanted(req.AddressIndices, addressIndex) {
+				continue
+			}
+
+			balance, unlocked, blocksToUnlock, outputs := w.balance(func(t *Transfer) bool {
+				return t.AccountIndex == accountIndex && t.AddressIndex == addressIndex
+			})
+
+			// just like monero-wallet-rpc, only subaddresses that
+			// received funds are listed (unless explicitly asked for).
+			//
+			if outputs == 0 && len(req.AddressIndices) == 0 {
+				continue
+			}
+
+			resp.PerSubaddress = append(resp.PerSubaddress, wallet.SubAddress{
+				AccountIndex:      accountIndex,
+				Address:           sub.address,
+				AddressIndex:      addressIndex,
+				Balance:           balance,
+				BlocksToUnlock:    blocksToUnlock,
+				Label:             sub.label,
+				NumUnspentOutputs: outputs,
+				TimeToUnlock:      blocksToUnlock * blockTime,
+				UnlockedBalance:   int64(unlocked),
+			})
+		}
+	}
+
+	resp.TimeToUnlock = int(resp.BlocksToUnlock) * blockTime
+
+	return resp, nil
+}
+
+func wanted(indices []uint, idx uint) bool {
+	if len(indices) == 0 {
+		return true
+	}
+
+	for _, i := range indices {
+		if i == idx {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getHeight(w *walletState, params []byte) (interface{}, error) {
+	return &wallet.GetHeightResult{
+		Height: w.walletHeight,
+	}, nil
+}
+
+func refresh(w *walletState, params []byte) (interface{}, error) {
+	req := &struct {
+		StartHeight uint64 `json:"start_height"`
+	}{}
+	if err := decode(params, req); err != nil {
+		return nil, err
+	}
+
+	// blocks below `start_height` are skipped if not scanned yet.
+	//
+	if req.StartHeight > w.walletHeight {
+		w.skip(req.StartHeight)
+	}
+
+	fetched, received := w.refresh()
+
+	return &wallet.RefreshResult{
+		BlocksFetched: fetched,
+		ReceivedMoney: received,
+	}, nil
+}
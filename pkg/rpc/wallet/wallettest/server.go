@@ -0,0 +1,203 @@
+package wallettest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/wallet"
+)
+
+const endpointJSONRPC = "/json_rpc"
+
+type serverOptions struct {
+	network       monero.Network
+	privateKey    []byte
+	chainHeight   uint64
+	restoreHeight uint64
+}
+
+// Option is a functional option for configuring the server.
+//
+type Option func(o *serverOptions)
+
+// WithNetwork sets the network that the wallet's addresses belong to.
+//
+func WithNetwork(v monero.Network) Option {
+	return func(o *serverOptions) {
+		o.network = v
+	}
+}
+
+// WithPrivateSpendKey sets the private spend key from which the wallet's
+// primary address is derived.
+//
+func WithPrivateSpendKey(v []byte) Option {
+	return func(o *serverOptions) {
+		o.privateKey = v
+	}
+}
+
+// WithChainHeight sets the initial height of the chain, to which the wallet
+// is already synced.
+//
+func WithChainHeight(v uint64) Option {
+	return func(o *serverOptions) {
+		o.chainHeight = v
+	}
+}
+
+// WithRestoreHeight sets the height from which the wallet scans the chain:
+// transfers mined in blocks below it are never seen.
+//
+func WithRestoreHeight(v uint64) Option {
+	return func(o *serverOptions) {
+		o.restoreHeight = v
+	}
+}
+
+// Server is an in-memory fake of monero-wallet-rpc serving all of the methods
+// that `wallet.Client` exposes, backed by a scriptable wallet.
+//
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	wallet *walletState
+	calls  map[string]uint64
+}
+
+// NewServer instantiates and starts a new fake monero-wallet-rpc.
+//
+func NewServer(opts ...Option) *Server {
+	options := serverOptions{
+		network:     monero.NetworkMainnet,
+		privateKey:  make([]byte, monero.KeySize),
+		chainHeight: 1,
+	}
+
+	options.privateKey[0] = 1
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	s := &Server{
+		wallet: newWalletState(options),
+		calls:  map[string]uint64{},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// NewClient instantiates a wallet client targeting this server.
+//
+func (s *Server) NewClient(opts ...rpc.ClientOption) (*wallet.Client, error) {
+	client, err := rpc.NewClient(s.URL,
+		append([]rpc.ClientOption{rpc.WithHTTPClient(s.Server.Client())}, opts...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	return wallet.NewClient(client), nil
+}
+
+// Calls gives how many times the JSONRPC method `method` has been served.
+//
+func (s *Server) Calls(method string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != endpointJSONRPC {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	req := &rpc.RequestEnvelope{}
+	if err := json.Unmarshal(body, req); err != nil {
+		writeJSON(w, &rpc.ResponseEnvelope{
+			ID:      "0",
+			JSONRPC: "2.0",
+			Error: &rpc.Error{
+				Code:    rpc.CodeParseError,
+				Message: "Parse error",
+			},
+		})
+
+		return
+	}
+
+	resp := &rpc.ResponseEnvelope{
+		ID:      req.ID,
+		JSONRPC: "2.0",
+	}
+
+	handler, found := handlers[req.Method]
+	if !found {
+		resp.Error = &rpc.Error{
+			Code:    rpc.CodeMethodNotFound,
+			Message: "Method not found",
+		}
+
+		writeJSON(w, resp)
+		return
+	}
+
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[req.Method]++
+	result, err := handler(s.wallet, params)
+	s.mu.Unlock()
+
+	if err != nil {
+		resp.Error = toRPCError(err)
+	} else {
+		resp.Result = result
+	}
+
+	writeJSON(w, resp)
+}
+
+func toRPCError(err error) *rpc.Error {
+	var rpcErr *rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	return &rpc.Error{
+		Code:    codeUnknownError,
+		Message: err.Error(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
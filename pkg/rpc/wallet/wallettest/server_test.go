@@ -0,0 +1,211 @@
+package wallettest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jjsteel/go-monero/pkg/rpc"
+	"github.com/jjsteel/go-monero/pkg/rpc/wallet"
+	"github.com/jjsteel/go-monero/pkg/rpc/wallet/wallettest"
+)
+
+// nolint:funlen
+func TestServer(t *testing.T) {
+	spec.Run(t, "Server", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			server *wallettest.Server
+			client *wallet.Client
+		)
+
+		it.Before(func() {
+			var err error
+
+			server = wallettest.NewServer(wallettest.WithChainHeight(100))
+			client, err = server.NewClient()
+			require.NoError(t, err)
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		when("receiving funds", func() {
+			it("ignores deposits still in the pool", func() {
+				_, err := server.Deposit(0, 0, 1000)
+				require.NoError(t, err)
+
+				resp, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 0, resp.Balance)
+				assert.Empty(t, resp.PerSubaddress)
+			})
+
+			it("locks funds until they're old enough", func() {
+				_, err := server.Deposit(0, 0, 1000)
+				require.NoError(t, err)
+
+				server.MineBlocks(1)
+
+				resp, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 1000, resp.Balance)
+				assert.EqualValues(t, 0, resp.UnlockedBalance)
+				assert.EqualValues(t, wallettest.SpendableAge-1, resp.BlocksToUnlock)
+				require.Len(t, resp.PerSubaddress, 1)
+				assert.EqualValues(t, 1, resp.PerSubaddress[0].NumUnspentOutputs)
+
+				server.MineBlocks(wallettest.SpendableAge - 1)
+
+				resp, err = client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 1000, resp.Balance)
+				assert.EqualValues(t, 1000, resp.UnlockedBalance)
+				assert.EqualValues(t, 0, resp.BlocksToUnlock)
+			})
+
+			it("splits balances across subaddresses", func() {
+				addr, err := client.CreateAddress(ctx, 0, 2, "shop")
+				require.NoError(t, err)
+				assert.Equal(t, []uint{1, 2}, addr.AddressIndices)
+
+				_, err = server.Deposit(0, 1, 100)
+				require.NoError(t, err)
+				_, err = server.Deposit(0, 2, 200)
+				require.NoError(t, err)
+
+				server.MineBlocks(wallettest.SpendableAge)
+
+				resp, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{
+					AddressIndices: []uint{2},
+				})
+				require.NoError(t, err)
+				assert.EqualValues(t, 300, resp.Balance)
+				require.Len(t, resp.PerSubaddress, 1)
+				assert.EqualValues(t, 200, resp.PerSubaddress[0].UnlockedBalance)
+				assert.Equal(t, addr.Addresses[1], resp.PerSubaddress[0].Address)
+
+				addresses, err := client.GetAddress(ctx, wallet.GetAddressRequestParameters{})
+				require.NoError(t, err)
+				require.Len(t, addresses.Addresses, 3)
+				assert.False(t, addresses.Addresses[0].Used)
+				assert.True(t, addresses.Addresses[1].Used)
+				assert.Equal(t, "shop", addresses.Addresses[2].Label)
+			})
+		})
+
+		when("accounts", func() {
+			it("totals balances per account", func() {
+				idx := server.CreateAccount("savings")
+				require.NoError(t, server.TagAccount(idx, "cold"))
+
+				_, err := server.Deposit(0, 0, 10)
+				require.NoError(t, err)
+				_, err = server.Deposit(idx, 0, 20)
+				require.NoError(t, err)
+
+				server.MineBlocks(1)
+
+				resp, err := client.GetAccounts(ctx, wallet.GetAccountsRequestParameters{})
+				require.NoError(t, err)
+				require.Len(t, resp.SubaddressAccounts, 2)
+				assert.EqualValues(t, 30, resp.TotalBalance)
+				assert.EqualValues(t, 0, resp.TotalUnlockedBalance)
+
+				resp, err = client.GetAccounts(ctx, wallet.GetAccountsRequestParameters{
+					Tag: "cold",
+				})
+				require.NoError(t, err)
+				require.Len(t, resp.SubaddressAccounts, 1)
+				assert.Equal(t, "savings", resp.SubaddressAccounts[0].Label)
+				assert.EqualValues(t, 20, resp.SubaddressAccounts[0].Balance)
+			})
+
+			it("errors w/ unknown accounts", func() {
+				_, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{
+					AccountIndex: 1,
+				})
+
+				var rpcErr *rpc.Error
+				require.True(t, errors.As(err, &rpcErr))
+				assert.Equal(t, -14, rpcErr.Code)
+			})
+		})
+
+		when("refreshing", func() {
+			it("only sees new blocks once refreshed", func() {
+				_, err := client.AutoRefresh(ctx, false, 0)
+				require.NoError(t, err)
+
+				_, err = server.Deposit(0, 0, 1000)
+				require.NoError(t, err)
+
+				server.MineBlocks(5)
+
+				height, err := client.GetHeight(ctx)
+				require.NoError(t, err)
+				assert.EqualValues(t, 100, height.Height)
+
+				resp, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 0, resp.Balance)
+
+				refresh, err := client.Refresh(ctx, 0)
+				require.NoError(t, err)
+				assert.EqualValues(t, 5, refresh.BlocksFetched)
+				assert.True(t, refresh.ReceivedMoney)
+
+				resp, err = client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 1000, resp.Balance)
+				assert.EqualValues(t, 105, server.WalletHeight())
+			})
+
+			it("misses transfers in blocks skipped over", func() {
+				_, err := client.AutoRefresh(ctx, false, 0)
+				require.NoError(t, err)
+
+				_, err = server.Deposit(0, 0, 1000)
+				require.NoError(t, err)
+
+				server.MineBlocks(5)
+
+				refresh, err := client.Refresh(ctx, 102)
+				require.NoError(t, err)
+				assert.EqualValues(t, 3, refresh.BlocksFetched)
+				assert.False(t, refresh.ReceivedMoney)
+
+				resp, err := client.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 0, resp.Balance)
+			})
+
+			it("ignores transfers below the restore height", func() {
+				restored := wallettest.NewServer(
+					wallettest.WithChainHeight(10),
+					wallettest.WithRestoreHeight(11),
+				)
+				defer restored.Close()
+
+				_, err := restored.Deposit(0, 0, 1000)
+				require.NoError(t, err)
+
+				restored.MineBlocks(2)
+				assert.Len(t, restored.Transfers(), 1)
+
+				c, err := restored.NewClient()
+				require.NoError(t, err)
+
+				resp, err := c.GetBalance(ctx, wallet.GetBalanceRequestParameters{})
+				require.NoError(t, err)
+				assert.EqualValues(t, 0, resp.Balance)
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,336 @@
+package wallettest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jjsteel/go-monero/pkg/monero"
+)
+
+const (
+	// SpendableAge is the number of blocks after which received outputs
+	// become spendable (unlocked).
+	//
+	SpendableAge = 10
+
+	// DefaultAutoRefreshPeriod is the period (in seconds) reported for the
+	// automatic refresh, enabled by default just like in monero-wallet-rpc.
+	//
+	DefaultAutoRefreshPeriod = 20
+
+	blockTime = 120
+)
+
+// Transfer is an incoming transfer to one of the wallet's subaddresses.
+//
+type Transfer struct {
+	TxID         string
+	AccountIndex uint
+	AddressIndex uint
+	Amount       uint64
+
+	// Height is the height of the block in which the transfer has been
+	// mined, or 0 if it's still in the pool.
+	//
+	Height uint64
+
+	// skipped indicates that the wallet skipped over the block in
+	// which the transfer was mined, thus never seeing it.
+	//
+	skipped bool
+}
+
+type subaddress struct {
+	address string
+	label   string
+}
+
+type account struct {
+	label        string
+	tag          string
+	subaddresses []*subaddress
+}
+
+// walletState is the in-memory state of the fake wallet. It's not safe for
+// concurrent use: the server serializes access to it.
+//
+type walletState struct {
+	network monero.Network
+	seed    *monero.Seed
+
+	accounts  []*account
+	transfers []*Transfer
+
+	chainHeight   uint64
+	walletHeight  uint64
+	restoreHeight uint64
+
+	autoRefresh       bool
+	autoRefreshPeriod int64
+}
+
+func newWalletState(opts serverOptions) *walletState {
+	w := &walletState{
+		network:           opts.network,
+		seed:              monero.NewSeed(opts.privateKey, monero.WithNetwork(opts.network)),
+		chainHeight:       opts.chainHeight,
+		walletHeight:      opts.chainHeight,
+		restoreHeight:     opts.restoreHeight,
+		autoRefresh:       true,
+		autoRefreshPeriod: DefaultAutoRefreshPeriod,
+	}
+
+	w.createAccount("Primary account")
+
+	return w
+}
+
+func (w *walletState) createAccount(label string) uint {
+	idx := uint(len(w.accounts))
+
+	w.accounts = append(w.accounts, &account{label: label})
+	w.createAddress(idx, label)
+
+	return idx
+}
+
+func (w *walletState) createAddress(accountIndex uint, label string) uint {
+	acc := w.accounts[accountIndex]
+	idx := uint(len(acc.subaddresses))
+
+	acc.subaddresses = append(acc.subaddresses, &subaddress{
+		address: w.seed.Subaddress(uint32(accountIndex), uint32(idx)).String(),
+		label:   label,
+	})
+
+	return idx
+}
+
+func (w *walletState) account(idx uint) (*account, error) {
+	if idx >= uint(len(w.accounts)) {
+		return nil, errAccountIndexOutOfBounds
+	}
+
+	return w.accounts[idx], nil
+}
+
+// seen tells whether the wallet has already scanned the block in which the
+// transfer was mined.
+//
+func (w *walletState) seen(t *Transfer) bool {
+	return t.Height != 0 && !t.skipped &&
+		t.Height >= w.restoreHeight &&
+		t.Height < w.walletHeight
+}
+
+func (w *walletState) unlocked(t *Transfer) bool {
+	return w.seen(t) && t.Height+SpendableAge <= w.walletHeight
+}
+
+// balance sums up the balance of the transfers that satisfy `filter`, also
+// giving how many blocks until all of them are unlocked.
+//
+func (w *walletState) balance(filter func(t *Transfer) bool) (
+	balance, unlocked uint64, blocksToUnlock uint, outputs uint,
+) {
+	for _, t := range w.transfers {
+		if !w.seen(t) || !filter(t) {
+			continue
+		}
+
+		balance += t.Amount
+		outputs++
+
+		if w.unlocked(t) {
+			unlocked += t.Amount
+			continue
+		}
+
+		if left := uint(t.Height + SpendableAge - w.walletHeight); left > blocksToUnlock {
+			blocksToUnlock = left
+		}
+	}
+
+	return
+}
+
+func (w *walletState) used(accountIndex, addressIndex uint) bool {
+	for _, t := range w.transfers {
+		if w.seen(t) && t.AccountIndex == accountIndex && t.AddressIndex == addressIndex {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refresh scans the chain up to its tip, giving the number of blocks fetched
+// and whether any money has been received.
+//
+func (w *walletState) refresh() (uint64, bool) {
+	from := w.walletHeight
+
+	received := false
+	for _, t := range w.transfers {
+		if t.Height != 0 && !t.skipped && t.Height >= from &&
+			t.Height >= w.restoreHeight && t.Height < w.chainHeight {
+			received = true
+		}
+	}
+
+	w.walletHeight = w.chainHeight
+
+	return w.chainHeight - from, received
+}
+
+// skip moves the wallet height to `height` (at most up to the chain's tip)
+// without scanning the blocks in between.
+//
+func (w *walletState) skip(height uint64) {
+	if height > w.chainHeight {
+		height = w.chainHeight
+	}
+
+	for _, t := range w.transfers {
+		if t.Height != 0 && t.Height >= w.walletHeight && t.Height < height {
+			t.skipped = true
+		}
+	}
+
+	w.walletHeight = height
+}
+
+// CreateAccount adds a new account to the wallet (along with its base
+// subaddress), returning its index.
+//
+func (s *Server) CreateAccount(label string) uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.wallet.createAccount(label)
+}
+
+// TagAccount tags the account at index `accountIndex`.
+//
+func (s *Server) TagAccount(accountIndex uint, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.wallet.account(accountIndex)
+	if err != nil {
+		return err
+	}
+
+	acc.tag = tag
+
+	return nil
+}
+
+// Address gives the address of the subaddress at the indices supplied.
+//
+func (s *Server) Address(accountIndex, addressIndex uint) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.wallet.account(accountIndex)
+	if err != nil {
+		return "", err
+	}
+
+	if addressIndex >= uint(len(acc.subaddresses)) {
+		return "", errAddressIndexOutOfBounds
+	}
+
+	return acc.subaddresses[addressIndex].address, nil
+}
+
+// Deposit submits an incoming transfer of `amount` atomic units to the
+// subaddress at the indices supplied, to be mined in the next block (see
+// `MineBlocks`), returning the hash of the transaction.
+//
+func (s *Server) Deposit(accountIndex, addressIndex uint, amount uint64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc, err := s.wallet.account(accountIndex)
+	if err != nil {
+		return "", err
+	}
+
+	if addressIndex >= uint(len(acc.subaddresses)) {
+		return "", errAddressIndexOutOfBounds
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d:%d",
+		len(s.wallet.transfers), accountIndex, addressIndex, amount)))
+	txid := hex.EncodeToString(sum[:])
+
+	s.wallet.transfers = append(s.wallet.transfers, &Transfer{
+		TxID:         txid,
+		AccountIndex: accountIndex,
+		AddressIndex: addressIndex,
+		Amount:       amount,
+	})
+
+	return txid, nil
+}
+
+// MineBlocks advances the chain by `n` blocks, the first of which includes
+// all of the pending deposits.
+//
+// With automatic refresh enabled (the default), the wallet immediately scans
+// the new blocks. Otherwise, it only does so once refreshed.
+//
+func (s *Server) MineBlocks(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n == 0 {
+		return
+	}
+
+	for _, t := range s.wallet.transfers {
+		if t.Height == 0 {
+			t.Height = s.wallet.chainHeight
+		}
+	}
+
+	s.wallet.chainHeight += n
+
+	if s.wallet.autoRefresh {
+		s.wallet.refresh()
+	}
+}
+
+// Transfers gives a copy of all of the transfers made to the wallet so far.
+//
+func (s *Server) Transfers() []Transfer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Transfer, len(s.wallet.transfers))
+	for idx, t := range s.wallet.transfers {
+		res[idx] = *t
+	}
+
+	return res
+}
+
+// ChainHeight gives the height of the chain.
+//
+func (s *Server) ChainHeight() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.wallet.chainHeight
+}
+
+// WalletHeight gives the height up to which the wallet has scanned the chain.
+//
+func (s *Server) WalletHeight() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.wallet.walletHeight
+}